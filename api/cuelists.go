@@ -0,0 +1,384 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+	"github.com/danesparza/fxdmx/internal/dmx"
+	"github.com/danesparza/fxdmx/internal/event"
+	"github.com/gorilla/mux"
+)
+
+// ListAllCueLists godoc
+// @Summary List all cue lists in the system
+// @Description List all cue lists in the system
+// @Tags cuelists
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} api.SystemResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /cuelists [get]
+func (service Service) ListAllCueLists(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get the cue lists
+	retval, err := service.DB.GetAllCueLists()
+	if err != nil {
+		err = fmt.Errorf("error getting a list of cue lists: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: fmt.Sprintf("%v cue list(s)", len(retval)),
+		Data:    retval,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// GetCueList godoc
+// @Summary Gets a cue list
+// @Description Gets a cue list
+// @Tags cuelists
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The cue list id to get"
+// @Success 200 {object} api.SystemResponse
+// @Failure 404 {object} api.ErrorResponse
+// @Router /cuelists/{id} [get]
+func (service Service) GetCueList(rw http.ResponseWriter, req *http.Request) {
+
+	//	Parse the request
+	vars := mux.Vars(req)
+
+	//	Get the cue list
+	cueList, err := service.DB.GetCueList(vars["id"])
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusNotFound)
+		return
+	}
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "Cue list fetched",
+		Data:    cueList,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// CreateCueList godoc
+// @Summary Create a new cue list
+// @Description Create a new cue list.  Cues are run one at a time with GO, BACK and GOTO
+// @Tags cuelists
+// @Accept  json
+// @Produce  json
+// @Param cuelist body api.CreateCueListRequest true "The cue list to create"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Router /cuelists [post]
+func (service Service) CreateCueList(rw http.ResponseWriter, req *http.Request) {
+
+	//	req.Body is a ReadCloser -- we need to remember to close it:
+	defer req.Body.Close()
+
+	//	Decode the request
+	request := CreateCueListRequest{}
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Make sure the cues are valid before we save them
+	if problems := dmx.ValidateCueList(request.Cues); problems != nil {
+		sendErrorResponse(rw, problems, http.StatusBadRequest)
+		return
+	}
+
+	//	Create the new cue list:
+	newCueList, err := service.DB.AddCueList(request.Name, request.USBDevicePath, request.Cues)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Record the event:
+	service.DB.AddEvent(event.CueListCreated, fmt.Sprintf("Cue list ID: %s / %s (%v cues)", newCueList.ID, newCueList.Name, len(newCueList.Cues)), GetIP(req), service.HistoryTTL)
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "Cue list created",
+		Data:    newCueList,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// UpdateCueList godoc
+// @Summary Update a cue list
+// @Description Update a cue list.  A running cue list picks up the changes on its next GO, BACK or GOTO (a new devpath needs a release first)
+// @Tags cuelists
+// @Accept  json
+// @Produce  json
+// @Param cuelist body api.UpdateCueListRequest true "The cue list to update.  Must include cuelist.id"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Router /cuelists [put]
+func (service Service) UpdateCueList(rw http.ResponseWriter, req *http.Request) {
+
+	//	req.Body is a ReadCloser -- we need to remember to close it:
+	defer req.Body.Close()
+
+	//	Decode the request
+	request := UpdateCueListRequest{}
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	If we don't have the cuelist.id, make sure we indicate that's not valid
+	if strings.TrimSpace(request.ID) == "" {
+		sendErrorResponse(rw, fmt.Errorf("the cuelist.id is required"), http.StatusBadRequest)
+		return
+	}
+
+	//	Make sure the id exists
+	cueListUpdate, _ := service.DB.GetCueList(request.ID)
+	if cueListUpdate.ID != request.ID {
+		sendErrorResponse(rw, fmt.Errorf("cue list must already exist"), http.StatusBadRequest)
+		return
+	}
+
+	//	Only update the fields that have been passed
+	if strings.TrimSpace(request.Name) != "" {
+		cueListUpdate.Name = request.Name
+	}
+
+	if strings.TrimSpace(request.USBDevicePath) != "" {
+		cueListUpdate.USBDevicePath = request.USBDevicePath
+	}
+
+	if len(request.Cues) > 0 {
+		cueListUpdate.Cues = request.Cues
+	}
+
+	//	Make sure the cues are valid before we save them
+	if problems := dmx.ValidateCueList(cueListUpdate.Cues); problems != nil {
+		sendErrorResponse(rw, problems, http.StatusBadRequest)
+		return
+	}
+
+	//	Update the cue list:
+	updatedCueList, err := service.DB.UpdateCueList(cueListUpdate)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Record the event:
+	service.DB.AddEvent(event.CueListUpdated, fmt.Sprintf("Cue list ID: %s / %s (%v cues)", updatedCueList.ID, updatedCueList.Name, len(updatedCueList.Cues)), GetIP(req), service.HistoryTTL)
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "Cue list updated",
+		Data:    updatedCueList,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// DeleteCueList godoc
+// @Summary Deletes a cue list
+// @Description Deletes a cue list (and releases it if it is running)
+// @Tags cuelists
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The cue list id to delete"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /cuelists/{id} [delete]
+func (service Service) DeleteCueList(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get the id from the url (if it's blank, return an error)
+	vars := mux.Vars(req)
+	if vars["id"] == "" {
+		err := fmt.Errorf("requires an id of a cue list to delete")
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Release it (if it's running), then delete the cue list
+	service.RunCue <- dmx.CueCommand{Command: dmx.CueRelease, CueList: data2.CueList{ID: vars["id"]}}
+
+	err := service.DB.DeleteCueList(vars["id"])
+	if err != nil {
+		err = fmt.Errorf("error deleting cue list: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Record the event:
+	service.DB.AddEvent(event.CueListDeleted, vars["id"], GetIP(req), service.HistoryTTL)
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: "Cue list deleted",
+		Data:    vars["id"],
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// CueListGo godoc
+// @Summary Runs the next cue in a cue list
+// @Description Runs the next cue in a cue list (the first cue if the cue list isn't running yet)
+// @Tags cuelists
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The cue list id"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Router /cuelists/{id}/go [post]
+func (service Service) CueListGo(rw http.ResponseWriter, req *http.Request) {
+	service.sendCueCommand(rw, req, dmx.CueCommand{Command: dmx.CueGo})
+}
+
+// CueListBack godoc
+// @Summary Runs the cue before the current one in a cue list
+// @Description Runs the cue before the current one in a cue list (using that cue's fade times)
+// @Tags cuelists
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The cue list id"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Router /cuelists/{id}/back [post]
+func (service Service) CueListBack(rw http.ResponseWriter, req *http.Request) {
+	service.sendCueCommand(rw, req, dmx.CueCommand{Command: dmx.CueBack})
+}
+
+// CueListGoto godoc
+// @Summary Runs a cue in a cue list by its number
+// @Description Runs a cue in a cue list by its number (using that cue's fade times)
+// @Tags cuelists
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The cue list id"
+// @Param cue path number true "The cue number to run"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Router /cuelists/{id}/goto/{cue} [post]
+func (service Service) CueListGoto(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get the cue number from the url
+	vars := mux.Vars(req)
+	cue, err := strconv.ParseFloat(vars["cue"], 64)
+	if err != nil {
+		sendErrorResponse(rw, fmt.Errorf("cue must be a cue number (like 2 or 2.5)"), http.StatusBadRequest)
+		return
+	}
+
+	service.sendCueCommand(rw, req, dmx.CueCommand{Command: dmx.CueGoto, Cue: cue})
+}
+
+// CueListRelease godoc
+// @Summary Stops running a cue list
+// @Description Stops running a cue list.  The output holds the last look
+// @Tags cuelists
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The cue list id"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Router /cuelists/{id}/release [post]
+func (service Service) CueListRelease(rw http.ResponseWriter, req *http.Request) {
+	service.sendCueCommand(rw, req, dmx.CueCommand{Command: dmx.CueRelease})
+}
+
+// GetCueListStatus godoc
+// @Summary Gets where a running cue list is up to
+// @Description Gets where a running cue list is up to (the current cue, the next cue and whether the current cue is still fading)
+// @Tags cuelists
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The cue list id"
+// @Success 200 {object} api.SystemResponse
+// @Failure 404 {object} api.ErrorResponse
+// @Router /cuelists/{id}/status [get]
+func (service Service) GetCueListStatus(rw http.ResponseWriter, req *http.Request) {
+
+	//	Parse the request
+	vars := mux.Vars(req)
+
+	//	Get the status
+	status, running := service.CueLists.Status(vars["id"])
+	if !running {
+		sendErrorResponse(rw, fmt.Errorf("cue list '%s' isn't running", vars["id"]), http.StatusNotFound)
+		return
+	}
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "Cue list status",
+		Data:    status,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// sendCueCommand sends a command to the cue list in the url
+func (service Service) sendCueCommand(rw http.ResponseWriter, req *http.Request, command dmx.CueCommand) {
+
+	//	Get the cue list
+	vars := mux.Vars(req)
+	cueList, err := service.DB.GetCueList(vars["id"])
+	if err != nil || cueList.ID != vars["id"] {
+		sendErrorResponse(rw, fmt.Errorf("cue list must already exist"), http.StatusBadRequest)
+		return
+	}
+
+	//	Make sure the cue exists
+	if command.Command == dmx.CueGoto {
+		if _, found := cueList.Cue(command.Cue); !found {
+			sendErrorResponse(rw, fmt.Errorf("cue list '%s' doesn't have a cue %v", cueList.Name, command.Cue), http.StatusBadRequest)
+			return
+		}
+	}
+
+	//	Send to the channel:
+	command.CueList = cueList
+	service.RunCue <- command
+
+	//	Record the event:
+	service.DB.AddEvent(event.CueListCommand, fmt.Sprintf("Cue list ID: %s / %s: %s %v", cueList.ID, cueList.Name, strings.ToUpper(command.Command), command.Cue), GetIP(req), service.HistoryTTL)
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: fmt.Sprintf("Cue list %s", strings.ToUpper(command.Command)),
+		Data:    command,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
@@ -3,10 +3,14 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/danesparza/fxdmx/internal/dmx"
 	"github.com/danesparza/fxdmx/internal/event"
 	"github.com/danesparza/fxdmx/internal/system"
 	"net/http"
+	"path"
 	"strings"
+
+	"github.com/gorilla/mux"
 )
 
 // GetSerialUSBDevices godoc
@@ -123,3 +127,186 @@ func (service Service) GetDefaultUSBDev(rw http.ResponseWriter, req *http.Reques
 	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
 	json.NewEncoder(rw).Encode(response)
 }
+
+// GetRDMDevices godoc
+// @Summary Discovers RDM devices connected to a USB device
+// @Description Discovers RDM devices connected to an RDM capable USB device and reads their device information (start address, personality, footprint)
+// @Tags devices
+// @Accept  json
+// @Produce  json
+// @Param name path string true "The USB device name (ttyUSB0) or 'default' for the default USB device"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /devices/{name}/rdm [get]
+func (service Service) GetRDMDevices(rw http.ResponseWriter, req *http.Request) {
+
+	//	Connect to the USB device
+	controller, err := service.openRDM(mux.Vars(req)["name"])
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+	defer controller.Close()
+
+	//	Discover everything on the line:
+	devices, err := controller.DiscoverDevices()
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: fmt.Sprintf("%v RDM devices found", len(devices)),
+		Data:    devices,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// GetRDMDevice godoc
+// @Summary Gets information about a single RDM device
+// @Description Gets information about a single RDM device
+// @Tags devices
+// @Accept  json
+// @Produce  json
+// @Param name path string true "The USB device name (ttyUSB0) or 'default' for the default USB device"
+// @Param uid path string true "The RDM device UID (MMMM:DDDDDDDD)"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /devices/{name}/rdm/{uid} [get]
+func (service Service) GetRDMDevice(rw http.ResponseWriter, req *http.Request) {
+
+	//	Parse the request
+	vars := mux.Vars(req)
+	uid, err := dmx.ParseUID(vars["uid"])
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Connect to the USB device
+	controller, err := service.openRDM(vars["name"])
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+	defer controller.Close()
+
+	//	Get the device information:
+	device, err := controller.DeviceInfo(uid)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "RDM device information",
+		Data:    device,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// UpdateRDMStartAddress godoc
+// @Summary Sets the DMX start address of an RDM device
+// @Description Sets the DMX start address of an RDM device
+// @Tags devices
+// @Accept  json
+// @Produce  json
+// @Param name path string true "The USB device name (ttyUSB0) or 'default' for the default USB device"
+// @Param uid path string true "The RDM device UID (MMMM:DDDDDDDD)"
+// @Param address body api.UpdateRDMAddressRequest true "The new start address"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /devices/{name}/rdm/{uid}/address [put]
+func (service Service) UpdateRDMStartAddress(rw http.ResponseWriter, req *http.Request) {
+
+	//	req.Body is a ReadCloser -- we need to remember to close it:
+	defer req.Body.Close()
+
+	//	Parse the request
+	vars := mux.Vars(req)
+	uid, err := dmx.ParseUID(vars["uid"])
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	request := UpdateRDMAddressRequest{}
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	if request.StartAddress < 1 || request.StartAddress > 512 {
+		sendErrorResponse(rw, fmt.Errorf("the start address must be between 1 and 512"), http.StatusBadRequest)
+		return
+	}
+
+	//	Connect to the USB device
+	controller, err := service.openRDM(vars["name"])
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+	defer controller.Close()
+
+	//	Set the address, then read the device back so we can return it
+	if err := controller.SetStartAddress(uid, request.StartAddress); err != nil {
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	device, err := controller.DeviceInfo(uid)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Record the event:
+	service.DB.AddEvent(event.RDMAddressUpdated, fmt.Sprintf("Device: %s / UID: %s / %+v", vars["name"], uid, request), GetIP(req), service.HistoryTTL)
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "RDM start address updated",
+		Data:    device,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// openRDM connects to the named USB device ('default' uses the default USB device)
+func (service Service) openRDM(name string) (*dmx.RDMController, error) {
+	devicePath := ""
+
+	switch {
+	case name == "default":
+		defaultDevice, err := service.DB.GetDefaultUSBDev()
+		if err != nil {
+			return nil, fmt.Errorf("an error occurred trying to get the default USB device: %v", err)
+		}
+		devicePath = defaultDevice
+	case strings.TrimSpace(name) == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, "."):
+		return nil, fmt.Errorf("device name '%s' is invalid -- it looks something like ttyUSB0", name)
+	default:
+		devicePath = path.Join("/dev", name)
+	}
+
+	transport, err := dmx.OpenEnttecRDM(devicePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return dmx.NewRDMController(transport), nil
+}
@@ -0,0 +1,239 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/danesparza/fxdmx/internal/event"
+	"github.com/gorilla/mux"
+)
+
+// ListAllFixtures godoc
+// @Summary List all patched fixtures in the system
+// @Description List all patched fixtures in the system
+// @Tags fixtures
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} api.SystemResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /fixtures [get]
+func (service Service) ListAllFixtures(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get the patch
+	retval, err := service.DB.GetAllFixtures()
+	if err != nil {
+		err = fmt.Errorf("error getting a list of fixtures: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: fmt.Sprintf("%v fixture(s)", len(retval)),
+		Data:    retval,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// GetFixture godoc
+// @Summary Gets a patched fixture
+// @Description Gets a patched fixture
+// @Tags fixtures
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The fixture id to get"
+// @Success 200 {object} api.SystemResponse
+// @Failure 404 {object} api.ErrorResponse
+// @Router /fixtures/{id} [get]
+func (service Service) GetFixture(rw http.ResponseWriter, req *http.Request) {
+
+	//	Parse the request
+	vars := mux.Vars(req)
+
+	//	Get the fixture
+	fixture, err := service.DB.GetFixture(vars["id"])
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusNotFound)
+		return
+	}
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "Fixture fetched",
+		Data:    fixture,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// CreateFixture godoc
+// @Summary Patch a new fixture
+// @Description Patch a new fixture to a universe and start address.  The fixture must fit in channels 1-512 and can't overlap another patched fixture.  Only universe 1 is supported
+// @Tags fixtures
+// @Accept  json
+// @Produce  json
+// @Param fixture body api.CreateFixtureRequest true "The fixture to patch"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Router /fixtures [post]
+func (service Service) CreateFixture(rw http.ResponseWriter, req *http.Request) {
+
+	//	req.Body is a ReadCloser -- we need to remember to close it:
+	defer req.Body.Close()
+
+	//	Decode the request
+	request := CreateFixtureRequest{}
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Patch the new fixture:
+	newFixture, err := service.DB.AddFixture(request.Name, request.ProfileID, request.Mode, request.Universe, request.Address)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Record the event:
+	service.DB.AddEvent(event.FixtureCreated, fmt.Sprintf("Fixture ID: %s / %s @ %v.%v", newFixture.ID, newFixture.Name, newFixture.Universe, newFixture.Address), GetIP(req), service.HistoryTTL)
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "Fixture created",
+		Data:    newFixture,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// UpdateFixture godoc
+// @Summary Update (or re-patch) a fixture
+// @Description Update (or re-patch) a fixture
+// @Tags fixtures
+// @Accept  json
+// @Produce  json
+// @Param fixture body api.UpdateFixtureRequest true "The fixture to update.  Must include fixture.id"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Router /fixtures [put]
+func (service Service) UpdateFixture(rw http.ResponseWriter, req *http.Request) {
+
+	//	req.Body is a ReadCloser -- we need to remember to close it:
+	defer req.Body.Close()
+
+	//	Decode the request
+	request := UpdateFixtureRequest{}
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	If we don't have the fixture.id, make sure we indicate that's not valid
+	if strings.TrimSpace(request.ID) == "" {
+		sendErrorResponse(rw, fmt.Errorf("the fixture.id is required"), http.StatusBadRequest)
+		return
+	}
+
+	//	Make sure the id exists
+	fixtureUpdate, _ := service.DB.GetFixture(request.ID)
+	if fixtureUpdate.ID != request.ID {
+		sendErrorResponse(rw, fmt.Errorf("fixture must already exist"), http.StatusBadRequest)
+		return
+	}
+
+	//	Only update the fields that have been passed
+	if strings.TrimSpace(request.Name) != "" {
+		fixtureUpdate.Name = request.Name
+	}
+
+	if strings.TrimSpace(request.ProfileID) != "" {
+		fixtureUpdate.ProfileID = request.ProfileID
+	}
+
+	if strings.TrimSpace(request.Mode) != "" {
+		fixtureUpdate.Mode = request.Mode
+	}
+
+	if request.Universe != 0 {
+		fixtureUpdate.Universe = request.Universe
+	}
+
+	if request.Address != 0 {
+		fixtureUpdate.Address = request.Address
+	}
+
+	//	Update the fixture:
+	updatedFixture, err := service.DB.UpdateFixture(fixtureUpdate)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Record the event:
+	service.DB.AddEvent(event.FixtureUpdated, fmt.Sprintf("Fixture ID: %s / %s @ %v.%v", updatedFixture.ID, updatedFixture.Name, updatedFixture.Universe, updatedFixture.Address), GetIP(req), service.HistoryTTL)
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "Fixture updated",
+		Data:    updatedFixture,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// DeleteFixture godoc
+// @Summary Removes a fixture from the patch
+// @Description Removes a fixture from the patch
+// @Tags fixtures
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The fixture id to delete"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /fixtures/{id} [delete]
+func (service Service) DeleteFixture(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get the id from the url (if it's blank, return an error)
+	vars := mux.Vars(req)
+	if vars["id"] == "" {
+		err := fmt.Errorf("requires an id of a fixture to delete")
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Delete the fixture
+	err := service.DB.DeleteFixture(vars["id"])
+	if err != nil {
+		err = fmt.Errorf("error deleting fixture: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Record the event:
+	service.DB.AddEvent(event.FixtureDeleted, vars["id"], GetIP(req), service.HistoryTTL)
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: "Fixture deleted",
+		Data:    vars["id"],
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
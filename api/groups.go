@@ -0,0 +1,227 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/danesparza/fxdmx/internal/event"
+	"github.com/gorilla/mux"
+)
+
+// ListAllFixtureGroups godoc
+// @Summary List all fixture groups in the system
+// @Description List all fixture groups in the system
+// @Tags groups
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} api.SystemResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /groups [get]
+func (service Service) ListAllFixtureGroups(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get the groups
+	retval, err := service.DB.GetAllFixtureGroups()
+	if err != nil {
+		err = fmt.Errorf("error getting a list of fixture groups: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: fmt.Sprintf("%v group(s)", len(retval)),
+		Data:    retval,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// GetFixtureGroup godoc
+// @Summary Gets a fixture group
+// @Description Gets a fixture group
+// @Tags groups
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The group id to get"
+// @Success 200 {object} api.SystemResponse
+// @Failure 404 {object} api.ErrorResponse
+// @Router /groups/{id} [get]
+func (service Service) GetFixtureGroup(rw http.ResponseWriter, req *http.Request) {
+
+	//	Parse the request
+	vars := mux.Vars(req)
+
+	//	Get the group
+	group, err := service.DB.GetFixtureGroup(vars["id"])
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusNotFound)
+		return
+	}
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "Group fetched",
+		Data:    group,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// CreateFixtureGroup godoc
+// @Summary Create a new fixture group
+// @Description Create a new named group of patched fixtures (like 'wash' or 'uplights').  The order of the fixtures is the order attributes are spread across the group
+// @Tags groups
+// @Accept  json
+// @Produce  json
+// @Param group body api.CreateFixtureGroupRequest true "The group to create"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Router /groups [post]
+func (service Service) CreateFixtureGroup(rw http.ResponseWriter, req *http.Request) {
+
+	//	req.Body is a ReadCloser -- we need to remember to close it:
+	defer req.Body.Close()
+
+	//	Decode the request
+	request := CreateFixtureGroupRequest{}
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Create the new group:
+	newGroup, err := service.DB.AddFixtureGroup(request.Name, request.Fixtures)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Record the event:
+	service.DB.AddEvent(event.GroupCreated, fmt.Sprintf("Group ID: %s / %s (%v fixtures)", newGroup.ID, newGroup.Name, len(newGroup.Fixtures)), GetIP(req), service.HistoryTTL)
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "Group created",
+		Data:    newGroup,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// UpdateFixtureGroup godoc
+// @Summary Update a fixture group
+// @Description Update a fixture group
+// @Tags groups
+// @Accept  json
+// @Produce  json
+// @Param group body api.UpdateFixtureGroupRequest true "The group to update.  Must include group.id"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Router /groups [put]
+func (service Service) UpdateFixtureGroup(rw http.ResponseWriter, req *http.Request) {
+
+	//	req.Body is a ReadCloser -- we need to remember to close it:
+	defer req.Body.Close()
+
+	//	Decode the request
+	request := UpdateFixtureGroupRequest{}
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	If we don't have the group.id, make sure we indicate that's not valid
+	if strings.TrimSpace(request.ID) == "" {
+		sendErrorResponse(rw, fmt.Errorf("the group.id is required"), http.StatusBadRequest)
+		return
+	}
+
+	//	Make sure the id exists
+	groupUpdate, _ := service.DB.GetFixtureGroup(request.ID)
+	if groupUpdate.ID != request.ID {
+		sendErrorResponse(rw, fmt.Errorf("group must already exist"), http.StatusBadRequest)
+		return
+	}
+
+	//	Only update the fields that have been passed
+	if strings.TrimSpace(request.Name) != "" {
+		groupUpdate.Name = request.Name
+	}
+
+	if len(request.Fixtures) > 0 {
+		groupUpdate.Fixtures = request.Fixtures
+	}
+
+	//	Update the group:
+	updatedGroup, err := service.DB.UpdateFixtureGroup(groupUpdate)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Record the event:
+	service.DB.AddEvent(event.GroupUpdated, fmt.Sprintf("Group ID: %s / %s (%v fixtures)", updatedGroup.ID, updatedGroup.Name, len(updatedGroup.Fixtures)), GetIP(req), service.HistoryTTL)
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "Group updated",
+		Data:    updatedGroup,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// DeleteFixtureGroup godoc
+// @Summary Deletes a fixture group
+// @Description Deletes a fixture group (the fixtures in it stay patched)
+// @Tags groups
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The group id to delete"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /groups/{id} [delete]
+func (service Service) DeleteFixtureGroup(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get the id from the url (if it's blank, return an error)
+	vars := mux.Vars(req)
+	if vars["id"] == "" {
+		err := fmt.Errorf("requires an id of a group to delete")
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Delete the group
+	err := service.DB.DeleteFixtureGroup(vars["id"])
+	if err != nil {
+		err = fmt.Errorf("error deleting group: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Record the event:
+	service.DB.AddEvent(event.GroupDeleted, vars["id"], GetIP(req), service.HistoryTTL)
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: "Group deleted",
+		Data:    vars["id"],
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
@@ -0,0 +1,282 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+	"github.com/danesparza/fxdmx/internal/dmx"
+	"github.com/danesparza/fxdmx/internal/event"
+)
+
+// GetInput godoc
+// @Summary Gets the most recently received DMX input universe
+// @Description Gets the most recently received DMX input universe
+// @Tags input
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} api.SystemResponse
+// @Router /input [get]
+func (service Service) GetInput(rw http.ResponseWriter, req *http.Request) {
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "DMX input universe",
+		Data:    service.Input.State(),
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// RequestInputStart godoc
+// @Summary Start receiving DMX input
+// @Description Start receiving DMX input from a widget input port (enttec), Art-Net (artnet) or sACN (sacn).  Replaces any current input source
+// @Tags input
+// @Accept  json
+// @Produce  json
+// @Param input body api.StartInputRequest true "The input source to receive from"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Router /input/start [post]
+func (service Service) RequestInputStart(rw http.ResponseWriter, req *http.Request) {
+
+	//	req.Body is a ReadCloser -- we need to remember to close it:
+	defer req.Body.Close()
+
+	//	Decode the request
+	request := StartInputRequest{}
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Make sure we know about the source
+	source := strings.ToLower(strings.TrimSpace(request.Source))
+	switch source {
+	case dmx.InputSourceEnttec, dmx.InputSourceArtNet, dmx.InputSourceSACN:
+	default:
+		sendErrorResponse(rw, fmt.Errorf("source must be one of %s, %s or %s", dmx.InputSourceEnttec, dmx.InputSourceArtNet, dmx.InputSourceSACN), http.StatusBadRequest)
+		return
+	}
+
+	//	Send to the channel:
+	inputRequest := dmx.InputRequest{
+		Source:        source,
+		USBDevicePath: request.USBDevicePath,
+		Universe:      request.Universe,
+		Bind:          request.Bind,
+	}
+	service.StartInput <- inputRequest
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "DMX input starting",
+		Data:    inputRequest,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// RequestInputStop godoc
+// @Summary Stop receiving DMX input
+// @Description Stop receiving DMX input
+// @Tags input
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} api.SystemResponse
+// @Router /input/stop [post]
+func (service Service) RequestInputStop(rw http.ResponseWriter, req *http.Request) {
+
+	//	Send to the channel:
+	service.StopInput <- true
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "DMX input stopping",
+		Data:    ".",
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// StreamInput godoc
+// @Summary Streams DMX input changes
+// @Description Streams DMX input changes as server-sent events.  The first event ('state') is the full universe, each following event ('change') is the set of channels that changed
+// @Tags input
+// @Produce  text/event-stream
+// @Success 200 {object} dmx.InputChange
+// @Failure 500 {object} api.ErrorResponse
+// @Router /input/stream [get]
+func (service Service) StreamInput(rw http.ResponseWriter, req *http.Request) {
+
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		sendErrorResponse(rw, fmt.Errorf("streaming is not supported"), http.StatusInternalServerError)
+		return
+	}
+
+	//	Subscribe before sending the current state, so we don't miss anything in between
+	changes, unsubscribe := service.Input.Subscribe()
+	defer unsubscribe()
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Set("Connection", "keep-alive")
+
+	sendServerEvent(rw, "state", service.Input.State())
+	flusher.Flush()
+
+	//	Send changes until the client goes away
+	for {
+		select {
+		case change := <-changes:
+			sendServerEvent(rw, "change", change)
+			flusher.Flush()
+		case <-req.Context().Done():
+			return
+		}
+	}
+}
+
+// Used to send a single server-sent event:
+func sendServerEvent(rw http.ResponseWriter, name string, data interface{}) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintf(rw, "event: %s\ndata: %s\n\n", name, encoded)
+}
+
+// GetRecordingStatus godoc
+// @Summary Gets the status of the current DMX input recording
+// @Description Gets the status of the current DMX input recording
+// @Tags input
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} api.SystemResponse
+// @Router /input/record [get]
+func (service Service) GetRecordingStatus(rw http.ResponseWriter, req *http.Request) {
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "Recording status",
+		Data:    service.Recorder.Status(),
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// RequestRecordingStart godoc
+// @Summary Start recording DMX input into a new timeline
+// @Description Start recording DMX input into a new timeline.  DMX input must already be started
+// @Tags input
+// @Accept  json
+// @Produce  json
+// @Param recording body api.StartRecordingRequest true "The timeline to record"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 409 {object} api.ErrorResponse
+// @Router /input/record/start [post]
+func (service Service) RequestRecordingStart(rw http.ResponseWriter, req *http.Request) {
+
+	//	req.Body is a ReadCloser -- we need to remember to close it:
+	defer req.Body.Close()
+
+	//	Decode the request
+	request := StartRecordingRequest{}
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	If we don't have a name, make one up
+	if strings.TrimSpace(request.Name) == "" {
+		request.Name = fmt.Sprintf("Recording %s", time.Now().Format(time.RFC3339))
+	}
+
+	//	Start recording:
+	recordRequest := dmx.RecordRequest{
+		Name:          request.Name,
+		CollapseFades: request.CollapseFades,
+	}
+	if err := service.Recorder.Start(recordRequest); err != nil {
+		sendErrorResponse(rw, err, http.StatusConflict)
+		return
+	}
+
+	//	Record the event:
+	service.DB.AddEvent(event.RecordingStarted, fmt.Sprintf("%+v", request), GetIP(req), service.HistoryTTL)
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "Recording started",
+		Data:    recordRequest,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// RequestRecordingStop godoc
+// @Summary Stop recording DMX input and save the new timeline
+// @Description Stop recording DMX input and save the new timeline
+// @Tags input
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /input/record/stop [post]
+func (service Service) RequestRecordingStop(rw http.ResponseWriter, req *http.Request) {
+
+	//	Stop recording and get the recorded frames:
+	recordRequest, frames, err := service.Recorder.Stop()
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Record the event:
+	service.DB.AddEvent(event.RecordingStopped, fmt.Sprintf("%+v", recordRequest), GetIP(req), service.HistoryTTL)
+
+	//	Make sure the recorded timeline is valid before we save it
+	if problems := dmx.ValidateTimeline(data2.Timeline{Frames: frames}); problems != nil {
+		sendErrorResponse(rw, problems, http.StatusBadRequest)
+		return
+	}
+
+	//	Create the new timeline:
+	newTimeline, err := service.DB.AddTimeline(recordRequest.Name, "", frames, nil)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Record the event:
+	service.DB.AddEvent(event.TimelineCreated, fmt.Sprintf("Recorded timeline ID: %s / Name: %s", newTimeline.ID, newTimeline.Name), GetIP(req), service.HistoryTTL)
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "Recording saved",
+		Data:    newTimeline,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
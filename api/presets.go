@@ -0,0 +1,231 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/danesparza/fxdmx/internal/event"
+	"github.com/gorilla/mux"
+)
+
+// ListAllPresets godoc
+// @Summary List all presets in the system
+// @Description List all presets in the system
+// @Tags presets
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} api.SystemResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /presets [get]
+func (service Service) ListAllPresets(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get the presets
+	retval, err := service.DB.GetAllPresets()
+	if err != nil {
+		err = fmt.Errorf("error getting a list of presets: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: fmt.Sprintf("%v preset(s)", len(retval)),
+		Data:    retval,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// GetPreset godoc
+// @Summary Gets a preset
+// @Description Gets a preset
+// @Tags presets
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The preset id to get"
+// @Success 200 {object} api.SystemResponse
+// @Failure 404 {object} api.ErrorResponse
+// @Router /presets/{id} [get]
+func (service Service) GetPreset(rw http.ResponseWriter, req *http.Request) {
+
+	//	Parse the request
+	vars := mux.Vars(req)
+
+	//	Get the preset
+	preset, err := service.DB.GetPreset(vars["id"])
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusNotFound)
+		return
+	}
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "Preset fetched",
+		Data:    preset,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// CreatePreset godoc
+// @Summary Create a new preset
+// @Description Create a new named preset of fixture attributes and/or channel values (like 'warm white' or 'blackout').  Timelines refer to presets by name, so updating a preset changes every timeline that uses it the next time it plays
+// @Tags presets
+// @Accept  json
+// @Produce  json
+// @Param preset body api.CreatePresetRequest true "The preset to create"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Router /presets [post]
+func (service Service) CreatePreset(rw http.ResponseWriter, req *http.Request) {
+
+	//	req.Body is a ReadCloser -- we need to remember to close it:
+	defer req.Body.Close()
+
+	//	Decode the request
+	request := CreatePresetRequest{}
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Create the new preset:
+	newPreset, err := service.DB.AddPreset(request.Name, request.Attributes, request.Channels)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Record the event:
+	service.DB.AddEvent(event.PresetCreated, fmt.Sprintf("Preset ID: %s / %s", newPreset.ID, newPreset.Name), GetIP(req), service.HistoryTTL)
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "Preset created",
+		Data:    newPreset,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// UpdatePreset godoc
+// @Summary Update a preset
+// @Description Update a preset
+// @Tags presets
+// @Accept  json
+// @Produce  json
+// @Param preset body api.UpdatePresetRequest true "The preset to update.  Must include preset.id"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Router /presets [put]
+func (service Service) UpdatePreset(rw http.ResponseWriter, req *http.Request) {
+
+	//	req.Body is a ReadCloser -- we need to remember to close it:
+	defer req.Body.Close()
+
+	//	Decode the request
+	request := UpdatePresetRequest{}
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	If we don't have the preset.id, make sure we indicate that's not valid
+	if strings.TrimSpace(request.ID) == "" {
+		sendErrorResponse(rw, fmt.Errorf("the preset.id is required"), http.StatusBadRequest)
+		return
+	}
+
+	//	Make sure the id exists
+	presetUpdate, _ := service.DB.GetPreset(request.ID)
+	if presetUpdate.ID != request.ID {
+		sendErrorResponse(rw, fmt.Errorf("preset must already exist"), http.StatusBadRequest)
+		return
+	}
+
+	//	Only update the fields that have been passed
+	if strings.TrimSpace(request.Name) != "" {
+		presetUpdate.Name = request.Name
+	}
+
+	if request.Attributes != nil {
+		presetUpdate.Attributes = request.Attributes
+	}
+
+	if request.Channels != nil {
+		presetUpdate.Channels = request.Channels
+	}
+
+	//	Update the preset:
+	updatedPreset, err := service.DB.UpdatePreset(presetUpdate)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Record the event:
+	service.DB.AddEvent(event.PresetUpdated, fmt.Sprintf("Preset ID: %s / %s", updatedPreset.ID, updatedPreset.Name), GetIP(req), service.HistoryTTL)
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "Preset updated",
+		Data:    updatedPreset,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// DeletePreset godoc
+// @Summary Deletes a preset
+// @Description Deletes a preset
+// @Tags presets
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The preset id to delete"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /presets/{id} [delete]
+func (service Service) DeletePreset(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get the id from the url (if it's blank, return an error)
+	vars := mux.Vars(req)
+	if vars["id"] == "" {
+		err := fmt.Errorf("requires an id of a preset to delete")
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Delete the preset
+	err := service.DB.DeletePreset(vars["id"])
+	if err != nil {
+		err = fmt.Errorf("error deleting preset: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Record the event:
+	service.DB.AddEvent(event.PresetDeleted, vars["id"], GetIP(req), service.HistoryTTL)
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: "Preset deleted",
+		Data:    vars["id"],
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
@@ -0,0 +1,339 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+	"github.com/danesparza/fxdmx/internal/event"
+	"github.com/danesparza/fxdmx/internal/profileimport"
+	"github.com/gorilla/mux"
+)
+
+// maxProfileUploadSize is the most fixture file data we'll keep in memory while importing
+const maxProfileUploadSize = 32 << 20
+
+// ListAllFixtureProfiles godoc
+// @Summary List all fixture profiles in the system
+// @Description List all fixture profiles in the system
+// @Tags profiles
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} api.SystemResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /profiles [get]
+func (service Service) ListAllFixtureProfiles(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get a list of profiles
+	retval, err := service.DB.GetAllFixtureProfiles()
+	if err != nil {
+		err = fmt.Errorf("error getting a list of fixture profiles: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: fmt.Sprintf("%v fixture profile(s)", len(retval)),
+		Data:    retval,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// GetFixtureProfile godoc
+// @Summary Gets a fixture profile
+// @Description Gets a fixture profile
+// @Tags profiles
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The fixture profile id to get"
+// @Success 200 {object} api.SystemResponse
+// @Failure 404 {object} api.ErrorResponse
+// @Router /profiles/{id} [get]
+func (service Service) GetFixtureProfile(rw http.ResponseWriter, req *http.Request) {
+
+	//	Parse the request
+	vars := mux.Vars(req)
+
+	//	Get the profile
+	profile, err := service.DB.GetFixtureProfile(vars["id"])
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusNotFound)
+		return
+	}
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "Fixture profile fetched",
+		Data:    profile,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// CreateFixtureProfile godoc
+// @Summary Create a new fixture profile
+// @Description Create a new fixture profile
+// @Tags profiles
+// @Accept  json
+// @Produce  json
+// @Param profile body api.CreateFixtureProfileRequest true "The fixture profile to create"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Router /profiles [post]
+func (service Service) CreateFixtureProfile(rw http.ResponseWriter, req *http.Request) {
+
+	//	req.Body is a ReadCloser -- we need to remember to close it:
+	defer req.Body.Close()
+
+	//	Decode the request
+	request := CreateFixtureProfileRequest{}
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Create the new profile:
+	newProfile, err := service.DB.AddFixtureProfile(request.Manufacturer, request.Model, request.Modes)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Record the event:
+	service.DB.AddEvent(event.ProfileCreated, fmt.Sprintf("Profile ID: %s / %s %s", newProfile.ID, newProfile.Manufacturer, newProfile.Model), GetIP(req), service.HistoryTTL)
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "Fixture profile created",
+		Data:    newProfile,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// UpdateFixtureProfile godoc
+// @Summary Update a fixture profile
+// @Description Update a fixture profile
+// @Tags profiles
+// @Accept  json
+// @Produce  json
+// @Param profile body api.UpdateFixtureProfileRequest true "The fixture profile to update.  Must include profile.id"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Router /profiles [put]
+func (service Service) UpdateFixtureProfile(rw http.ResponseWriter, req *http.Request) {
+
+	//	req.Body is a ReadCloser -- we need to remember to close it:
+	defer req.Body.Close()
+
+	//	Decode the request
+	request := UpdateFixtureProfileRequest{}
+	err := json.NewDecoder(req.Body).Decode(&request)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	If we don't have the profile.id, make sure we indicate that's not valid
+	if strings.TrimSpace(request.ID) == "" {
+		sendErrorResponse(rw, fmt.Errorf("the profile.id is required"), http.StatusBadRequest)
+		return
+	}
+
+	//	Make sure the id exists
+	profileUpdate, _ := service.DB.GetFixtureProfile(request.ID)
+	if profileUpdate.ID != request.ID {
+		sendErrorResponse(rw, fmt.Errorf("fixture profile must already exist"), http.StatusBadRequest)
+		return
+	}
+
+	//	Only update the fields that have been passed
+	if strings.TrimSpace(request.Manufacturer) != "" {
+		profileUpdate.Manufacturer = request.Manufacturer
+	}
+
+	if strings.TrimSpace(request.Model) != "" {
+		profileUpdate.Model = request.Model
+	}
+
+	if len(request.Modes) > 0 {
+		profileUpdate.Modes = request.Modes
+	}
+
+	//	Update the profile:
+	updatedProfile, err := service.DB.UpdateFixtureProfile(profileUpdate)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Record the event:
+	service.DB.AddEvent(event.ProfileUpdated, fmt.Sprintf("Profile ID: %s / %s %s", updatedProfile.ID, updatedProfile.Manufacturer, updatedProfile.Model), GetIP(req), service.HistoryTTL)
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: "Fixture profile updated",
+		Data:    updatedProfile,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// DeleteFixtureProfile godoc
+// @Summary Deletes a fixture profile in the system
+// @Description Deletes a fixture profile in the system
+// @Tags profiles
+// @Accept  json
+// @Produce  json
+// @Param id path string true "The fixture profile id to delete"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 409 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /profiles/{id} [delete]
+func (service Service) DeleteFixtureProfile(rw http.ResponseWriter, req *http.Request) {
+
+	//	Get the id from the url (if it's blank, return an error)
+	vars := mux.Vars(req)
+	if vars["id"] == "" {
+		err := fmt.Errorf("requires an id of a fixture profile to delete")
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Delete the profile
+	err := service.DB.DeleteFixtureProfile(vars["id"])
+	if errors.Is(err, data2.ErrProfileInUse) {
+		sendErrorResponse(rw, err, http.StatusConflict)
+		return
+	}
+	if err != nil {
+		err = fmt.Errorf("error deleting fixture profile: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Record the event:
+	service.DB.AddEvent(event.ProfileDeleted, vars["id"], GetIP(req), service.HistoryTTL)
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: "Fixture profile deleted",
+		Data:    vars["id"],
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
+// ImportOFLFixtureProfiles godoc
+// @Summary Import fixture profiles from Open Fixture Library fixture files
+// @Description Import fixture profiles from one or more uploaded Open Fixture Library (OFL) JSON fixture files
+// @Tags profiles
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param file formData file true "The OFL fixture file(s) to import"
+// @Param manufacturer formData string false "The fixture manufacturer (OFL fixture files don't include it)"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /profiles/import/ofl [post]
+func (service Service) ImportOFLFixtureProfiles(rw http.ResponseWriter, req *http.Request) {
+	service.importFixtureProfiles(rw, req, "OFL", func(file multipart.File, header *multipart.FileHeader) (data2.FixtureProfile, error) {
+		return profileimport.ParseOFL(file, req.FormValue("manufacturer"))
+	})
+}
+
+// ImportGDTFFixtureProfiles godoc
+// @Summary Import fixture profiles from GDTF fixture files
+// @Description Import fixture profiles from one or more uploaded GDTF (General Device Type Format) .gdtf archives.  Each DMX mode becomes a profile mode
+// @Tags profiles
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param file formData file true "The .gdtf file(s) to import"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /profiles/import/gdtf [post]
+func (service Service) ImportGDTFFixtureProfiles(rw http.ResponseWriter, req *http.Request) {
+	service.importFixtureProfiles(rw, req, "GDTF", func(file multipart.File, header *multipart.FileHeader) (data2.FixtureProfile, error) {
+		return profileimport.ParseGDTF(file, header.Size)
+	})
+}
+
+// importFixtureProfiles converts each uploaded fixture file with the parse function and adds the profiles
+func (service Service) importFixtureProfiles(rw http.ResponseWriter, req *http.Request, format string, parse func(multipart.File, *multipart.FileHeader) (data2.FixtureProfile, error)) {
+
+	//	Parse the uploaded files
+	if err := req.ParseMultipartForm(maxProfileUploadSize); err != nil {
+		sendErrorResponse(rw, fmt.Errorf("problem reading the uploaded files: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	files := req.MultipartForm.File["file"]
+	if len(files) == 0 {
+		sendErrorResponse(rw, fmt.Errorf("at least one %s fixture file is required", format), http.StatusBadRequest)
+		return
+	}
+
+	//	Convert and check every file before we add any of them
+	//	(so one bad file doesn't leave us with half an import)
+	profiles := []data2.FixtureProfile{}
+	for _, header := range files {
+		file, err := header.Open()
+		if err != nil {
+			sendErrorResponse(rw, fmt.Errorf("problem opening %s: %v", header.Filename, err), http.StatusBadRequest)
+			return
+		}
+
+		profile, err := parse(file, header)
+		file.Close()
+		if err == nil {
+			err = profile.Validate()
+		}
+		if err != nil {
+			sendErrorResponse(rw, fmt.Errorf("%s: %v", header.Filename, err), http.StatusBadRequest)
+			return
+		}
+
+		profiles = append(profiles, profile)
+	}
+
+	//	Add the profiles (all together)
+	retval, err := service.DB.AddFixtureProfiles(profiles)
+	if err != nil {
+		sendErrorResponse(rw, fmt.Errorf("problem importing the fixture profiles: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	//	Record the events:
+	for _, newProfile := range retval {
+		service.DB.AddEvent(event.ProfileCreated, fmt.Sprintf("Profile ID: %s / %s %s (imported from %s)", newProfile.ID, newProfile.Manufacturer, newProfile.Model, format), GetIP(req), service.HistoryTTL)
+	}
+
+	//	Create our response and send information back:
+	response := SystemResponse{
+		Message: fmt.Sprintf("%v fixture profile(s) imported", len(retval)),
+		Data:    retval,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
@@ -1,10 +1,14 @@
 package api
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	data2 "github.com/danesparza/fxdmx/internal/data"
 	"github.com/danesparza/fxdmx/internal/dmx"
+	"github.com/danesparza/fxdmx/internal/jsonc"
+	"io"
 	"net/http"
 	"time"
 )
@@ -23,6 +27,24 @@ type Service struct {
 
 	//	StopAllTimelines signals all timelines should stop playing
 	StopAllTimelines chan bool
+
+	// StartInput signals DMX input should be received from a source
+	StartInput chan dmx.InputRequest
+
+	// StopInput signals DMX input should stop being received
+	StopInput chan bool
+
+	// Input tracks the most recently received DMX input
+	Input *dmx.InputUniverse
+
+	// Recorder records DMX input into new timelines
+	Recorder *dmx.InputRecorder
+
+	// RunCue signals a cue list command (GO, BACK, GOTO or release)
+	RunCue chan dmx.CueCommand
+
+	// CueLists tracks where each running cue list is up to
+	CueLists *dmx.CueListPlayback
 }
 
 // CreateTimelineRequest is a request to create a new timeline
@@ -30,6 +52,7 @@ type CreateTimelineRequest struct {
 	Name          string                `json:"name"`    // The timeline name
 	USBDevicePath string                `json:"devpath"` // The usb device path to use for this timeline
 	Frames        []data2.TimelineFrame `json:"frames"`  // The frame sequence to progress through
+	Tracks        []data2.Track         `json:"tracks"`  // More frame sequences that play alongside the frames
 }
 
 // UpdateTimelineRequest is a request to update a timeline
@@ -39,6 +62,90 @@ type UpdateTimelineRequest struct {
 	Name          string                `json:"name"`    // The timeline name
 	USBDevicePath string                `json:"devpath"` // The usb device path to use for this timeline
 	Frames        []data2.TimelineFrame `json:"frames"`  // The frame sequence to progress through
+	Tracks        *[]data2.Track        `json:"tracks"`  // More frame sequences that play alongside the frames.  If not passed, the tracks are kept.  Pass an empty list to remove them
+}
+
+// RenderTimelineRequest is a request to render a timeline without playing it
+type RenderTimelineRequest struct {
+	Rate   float64 `json:"rate"`   // Samples per second (optional) Defaults to 40
+	Format string  `json:"format"` // The format to return (json/csv) (optional) Defaults to json
+}
+
+// CreateFixtureProfileRequest is a request to create a new fixture profile
+type CreateFixtureProfileRequest struct {
+	Manufacturer string              `json:"manufacturer"` // Fixture manufacturer
+	Model        string              `json:"model"`        // Fixture model
+	Modes        []data2.FixtureMode `json:"modes"`        // The DMX modes the fixture supports
+}
+
+// UpdateFixtureProfileRequest is a request to update a fixture profile
+type UpdateFixtureProfileRequest struct {
+	ID           string              `json:"id"`           // Unique profile ID
+	Manufacturer string              `json:"manufacturer"` // Fixture manufacturer
+	Model        string              `json:"model"`        // Fixture model
+	Modes        []data2.FixtureMode `json:"modes"`        // The DMX modes the fixture supports
+}
+
+// CreateFixtureRequest is a request to patch a new fixture
+type CreateFixtureRequest struct {
+	Name      string `json:"name"`      // Unique fixture name
+	ProfileID string `json:"profileid"` // The fixture profile
+	Mode      string `json:"mode"`      // The fixture profile mode.  Optional if the profile only has one mode
+	Universe  int    `json:"universe"`  // The universe to patch to.  Optional.  Defaults to 1 (the only universe supported)
+	Address   int    `json:"address"`   // The DMX start address (1-512)
+}
+
+// UpdateFixtureRequest is a request to update (or re-patch) a fixture
+type UpdateFixtureRequest struct {
+	ID        string `json:"id"`        // Unique fixture ID
+	Name      string `json:"name"`      // Unique fixture name
+	ProfileID string `json:"profileid"` // The fixture profile
+	Mode      string `json:"mode"`      // The fixture profile mode
+	Universe  int    `json:"universe"`  // The universe to patch to
+	Address   int    `json:"address"`   // The DMX start address (1-512)
+}
+
+// CreateFixtureGroupRequest is a request to create a new fixture group
+type CreateFixtureGroupRequest struct {
+	Name     string   `json:"name"`     // Unique group name
+	Fixtures []string `json:"fixtures"` // The fixture ids in the group (in group order)
+}
+
+// UpdateFixtureGroupRequest is a request to update a fixture group
+type UpdateFixtureGroupRequest struct {
+	ID       string   `json:"id"`       // Unique group ID
+	Name     string   `json:"name"`     // Unique group name
+	Fixtures []string `json:"fixtures"` // The fixture ids in the group (in group order)
+}
+
+// CreatePresetRequest is a request to create a new preset
+type CreatePresetRequest struct {
+	Name       string                 `json:"name"`       // Unique preset name
+	Attributes map[string]interface{} `json:"attributes"` // Fixture attribute values (optional) Attributes or channels are required
+	Channels   []data2.ChannelValue   `json:"channels"`   // Raw channel values (optional) Attributes or channels are required
+}
+
+// UpdatePresetRequest is a request to update a preset
+type UpdatePresetRequest struct {
+	ID         string                 `json:"id"`         // Unique preset ID
+	Name       string                 `json:"name"`       // Unique preset name
+	Attributes map[string]interface{} `json:"attributes"` // Fixture attribute values
+	Channels   []data2.ChannelValue   `json:"channels"`   // Raw channel values
+}
+
+// CreateCueListRequest is a request to create a new cue list
+type CreateCueListRequest struct {
+	Name          string      `json:"name"`    // Unique cue list name
+	USBDevicePath string      `json:"devpath"` // The usb device path to run the cue list on.  Optional.  If not set, uses the default
+	Cues          []data2.Cue `json:"cues"`    // The cues, in order
+}
+
+// UpdateCueListRequest is a request to update a cue list
+type UpdateCueListRequest struct {
+	ID            string      `json:"id"`      // Unique cue list ID
+	Name          string      `json:"name"`    // Unique cue list name
+	USBDevicePath string      `json:"devpath"` // The usb device path to run the cue list on
+	Cues          []data2.Cue `json:"cues"`    // The cues, in order
 }
 
 // UpdateDefaultUSBRequest is a request to update the default USB device to use
@@ -46,6 +153,25 @@ type UpdateDefaultUSBRequest struct {
 	DevicePath string `json:"devicepath"` // Unique USB device path
 }
 
+// StartInputRequest is a request to start receiving DMX input
+type StartInputRequest struct {
+	Source        string `json:"source"`   // The input source (enttec/artnet/sacn)
+	USBDevicePath string `json:"devpath"`  // The usb device path to receive from (enttec only).  Optional.  If not set, uses the default
+	Universe      int    `json:"universe"` // The universe to listen to.  Art-Net port-addresses start at 0, sACN universes start at 1
+	Bind          string `json:"bind"`     // The address to listen on for Art-Net.  Optional.  Defaults to :6454
+}
+
+// StartRecordingRequest is a request to start recording DMX input into a new timeline
+type StartRecordingRequest struct {
+	Name          string `json:"name"`          // The name of the timeline to create
+	CollapseFades bool   `json:"collapsefades"` // Collapse smooth ramps into fade frames
+}
+
+// UpdateRDMAddressRequest is a request to set the DMX start address of an RDM device
+type UpdateRDMAddressRequest struct {
+	StartAddress int `json:"startaddress"` // The new DMX start address (1-512)
+}
+
 // SystemResponse is a response for a system request
 type SystemResponse struct {
 	Message string      `json:"message"`
@@ -54,7 +180,8 @@ type SystemResponse struct {
 
 // ErrorResponse represents an API response
 type ErrorResponse struct {
-	Message string `json:"message"`
+	Message string                `json:"message"`
+	Errors  []dmx.ValidationError `json:"errors,omitempty"` // Every problem found (if the request didn't validate)
 }
 
 // Used to send back an error:
@@ -63,12 +190,29 @@ func sendErrorResponse(rw http.ResponseWriter, err error, code int) {
 	response := ErrorResponse{
 		Message: "Error: " + err.Error()}
 
+	//	Include each problem if it was a validation error
+	var problems dmx.ValidationErrors
+	if errors.As(err, &problems) {
+		response.Errors = problems
+	}
+
 	//	Serialize to JSON & return the response:
 	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
 	rw.WriteHeader(code)
 	json.NewEncoder(rw).Encode(response)
 }
 
+// newJSONCDecoder reads a request body that can have comments and trailing
+// commas (like a hand-authored timeline file) and returns a decoder for it
+func newJSONCDecoder(body io.Reader) (*json.Decoder, error) {
+	contents, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.NewDecoder(bytes.NewReader(jsonc.Standardize(contents))), nil
+}
+
 // ShowUI redirects to the /ui/ url path
 func ShowUI(rw http.ResponseWriter, req *http.Request) {
 	// http.Redirect(rw, req, "/ui/", 301)
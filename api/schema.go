@@ -0,0 +1,21 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/danesparza/fxdmx/internal/dmx"
+)
+
+// GetTimelineSchema godoc
+// @Summary Gets the JSON Schema for timelines
+// @Description Gets the JSON Schema (draft-07) for timeline documents, for editors and CI to check timeline files with before they're uploaded
+// @Tags timelines
+// @Produce  json
+// @Success 200 {object} object
+// @Router /schema/timeline [get]
+func (service Service) GetTimelineSchema(rw http.ResponseWriter, req *http.Request) {
+
+	//	Send the schema itself (not wrapped in a SystemResponse), so tools can use the url directly
+	rw.Header().Set("Content-Type", "application/schema+json; charset=utf-8")
+	rw.Write(dmx.TimelineSchema())
+}
@@ -2,9 +2,12 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	data2 "github.com/danesparza/fxdmx/internal/data"
 	"github.com/danesparza/fxdmx/internal/dmx"
 	"github.com/danesparza/fxdmx/internal/event"
+	"io"
 	"net/http"
 	"strings"
 
@@ -44,7 +47,7 @@ func (service Service) ListAllTimelines(rw http.ResponseWriter, req *http.Reques
 
 // CreateTimeline godoc
 // @Summary Create a new timeline
-// @Description Create a new timeline
+// @Description Create a new timeline.  Comments and trailing commas are allowed
 // @Tags timelines
 // @Accept  json
 // @Produce  json
@@ -60,20 +63,23 @@ func (service Service) CreateTimeline(rw http.ResponseWriter, req *http.Request)
 
 	//	Decode the request
 	request := CreateTimelineRequest{}
-	err := json.NewDecoder(req.Body).Decode(&request)
+	decoder, err := newJSONCDecoder(req.Body)
+	if err == nil {
+		err = decoder.Decode(&request)
+	}
 	if err != nil {
 		sendErrorResponse(rw, err, http.StatusBadRequest)
 		return
 	}
 
-	//	If we don't have any webhooks associated, make sure we indicate that's not valid
-	if len(request.Frames) < 1 {
-		sendErrorResponse(rw, fmt.Errorf("at least one frame must be included"), http.StatusBadRequest)
+	//	Make sure the timeline is valid before we save it
+	if problems := dmx.ValidateTimeline(data2.Timeline{Frames: request.Frames, Tracks: request.Tracks}); problems != nil {
+		sendErrorResponse(rw, problems, http.StatusBadRequest)
 		return
 	}
 
 	//	Create the new timeline:
-	newTimeline, err := service.DB.AddTimeline(request.Name, request.USBDevicePath, request.Frames)
+	newTimeline, err := service.DB.AddTimeline(request.Name, request.USBDevicePath, request.Frames, request.Tracks)
 	if err != nil {
 		sendErrorResponse(rw, err, http.StatusInternalServerError)
 		return
@@ -93,9 +99,53 @@ func (service Service) CreateTimeline(rw http.ResponseWriter, req *http.Request)
 	json.NewEncoder(rw).Encode(response)
 }
 
+// ValidateTimeline godoc
+// @Summary Checks a timeline without saving it
+// @Description Checks a timeline document (like a timeline file) the same way it's checked when it's created, without saving it.  Comments and trailing commas are allowed, but unknown fields are problems
+// @Tags timelines
+// @Accept  json
+// @Produce  json
+// @Param timeline body data.Timeline true "The timeline document to check"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Router /timelines/validate [post]
+func (service Service) ValidateTimeline(rw http.ResponseWriter, req *http.Request) {
+
+	//	req.Body is a ReadCloser -- we need to remember to close it:
+	defer req.Body.Close()
+
+	//	Decode the document (catching misspelled fields as well)
+	timeline := data2.Timeline{}
+	decoder, err := newJSONCDecoder(req.Body)
+	if err == nil {
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&timeline)
+	}
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Check it
+	if problems := dmx.ValidateTimeline(timeline); problems != nil {
+		sendErrorResponse(rw, problems, http.StatusBadRequest)
+		return
+	}
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: "Timeline is valid",
+		Data:    timeline,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
 // UpdateTimeline godoc
 // @Summary Update a timeline
-// @Description Update a timeline
+// @Description Update a timeline.  Comments and trailing commas are allowed
 // @Tags timelines
 // @Accept  json
 // @Produce  json
@@ -111,7 +161,10 @@ func (service Service) UpdateTimeline(rw http.ResponseWriter, req *http.Request)
 
 	//	Decode the request
 	request := UpdateTimelineRequest{}
-	err := json.NewDecoder(req.Body).Decode(&request)
+	decoder, err := newJSONCDecoder(req.Body)
+	if err == nil {
+		err = decoder.Decode(&request)
+	}
 	if err != nil {
 		sendErrorResponse(rw, err, http.StatusBadRequest)
 		return
@@ -148,6 +201,17 @@ func (service Service) UpdateTimeline(rw http.ResponseWriter, req *http.Request)
 		timeUpdate.Frames = request.Frames
 	}
 
+	//	Only update tracks if they've been passed (an empty list removes them)
+	if request.Tracks != nil {
+		timeUpdate.Tracks = *request.Tracks
+	}
+
+	//	Make sure the updated timeline is valid before we save it
+	if problems := dmx.ValidateTimeline(timeUpdate); problems != nil {
+		sendErrorResponse(rw, problems, http.StatusBadRequest)
+		return
+	}
+
 	//	Update the timeline:
 	updatedTimeline, err := service.DB.UpdateTimeline(timeUpdate)
 	if err != nil {
@@ -263,6 +327,95 @@ func (service Service) RequestTimelinePlay(rw http.ResponseWriter, req *http.Req
 	json.NewEncoder(rw).Encode(response)
 }
 
+// RenderTimeline godoc
+// @Summary Renders a timeline without playing it
+// @Description Plays a timeline against a virtual clock (without touching any hardware) and returns every channel it sets over time, as JSON or CSV
+// @Tags timelines
+// @Accept  json
+// @Produce  json
+// @Produce  text/csv
+// @Param id path string true "The timeline id to render"
+// @Param render body api.RenderTimelineRequest false "The sample rate and format (optional)"
+// @Success 200 {object} api.SystemResponse
+// @Failure 400 {object} api.ErrorResponse
+// @Failure 500 {object} api.ErrorResponse
+// @Router /timelines/{id}/render [post]
+func (service Service) RenderTimeline(rw http.ResponseWriter, req *http.Request) {
+
+	//	req.Body is a ReadCloser -- we need to remember to close it:
+	defer req.Body.Close()
+
+	//	Get the id from the url (if it's blank, return an error)
+	vars := mux.Vars(req)
+	if vars["id"] == "" {
+		err := fmt.Errorf("requires an id of a timeline to render")
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	//	Decode the request (if there is one)
+	request := RenderTimelineRequest{}
+	err := json.NewDecoder(http.MaxBytesReader(rw, req.Body, 4096)).Decode(&request)
+	if err != nil && !errors.Is(err, io.EOF) {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	if request.Rate == 0 {
+		request.Rate = dmx.DefaultRenderRate
+	}
+
+	//	Check the rate before rendering anything
+	if request.Rate < 0 || request.Rate > dmx.MaxRenderRate {
+		sendErrorResponse(rw, fmt.Errorf("rate must be greater than 0 and at most %v samples per second", dmx.MaxRenderRate), http.StatusBadRequest)
+		return
+	}
+
+	format := strings.ToLower(request.Format)
+	if format != "" && format != "json" && format != "csv" {
+		sendErrorResponse(rw, fmt.Errorf("format '%s' must be either json or csv", request.Format), http.StatusBadRequest)
+		return
+	}
+
+	//	Get the timeline
+	timeline, err := service.DB.GetTimeline(vars["id"])
+	if err != nil {
+		err = fmt.Errorf("error getting timeline: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	//	Render it using the current patch
+	patch, err := dmx.LoadPatch(service.DB)
+	if err != nil {
+		err = fmt.Errorf("error loading the fixture patch: %v", err)
+		sendErrorResponse(rw, err, http.StatusInternalServerError)
+		return
+	}
+
+	render, err := patch.RenderTimeline(timeline, request.Rate)
+	if err != nil {
+		sendErrorResponse(rw, err, http.StatusBadRequest)
+		return
+	}
+
+	if format == "csv" {
+		rw.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		render.WriteCSV(rw)
+		return
+	}
+
+	//	Construct our response
+	response := SystemResponse{
+		Message: fmt.Sprintf("Timeline rendered (%v samples)", len(render.Samples)),
+		Data:    render,
+	}
+
+	//	Serialize to JSON & return the response:
+	rw.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(rw).Encode(response)
+}
+
 // RequestTimelineStop godoc
 // @Summary Stops a specific timeline 'play' process
 // @Description Stops a specific timeline 'play' process
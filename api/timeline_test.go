@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+func TestTimeline_UpdateTimeline_Tracks_KeptUnlessPassed(t *testing.T) {
+
+	//	Arrange
+	db, err := data2.NewManager(filepath.Join(t.TempDir(), "system.db"))
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer db.Close()
+
+	frames := []data2.TimelineFrame{{Type: "scene", Channels: []data2.ChannelValue{{Channel: 1, Value: 255}}}}
+	tracks := []data2.Track{{Frames: frames}}
+	timeline, err := db.AddTimeline("Unit test timeline", "", frames, tracks)
+	if err != nil {
+		t.Fatalf("AddTimeline failed: %s", err)
+	}
+
+	service := Service{DB: db}
+	update := func(body string) int {
+		rw := httptest.NewRecorder()
+		service.UpdateTimeline(rw, httptest.NewRequest(http.MethodPut, "/v1/timelines", strings.NewReader(body)))
+		return rw.Code
+	}
+
+	//	Act
+	framesOnly := update(`{"id": "` + timeline.ID + `", "frames": [{"type": "scene", "channels": [{"channel": 2, "value": 10}]}]}`)
+	afterFrames, _ := db.GetTimeline(timeline.ID)
+
+	cleared := update(`{"id": "` + timeline.ID + `", "tracks": []}`)
+	afterClear, _ := db.GetTimeline(timeline.ID)
+
+	//	Assert
+	if framesOnly != http.StatusOK || len(afterFrames.Tracks) != 1 || afterFrames.Frames[0].Channels[0].Channel != 2 {
+		t.Errorf("UpdateTimeline failed: Should update the frames and keep the tracks but got %v: %+v", framesOnly, afterFrames)
+	}
+
+	if cleared != http.StatusOK || len(afterClear.Tracks) != 0 || len(afterClear.Frames) != 1 {
+		t.Errorf("UpdateTimeline failed: Should remove the tracks when passed an empty list but got %v: %+v", cleared, afterClear)
+	}
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/danesparza/fxdmx/internal/data"
+	"github.com/danesparza/fxdmx/internal/dmx"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	renderRate   float64
+	renderFormat string
+)
+
+// renderCmd represents the render command
+var renderCmd = &cobra.Command{
+	Use:   "render [timeline id]",
+	Short: "Renders a timeline without playing it",
+	Long: `Plays a timeline against a virtual clock (without touching any hardware)
+and prints every channel it sets over time, as JSON or CSV.
+	Example:
+	fxdmx render bvjd3b1o1f7pvq9m8m6g --format csv > show.csv`,
+	Args: cobra.ExactArgs(1),
+	Run:  render,
+}
+
+func render(cmd *cobra.Command, args []string) {
+	format := strings.ToLower(renderFormat)
+	if format != "json" && format != "csv" {
+		log.Fatalf("[ERROR] The format '%s' must be either json or csv", renderFormat)
+	}
+
+	//	Check the rate before we render anything (this also catches NaN)
+	if !(renderRate > 0 && renderRate <= dmx.MaxRenderRate) {
+		log.Fatalf("[ERROR] The --rate flag (%v) must be greater than 0 and at most %v samples per second", renderRate, dmx.MaxRenderRate)
+	}
+
+	//	Open the system database
+	db, err := data.NewManager(viper.GetString("datastore.system"))
+	if err != nil {
+		log.Fatalf("[ERROR] Error trying to open the system database: %s", err)
+	}
+	defer db.Close()
+
+	timeline, err := db.GetTimeline(args[0])
+	if err != nil {
+		log.Fatalf("[ERROR] Error getting timeline: %s", err)
+	}
+
+	//	Render it using the current patch
+	patch, err := dmx.LoadPatch(db)
+	if err != nil {
+		log.Fatalf("[ERROR] Error loading the fixture patch: %s", err)
+	}
+
+	render, err := patch.RenderTimeline(timeline, renderRate)
+	if err != nil {
+		log.Fatalf("[ERROR] Error rendering timeline: %s", err)
+	}
+
+	if format == "csv" {
+		err = render.WriteCSV(os.Stdout)
+	} else {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(render)
+	}
+	if err != nil {
+		log.Fatalf("[ERROR] Error writing the render: %s", err)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(renderCmd)
+
+	renderCmd.Flags().Float64VarP(&renderRate, "rate", "r", dmx.DefaultRenderRate, fmt.Sprintf("Samples per second (up to %v)", dmx.MaxRenderRate))
+	renderCmd.Flags().StringVarP(&renderFormat, "format", "f", "json", "Output format: json/csv")
+}
@@ -68,6 +68,11 @@ func start(cmd *cobra.Command, args []string) {
 		PlayTimeline:     make(chan dmx.PlayTimelineRequest),
 		StopTimeline:     make(chan string),
 		StopAllTimelines: make(chan bool),
+		StartInput:       make(chan dmx.InputRequest),
+		StopInput:        make(chan bool),
+		Input:            dmx.NewInputUniverse(),
+		RunCue:           make(chan dmx.CueCommand),
+		CueLists:         dmx.NewCueListPlayback(),
 		DB:               db,
 		HistoryTTL:       time.Duration(int(historyttl)*24) * time.Hour,
 	}
@@ -77,6 +82,12 @@ func start(cmd *cobra.Command, args []string) {
 		PlayTimeline:     backgroundService.PlayTimeline,
 		StopTimeline:     backgroundService.StopTimeline,
 		StopAllTimelines: backgroundService.StopAllTimelines,
+		StartInput:       backgroundService.StartInput,
+		StopInput:        backgroundService.StopInput,
+		Input:            backgroundService.Input,
+		Recorder:         dmx.NewInputRecorder(backgroundService.Input),
+		RunCue:           backgroundService.RunCue,
+		CueLists:         backgroundService.CueLists,
 		DB:               db,
 		StartTime:        time.Now(),
 		HistoryTTL:       time.Duration(int(historyttl)*24) * time.Hour,
@@ -126,12 +137,69 @@ func start(cmd *cobra.Command, args []string) {
 	restRouter.HandleFunc("/v1/timelines/play/{id}", apiService.RequestTimelinePlay).Methods("POST")  // Play a timeline
 	restRouter.HandleFunc("/v1/timelines/stop/{pid}", apiService.RequestTimelineStop).Methods("POST") // Stop a timeline
 	restRouter.HandleFunc("/v1/timelines/stop", apiService.RequestAllTimelinesStop).Methods("POST")   // Stop all timeline
+	restRouter.HandleFunc("/v1/timelines/{id}/render", apiService.RenderTimeline).Methods("POST")     // Render a timeline without playing it
+	restRouter.HandleFunc("/v1/timelines/validate", apiService.ValidateTimeline).Methods("POST")      // Check a timeline without saving it
+	restRouter.HandleFunc("/v1/schema/timeline", apiService.GetTimelineSchema).Methods("GET")         // Get the timeline JSON Schema
+
+	//	FIXTURE PROFILE ROUTES
+	restRouter.HandleFunc("/v1/profiles", apiService.CreateFixtureProfile).Methods("POST")        // Create a fixture profile
+	restRouter.HandleFunc("/v1/profiles", apiService.UpdateFixtureProfile).Methods("PUT")         // Update a fixture profile
+	restRouter.HandleFunc("/v1/profiles", apiService.ListAllFixtureProfiles).Methods("GET")       // List all fixture profiles
+	restRouter.HandleFunc("/v1/profiles/{id}", apiService.GetFixtureProfile).Methods("GET")       // Get a fixture profile
+	restRouter.HandleFunc("/v1/profiles/{id}", apiService.DeleteFixtureProfile).Methods("DELETE") // Delete a fixture profile
+
+	restRouter.HandleFunc("/v1/profiles/import/ofl", apiService.ImportOFLFixtureProfiles).Methods("POST")   // Import Open Fixture Library fixtures
+	restRouter.HandleFunc("/v1/profiles/import/gdtf", apiService.ImportGDTFFixtureProfiles).Methods("POST") // Import GDTF fixtures
+
+	//	FIXTURE (PATCH) ROUTES
+	restRouter.HandleFunc("/v1/fixtures", apiService.CreateFixture).Methods("POST")        // Patch a fixture
+	restRouter.HandleFunc("/v1/fixtures", apiService.UpdateFixture).Methods("PUT")         // Update (re-patch) a fixture
+	restRouter.HandleFunc("/v1/fixtures", apiService.ListAllFixtures).Methods("GET")       // List the patch
+	restRouter.HandleFunc("/v1/fixtures/{id}", apiService.GetFixture).Methods("GET")       // Get a fixture
+	restRouter.HandleFunc("/v1/fixtures/{id}", apiService.DeleteFixture).Methods("DELETE") // Unpatch a fixture
+
+	//	FIXTURE GROUP ROUTES
+	restRouter.HandleFunc("/v1/groups", apiService.CreateFixtureGroup).Methods("POST")        // Create a fixture group
+	restRouter.HandleFunc("/v1/groups", apiService.UpdateFixtureGroup).Methods("PUT")         // Update a fixture group
+	restRouter.HandleFunc("/v1/groups", apiService.ListAllFixtureGroups).Methods("GET")       // List all fixture groups
+	restRouter.HandleFunc("/v1/groups/{id}", apiService.GetFixtureGroup).Methods("GET")       // Get a fixture group
+	restRouter.HandleFunc("/v1/groups/{id}", apiService.DeleteFixtureGroup).Methods("DELETE") // Delete a fixture group
+
+	//	PRESET ROUTES
+	restRouter.HandleFunc("/v1/presets", apiService.CreatePreset).Methods("POST")        // Create a preset
+	restRouter.HandleFunc("/v1/presets", apiService.UpdatePreset).Methods("PUT")         // Update a preset
+	restRouter.HandleFunc("/v1/presets", apiService.ListAllPresets).Methods("GET")       // List all presets
+	restRouter.HandleFunc("/v1/presets/{id}", apiService.GetPreset).Methods("GET")       // Get a preset
+	restRouter.HandleFunc("/v1/presets/{id}", apiService.DeletePreset).Methods("DELETE") // Delete a preset
+
+	//	CUE LIST ROUTES
+	restRouter.HandleFunc("/v1/cuelists", apiService.CreateCueList).Methods("POST")               // Create a cue list
+	restRouter.HandleFunc("/v1/cuelists", apiService.UpdateCueList).Methods("PUT")                // Update a cue list
+	restRouter.HandleFunc("/v1/cuelists", apiService.ListAllCueLists).Methods("GET")              // List all cue lists
+	restRouter.HandleFunc("/v1/cuelists/{id}", apiService.GetCueList).Methods("GET")              // Get a cue list
+	restRouter.HandleFunc("/v1/cuelists/{id}", apiService.DeleteCueList).Methods("DELETE")        // Delete a cue list
+	restRouter.HandleFunc("/v1/cuelists/{id}/go", apiService.CueListGo).Methods("POST")           // GO (run the next cue)
+	restRouter.HandleFunc("/v1/cuelists/{id}/back", apiService.CueListBack).Methods("POST")       // BACK (run the cue before)
+	restRouter.HandleFunc("/v1/cuelists/{id}/goto/{cue}", apiService.CueListGoto).Methods("POST") // GOTO a cue
+	restRouter.HandleFunc("/v1/cuelists/{id}/release", apiService.CueListRelease).Methods("POST") // Stop running a cue list
+	restRouter.HandleFunc("/v1/cuelists/{id}/status", apiService.GetCueListStatus).Methods("GET") // Where a running cue list is up to
+
+	//	INPUT ROUTES
+	restRouter.HandleFunc("/v1/input", apiService.GetInput).Methods("GET")                 // Get the received DMX input universe
+	restRouter.HandleFunc("/v1/input/stream", apiService.StreamInput).Methods("GET")       // Stream DMX input changes
+	restRouter.HandleFunc("/v1/input/start", apiService.RequestInputStart).Methods("POST") // Start receiving DMX input
+	restRouter.HandleFunc("/v1/input/stop", apiService.RequestInputStop).Methods("POST")   // Stop receiving DMX input
 
 	//	SYSTEM ROUTES
 	restRouter.HandleFunc("/v1/system/usbinfo", apiService.GetSerialUSBDevices).Methods("GET")    // List all serial USB devices
 	restRouter.HandleFunc("/v1/system/defaultusb", apiService.GetDefaultUSBDev).Methods("GET")    // Get the default serial USB device
 	restRouter.HandleFunc("/v1/system/defaultusb", apiService.UpdateDefaultUSBDev).Methods("PUT") // Set the default serial USB device
 
+	//	DEVICE ROUTES
+	restRouter.HandleFunc("/v1/devices/{name}/rdm", apiService.GetRDMDevices).Methods("GET")                       // Discover RDM devices
+	restRouter.HandleFunc("/v1/devices/{name}/rdm/{uid}", apiService.GetRDMDevice).Methods("GET")                  // Get an RDM device
+	restRouter.HandleFunc("/v1/devices/{name}/rdm/{uid}/address", apiService.UpdateRDMStartAddress).Methods("PUT") // Set the start address of an RDM device
+
 	//	EVENT ROUTES
 	restRouter.HandleFunc("/v1/events", apiService.GetAllEvents).Methods("GET") // List all events
 	restRouter.HandleFunc("/v1/event/{id}", apiService.GetEvent).Methods("GET") // Get a specific log event
@@ -143,6 +211,17 @@ func start(cmd *cobra.Command, args []string) {
 	//	- handle requests to play a timeline:
 	go backgroundService.HandleAndProcess(ctx)
 
+	//	If we have an input source configured, start receiving from it:
+	if inputSource := viper.GetString("input.source"); inputSource != "" {
+		log.Printf("[INFO] Receiving DMX input from: %s\n", inputSource)
+		backgroundService.StartInput <- dmx.InputRequest{
+			Source:        strings.ToLower(inputSource),
+			USBDevicePath: viper.GetString("input.devpath"),
+			Universe:      viper.GetInt("input.universe"),
+			Bind:          viper.GetString("input.bind"),
+		}
+	}
+
 	//	Setup the CORS options:
 	log.Printf("[INFO] Allowed CORS origins: %s\n", viper.GetString("server.allowed-origins"))
 
@@ -28,9 +28,9 @@ var doc = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/event/{id}": {
+        "/cuelists": {
             "get": {
-                "description": "Gets a log event.",
+                "description": "List all cue lists in the system",
                 "consumes": [
                     "application/json"
                 ],
@@ -38,18 +38,9 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "events"
-                ],
-                "summary": "Gets a log event.",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "The event id to get",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "cuelists"
                 ],
+                "summary": "List all cue lists in the system",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -57,18 +48,16 @@ var doc = `{
                             "$ref": "#/definitions/api.SystemResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/events": {
-            "get": {
-                "description": "Gets all events in the system",
+            },
+            "put": {
+                "description": "Update a cue list.  A running cue list picks up the changes on its next GO, BACK or GOTO (a new devpath needs a release first)",
                 "consumes": [
                     "application/json"
                 ],
@@ -76,9 +65,20 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "events"
+                    "cuelists"
+                ],
+                "summary": "Update a cue list",
+                "parameters": [
+                    {
+                        "description": "The cue list to update.  Must include cuelist.id",
+                        "name": "cuelist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateCueListRequest"
+                        }
+                    }
                 ],
-                "summary": "Gets all events in the system",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -86,18 +86,16 @@ var doc = `{
                             "$ref": "#/definitions/api.SystemResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/system/defaultusb": {
-            "get": {
-                "description": "Get the current default USB device",
+            },
+            "post": {
+                "description": "Create a new cue list.  Cues are run one at a time with GO, BACK and GOTO",
                 "consumes": [
                     "application/json"
                 ],
@@ -105,9 +103,20 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "system"
+                    "cuelists"
+                ],
+                "summary": "Create a new cue list",
+                "parameters": [
+                    {
+                        "description": "The cue list to create",
+                        "name": "cuelist",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateCueListRequest"
+                        }
+                    }
                 ],
-                "summary": "Get the current default USB device",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -115,16 +124,18 @@ var doc = `{
                             "$ref": "#/definitions/api.SystemResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update the default USB device",
+            }
+        },
+        "/cuelists/{id}": {
+            "get": {
+                "description": "Gets a cue list",
                 "consumes": [
                     "application/json"
                 ],
@@ -132,18 +143,16 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "system"
+                    "cuelists"
                 ],
-                "summary": "Update the default USB device",
+                "summary": "Gets a cue list",
                 "parameters": [
                     {
-                        "description": "The device path to use.  Example: /dev/ttyUSB0",
-                        "name": "timeline",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.UpdateDefaultUSBRequest"
-                        }
+                        "type": "string",
+                        "description": "The cue list id to get",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -153,24 +162,16 @@ var doc = `{
                             "$ref": "#/definitions/api.SystemResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/system/usbinfo": {
-            "get": {
-                "description": "Gets information about currently connected USB serial devices",
+            },
+            "delete": {
+                "description": "Deletes a cue list (and releases it if it is running)",
                 "consumes": [
                     "application/json"
                 ],
@@ -178,9 +179,18 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "system"
+                    "cuelists"
+                ],
+                "summary": "Deletes a cue list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The cue list id to delete",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Gets information about currently connected USB serial devices",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -188,6 +198,12 @@ var doc = `{
                             "$ref": "#/definitions/api.SystemResponse"
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -197,9 +213,9 @@ var doc = `{
                 }
             }
         },
-        "/timelines": {
-            "get": {
-                "description": "List all timelines in the system",
+        "/cuelists/{id}/back": {
+            "post": {
+                "description": "Runs the cue before the current one in a cue list (using that cue's fade times)",
                 "consumes": [
                     "application/json"
                 ],
@@ -207,9 +223,18 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "timelines"
+                    "cuelists"
+                ],
+                "summary": "Runs the cue before the current one in a cue list",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The cue list id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "List all timelines in the system",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -217,16 +242,18 @@ var doc = `{
                             "$ref": "#/definitions/api.SystemResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update a timeline",
+            }
+        },
+        "/cuelists/{id}/go": {
+            "post": {
+                "description": "Runs the next cue in a cue list (the first cue if the cue list isn't running yet)",
                 "consumes": [
                     "application/json"
                 ],
@@ -234,18 +261,16 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "timelines"
+                    "cuelists"
                 ],
-                "summary": "Update a timeline",
+                "summary": "Runs the next cue in a cue list",
                 "parameters": [
                     {
-                        "description": "The timeline to update.  Must include timeline.id",
-                        "name": "timeline",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.UpdateTimelineRequest"
-                        }
+                        "type": "string",
+                        "description": "The cue list id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -260,17 +285,13 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
-                        }
                     }
                 }
-            },
+            }
+        },
+        "/cuelists/{id}/goto/{cue}": {
             "post": {
-                "description": "Create a new timeline",
+                "description": "Runs a cue in a cue list by its number (using that cue's fade times)",
                 "consumes": [
                     "application/json"
                 ],
@@ -278,18 +299,23 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "timelines"
+                    "cuelists"
                 ],
-                "summary": "Create a new timeline",
+                "summary": "Runs a cue in a cue list by its number",
                 "parameters": [
                     {
-                        "description": "The timeline to create",
-                        "name": "timeline",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.CreateTimelineRequest"
-                        }
+                        "type": "string",
+                        "description": "The cue list id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "The cue number to run",
+                        "name": "cue",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -304,19 +330,13 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
-                        }
                     }
                 }
             }
         },
-        "/timelines/play/{id}": {
+        "/cuelists/{id}/release": {
             "post": {
-                "description": "Plays a timeline in the system",
+                "description": "Stops running a cue list.  The output holds the last look",
                 "consumes": [
                     "application/json"
                 ],
@@ -324,13 +344,13 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "timelines"
+                    "cuelists"
                 ],
-                "summary": "Plays a timeline in the system",
+                "summary": "Stops running a cue list",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "The timeline id to play",
+                        "description": "The cue list id",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -348,19 +368,13 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
-                        }
                     }
                 }
             }
         },
-        "/timelines/stop": {
-            "post": {
-                "description": "Stops all timeline 'play' processes",
+        "/cuelists/{id}/status": {
+            "get": {
+                "description": "Gets where a running cue list is up to (the current cue, the next cue and whether the current cue is still fading)",
                 "consumes": [
                     "application/json"
                 ],
@@ -368,22 +382,37 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "timelines"
+                    "cuelists"
+                ],
+                "summary": "Gets where a running cue list is up to",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The cue list id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Stops all timeline 'play' processes",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/api.SystemResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/timelines/stop/{pid}": {
-            "post": {
-                "description": "Stops a specific timeline 'play' process",
+        "/devices/{name}/rdm": {
+            "get": {
+                "description": "Discovers RDM devices connected to an RDM capable USB device and reads their device information (start address, personality, footprint)",
                 "consumes": [
                     "application/json"
                 ],
@@ -391,14 +420,14 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "timelines"
+                    "devices"
                 ],
-                "summary": "Stops a specific timeline 'play' process",
+                "summary": "Discovers RDM devices connected to a USB device",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "The process id to stop",
-                        "name": "pid",
+                        "description": "The USB device name (ttyUSB0) or 'default' for the default USB device",
+                        "name": "name",
                         "in": "path",
                         "required": true
                     }
@@ -415,13 +444,19 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/timelines/{id}": {
-            "delete": {
-                "description": "Deletes a timeline in the system",
+        "/devices/{name}/rdm/{uid}": {
+            "get": {
+                "description": "Gets information about a single RDM device",
                 "consumes": [
                     "application/json"
                 ],
@@ -429,14 +464,21 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "timelines"
+                    "devices"
                 ],
-                "summary": "Deletes a timeline in the system",
+                "summary": "Gets information about a single RDM device",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "The timeline id to delete",
-                        "name": "id",
+                        "description": "The USB device name (ttyUSB0) or 'default' for the default USB device",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The RDM device UID (MMMM:DDDDDDDD)",
+                        "name": "uid",
                         "in": "path",
                         "required": true
                     }
@@ -459,71 +501,2384 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/api.ErrorResponse"
                         }
-                    },
-                    "503": {
-                        "description": "Service Unavailable",
-                        "schema": {
-                            "$ref": "#/definitions/api.ErrorResponse"
-                        }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "api.CreateTimelineRequest": {
-            "type": "object",
-            "properties": {
-                "frames": {
-                    "description": "The frame sequence to progress through",
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/data.TimelineFrame"
-                    }
-                },
+        },
+        "/devices/{name}/rdm/{uid}/address": {
+            "put": {
+                "description": "Sets the DMX start address of an RDM device",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "devices"
+                ],
+                "summary": "Sets the DMX start address of an RDM device",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The USB device name (ttyUSB0) or 'default' for the default USB device",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The RDM device UID (MMMM:DDDDDDDD)",
+                        "name": "uid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The new start address",
+                        "name": "address",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateRDMAddressRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/event/{id}": {
+            "get": {
+                "description": "Gets a log event.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Gets a log event.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The event id to get",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "description": "Gets all events in the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Gets all events in the system",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/fixtures": {
+            "get": {
+                "description": "List all patched fixtures in the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fixtures"
+                ],
+                "summary": "List all patched fixtures in the system",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update (or re-patch) a fixture",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fixtures"
+                ],
+                "summary": "Update (or re-patch) a fixture",
+                "parameters": [
+                    {
+                        "description": "The fixture to update.  Must include fixture.id",
+                        "name": "fixture",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateFixtureRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Patch a new fixture to a universe and start address.  The fixture must fit in channels 1-512 and can't overlap another patched fixture.  Only universe 1 is supported",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fixtures"
+                ],
+                "summary": "Patch a new fixture",
+                "parameters": [
+                    {
+                        "description": "The fixture to patch",
+                        "name": "fixture",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateFixtureRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/fixtures/{id}": {
+            "get": {
+                "description": "Gets a patched fixture",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fixtures"
+                ],
+                "summary": "Gets a patched fixture",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The fixture id to get",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes a fixture from the patch",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fixtures"
+                ],
+                "summary": "Removes a fixture from the patch",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The fixture id to delete",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups": {
+            "get": {
+                "description": "List all fixture groups in the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "List all fixture groups in the system",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a fixture group",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Update a fixture group",
+                "parameters": [
+                    {
+                        "description": "The group to update.  Must include group.id",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateFixtureGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new named group of patched fixtures (like 'wash' or 'uplights').  The order of the fixtures is the order attributes are spread across the group",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Create a new fixture group",
+                "parameters": [
+                    {
+                        "description": "The group to create",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateFixtureGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/groups/{id}": {
+            "get": {
+                "description": "Gets a fixture group",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Gets a fixture group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The group id to get",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a fixture group (the fixtures in it stay patched)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "groups"
+                ],
+                "summary": "Deletes a fixture group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The group id to delete",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/input": {
+            "get": {
+                "description": "Gets the most recently received DMX input universe",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "input"
+                ],
+                "summary": "Gets the most recently received DMX input universe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/input/record": {
+            "get": {
+                "description": "Gets the status of the current DMX input recording",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "input"
+                ],
+                "summary": "Gets the status of the current DMX input recording",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/input/record/start": {
+            "post": {
+                "description": "Start recording DMX input into a new timeline.  DMX input must already be started",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "input"
+                ],
+                "summary": "Start recording DMX input into a new timeline",
+                "parameters": [
+                    {
+                        "description": "The timeline to record",
+                        "name": "recording",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.StartRecordingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/input/record/stop": {
+            "post": {
+                "description": "Stop recording DMX input and save the new timeline",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "input"
+                ],
+                "summary": "Stop recording DMX input and save the new timeline",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/input/start": {
+            "post": {
+                "description": "Start receiving DMX input from a widget input port (enttec), Art-Net (artnet) or sACN (sacn).  Replaces any current input source",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "input"
+                ],
+                "summary": "Start receiving DMX input",
+                "parameters": [
+                    {
+                        "description": "The input source to receive from",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.StartInputRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/input/stop": {
+            "post": {
+                "description": "Stop receiving DMX input",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "input"
+                ],
+                "summary": "Stop receiving DMX input",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/input/stream": {
+            "get": {
+                "description": "Streams DMX input changes as server-sent events.  The first event ('state') is the full universe, each following event ('change') is the set of channels that changed",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "input"
+                ],
+                "summary": "Streams DMX input changes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dmx.InputChange"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/presets": {
+            "get": {
+                "description": "List all presets in the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "presets"
+                ],
+                "summary": "List all presets in the system",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a preset",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "presets"
+                ],
+                "summary": "Update a preset",
+                "parameters": [
+                    {
+                        "description": "The preset to update.  Must include preset.id",
+                        "name": "preset",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdatePresetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new named preset of fixture attributes and/or channel values (like 'warm white' or 'blackout').  Timelines refer to presets by name, so updating a preset changes every timeline that uses it the next time it plays",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "presets"
+                ],
+                "summary": "Create a new preset",
+                "parameters": [
+                    {
+                        "description": "The preset to create",
+                        "name": "preset",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreatePresetRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/presets/{id}": {
+            "get": {
+                "description": "Gets a preset",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "presets"
+                ],
+                "summary": "Gets a preset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The preset id to get",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a preset",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "presets"
+                ],
+                "summary": "Deletes a preset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The preset id to delete",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles": {
+            "get": {
+                "description": "List all fixture profiles in the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "List all fixture profiles in the system",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a fixture profile",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Update a fixture profile",
+                "parameters": [
+                    {
+                        "description": "The fixture profile to update.  Must include profile.id",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateFixtureProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new fixture profile",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Create a new fixture profile",
+                "parameters": [
+                    {
+                        "description": "The fixture profile to create",
+                        "name": "profile",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateFixtureProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/import/gdtf": {
+            "post": {
+                "description": "Import fixture profiles from one or more uploaded GDTF (General Device Type Format) .gdtf archives.  Each DMX mode becomes a profile mode",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Import fixture profiles from GDTF fixture files",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "The .gdtf file(s) to import",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/import/ofl": {
+            "post": {
+                "description": "Import fixture profiles from one or more uploaded Open Fixture Library (OFL) JSON fixture files",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Import fixture profiles from Open Fixture Library fixture files",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "The OFL fixture file(s) to import",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "The fixture manufacturer (OFL fixture files don't include it)",
+                        "name": "manufacturer",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profiles/{id}": {
+            "get": {
+                "description": "Gets a fixture profile",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Gets a fixture profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The fixture profile id to get",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes a fixture profile in the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "profiles"
+                ],
+                "summary": "Deletes a fixture profile in the system",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The fixture profile id to delete",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/schema/timeline": {
+            "get": {
+                "description": "Gets the JSON Schema (draft-07) for timeline documents, for editors and CI to check timeline files with before they're uploaded",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timelines"
+                ],
+                "summary": "Gets the JSON Schema for timelines",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/defaultusb": {
+            "get": {
+                "description": "Get the current default USB device",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Get the current default USB device",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update the default USB device",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Update the default USB device",
+                "parameters": [
+                    {
+                        "description": "The device path to use.  Example: /dev/ttyUSB0",
+                        "name": "timeline",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateDefaultUSBRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/usbinfo": {
+            "get": {
+                "description": "Gets information about currently connected USB serial devices",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "system"
+                ],
+                "summary": "Gets information about currently connected USB serial devices",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/timelines": {
+            "get": {
+                "description": "List all timelines in the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timelines"
+                ],
+                "summary": "List all timelines in the system",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a timeline.  Comments and trailing commas are allowed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timelines"
+                ],
+                "summary": "Update a timeline",
+                "parameters": [
+                    {
+                        "description": "The timeline to update.  Must include timeline.id",
+                        "name": "timeline",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateTimelineRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new timeline.  Comments and trailing commas are allowed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timelines"
+                ],
+                "summary": "Create a new timeline",
+                "parameters": [
+                    {
+                        "description": "The timeline to create",
+                        "name": "timeline",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.CreateTimelineRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/timelines/play/{id}": {
+            "post": {
+                "description": "Plays a timeline in the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timelines"
+                ],
+                "summary": "Plays a timeline in the system",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The timeline id to play",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/timelines/stop": {
+            "post": {
+                "description": "Stops all timeline 'play' processes",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timelines"
+                ],
+                "summary": "Stops all timeline 'play' processes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/timelines/stop/{pid}": {
+            "post": {
+                "description": "Stops a specific timeline 'play' process",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timelines"
+                ],
+                "summary": "Stops a specific timeline 'play' process",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The process id to stop",
+                        "name": "pid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/timelines/validate": {
+            "post": {
+                "description": "Checks a timeline document (like a timeline file) the same way it's checked when it's created, without saving it.  Comments and trailing commas are allowed, but unknown fields are problems",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timelines"
+                ],
+                "summary": "Checks a timeline without saving it",
+                "parameters": [
+                    {
+                        "description": "The timeline document to check",
+                        "name": "timeline",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/data.Timeline"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/timelines/{id}": {
+            "delete": {
+                "description": "Deletes a timeline in the system",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timelines"
+                ],
+                "summary": "Deletes a timeline in the system",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The timeline id to delete",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/timelines/{id}/render": {
+            "post": {
+                "description": "Plays a timeline against a virtual clock (without touching any hardware) and returns every channel it sets over time, as JSON or CSV",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "timelines"
+                ],
+                "summary": "Renders a timeline without playing it",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "The timeline id to render",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "The sample rate and format (optional)",
+                        "name": "render",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/api.RenderTimelineRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.SystemResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.CreateCueListRequest": {
+            "type": "object",
+            "properties": {
+                "cues": {
+                    "description": "The cues, in order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.Cue"
+                    }
+                },
+                "devpath": {
+                    "description": "The usb device path to run the cue list on.  Optional.  If not set, uses the default",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Unique cue list name",
+                    "type": "string"
+                }
+            }
+        },
+        "api.CreateFixtureGroupRequest": {
+            "type": "object",
+            "properties": {
+                "fixtures": {
+                    "description": "The fixture ids in the group (in group order)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "description": "Unique group name",
+                    "type": "string"
+                }
+            }
+        },
+        "api.CreateFixtureProfileRequest": {
+            "type": "object",
+            "properties": {
+                "manufacturer": {
+                    "description": "Fixture manufacturer",
+                    "type": "string"
+                },
+                "model": {
+                    "description": "Fixture model",
+                    "type": "string"
+                },
+                "modes": {
+                    "description": "The DMX modes the fixture supports",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.FixtureMode"
+                    }
+                }
+            }
+        },
+        "api.CreateFixtureRequest": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "The DMX start address (1-512)",
+                    "type": "integer"
+                },
+                "mode": {
+                    "description": "The fixture profile mode.  Optional if the profile only has one mode",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Unique fixture name",
+                    "type": "string"
+                },
+                "profileid": {
+                    "description": "The fixture profile",
+                    "type": "string"
+                },
+                "universe": {
+                    "description": "The universe to patch to.  Optional.  Defaults to 1 (the only universe supported)",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.CreatePresetRequest": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "Fixture attribute values (optional) Attributes or channels are required",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "channels": {
+                    "description": "Raw channel values (optional) Attributes or channels are required",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.ChannelValue"
+                    }
+                },
+                "name": {
+                    "description": "Unique preset name",
+                    "type": "string"
+                }
+            }
+        },
+        "api.CreateTimelineRequest": {
+            "type": "object",
+            "properties": {
+                "devpath": {
+                    "description": "The usb device path to use for this timeline",
+                    "type": "string"
+                },
+                "frames": {
+                    "description": "The frame sequence to progress through",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.TimelineFrame"
+                    }
+                },
+                "name": {
+                    "description": "The timeline name",
+                    "type": "string"
+                },
+                "tracks": {
+                    "description": "More frame sequences that play alongside the frames",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.Track"
+                    }
+                }
+            }
+        },
+        "api.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "description": "Every problem found (if the request didn't validate)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dmx.ValidationError"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.RenderTimelineRequest": {
+            "type": "object",
+            "properties": {
+                "format": {
+                    "description": "The format to return (json/csv) (optional) Defaults to json",
+                    "type": "string"
+                },
+                "rate": {
+                    "description": "Samples per second (optional) Defaults to 40",
+                    "type": "number"
+                }
+            }
+        },
+        "api.StartInputRequest": {
+            "type": "object",
+            "properties": {
+                "bind": {
+                    "description": "The address to listen on for Art-Net.  Optional.  Defaults to :6454",
+                    "type": "string"
+                },
+                "devpath": {
+                    "description": "The usb device path to receive from (enttec only).  Optional.  If not set, uses the default",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "The input source (enttec/artnet/sacn)",
+                    "type": "string"
+                },
+                "universe": {
+                    "description": "The universe to listen to.  Art-Net port-addresses start at 0, sACN universes start at 1",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.StartRecordingRequest": {
+            "type": "object",
+            "properties": {
+                "collapsefades": {
+                    "description": "Collapse smooth ramps into fade frames",
+                    "type": "boolean"
+                },
+                "name": {
+                    "description": "The name of the timeline to create",
+                    "type": "string"
+                }
+            }
+        },
+        "api.SystemResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.UpdateCueListRequest": {
+            "type": "object",
+            "properties": {
+                "cues": {
+                    "description": "The cues, in order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.Cue"
+                    }
+                },
+                "devpath": {
+                    "description": "The usb device path to run the cue list on",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "Unique cue list ID",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Unique cue list name",
+                    "type": "string"
+                }
+            }
+        },
+        "api.UpdateDefaultUSBRequest": {
+            "type": "object",
+            "properties": {
+                "devicepath": {
+                    "description": "Unique USB device path",
+                    "type": "string"
+                }
+            }
+        },
+        "api.UpdateFixtureGroupRequest": {
+            "type": "object",
+            "properties": {
+                "fixtures": {
+                    "description": "The fixture ids in the group (in group order)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "description": "Unique group ID",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Unique group name",
+                    "type": "string"
+                }
+            }
+        },
+        "api.UpdateFixtureProfileRequest": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "Unique profile ID",
+                    "type": "string"
+                },
+                "manufacturer": {
+                    "description": "Fixture manufacturer",
+                    "type": "string"
+                },
+                "model": {
+                    "description": "Fixture model",
+                    "type": "string"
+                },
+                "modes": {
+                    "description": "The DMX modes the fixture supports",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.FixtureMode"
+                    }
+                }
+            }
+        },
+        "api.UpdateFixtureRequest": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "The DMX start address (1-512)",
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "Unique fixture ID",
+                    "type": "string"
+                },
+                "mode": {
+                    "description": "The fixture profile mode",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Unique fixture name",
+                    "type": "string"
+                },
+                "profileid": {
+                    "description": "The fixture profile",
+                    "type": "string"
+                },
+                "universe": {
+                    "description": "The universe to patch to",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.UpdatePresetRequest": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "Fixture attribute values",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "channels": {
+                    "description": "Raw channel values",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.ChannelValue"
+                    }
+                },
+                "id": {
+                    "description": "Unique preset ID",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "Unique preset name",
+                    "type": "string"
+                }
+            }
+        },
+        "api.UpdateRDMAddressRequest": {
+            "type": "object",
+            "properties": {
+                "startaddress": {
+                    "description": "The new DMX start address (1-512)",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.UpdateTimelineRequest": {
+            "type": "object",
+            "properties": {
+                "devpath": {
+                    "description": "The usb device path to use for this timeline",
+                    "type": "string"
+                },
+                "enabled": {
+                    "description": "Timeline enabled or not",
+                    "type": "boolean"
+                },
+                "frames": {
+                    "description": "The frame sequence to progress through",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.TimelineFrame"
+                    }
+                },
+                "id": {
+                    "description": "Unique Timeline ID",
+                    "type": "string"
+                },
                 "name": {
                     "description": "The timeline name",
                     "type": "string"
+                },
+                "tracks": {
+                    "description": "More frame sequences that play alongside the frames.  If not passed, the tracks are kept.  Pass an empty list to remove them",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.Track"
+                    }
                 }
             }
         },
-        "api.ErrorResponse": {
+        "data.ChannelValue": {
             "type": "object",
             "properties": {
-                "message": {
+                "channel": {
+                    "description": "DMX channel (the coarse channel of a 16 bit value)",
+                    "type": "integer"
+                },
+                "fine": {
+                    "description": "The fine channel of a 16 bit value (optional)",
+                    "type": "integer"
+                },
+                "value": {
+                    "description": "Channel value (0 - 255)",
+                    "type": "integer"
+                },
+                "value16": {
+                    "description": "16 bit value (0 - 65535) split across the channel and fine channel (optional) Used instead of value if fine is set",
+                    "type": "integer"
+                }
+            }
+        },
+        "data.Chase": {
+            "type": "object",
+            "properties": {
+                "direction": {
+                    "description": "The order to run the steps in (forward/reverse/bounce/random) (optional) Defaults to forward",
                     "type": "string"
+                },
+                "faderatio": {
+                    "description": "How much of each step is spent fading into it (0.0 - 1.0) (optional) If not set, the chase snaps from step to step",
+                    "type": "number"
+                },
+                "repeat": {
+                    "description": "How many times to run through the steps (optional) Defaults to 1",
+                    "type": "integer"
+                },
+                "seed": {
+                    "description": "Seeds the random direction (optional) A chase with the same seed plays the same way every time",
+                    "type": "integer"
+                },
+                "steps": {
+                    "description": "The chase steps, in order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.ChaseStep"
+                    }
+                },
+                "steptime": {
+                    "description": "How long each step lasts in milliseconds",
+                    "type": "integer"
                 }
             }
         },
-        "api.SystemResponse": {
+        "data.ChaseStep": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "object"
+                "channels": {
+                    "description": "Channel information to set for the step (optional) Channels, fixtures or presets are required",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.ChannelValue"
+                    }
                 },
-                "message": {
+                "fixtures": {
+                    "description": "Fixture attributes to set for the step (optional)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.FixtureValue"
+                    }
+                },
+                "presets": {
+                    "description": "Names of presets whose channels to set for the step (optional)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "data.Cue": {
+            "type": "object",
+            "properties": {
+                "autofollow": {
+                    "description": "Run the next cue automatically once this one has finished fading (optional)",
+                    "type": "boolean"
+                },
+                "channels": {
+                    "description": "Channel information to set for the cue (optional) Channels, fixtures or presets are required",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.ChannelValue"
+                    }
+                },
+                "delay": {
+                    "description": "How long to wait after GO before the cue starts fading in milliseconds (optional)",
+                    "type": "integer"
+                },
+                "fadein": {
+                    "description": "How long channels going up take to fade in milliseconds (optional) If not set, they snap",
+                    "type": "integer"
+                },
+                "fadeout": {
+                    "description": "How long channels going down take to fade in milliseconds (optional) If not set, they snap",
+                    "type": "integer"
+                },
+                "fixtures": {
+                    "description": "Fixture attributes to set for the cue (optional)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.FixtureValue"
+                    }
+                },
+                "followtime": {
+                    "description": "How long to wait before running the next cue automatically in milliseconds (optional)",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Cue name (optional)",
                     "type": "string"
+                },
+                "number": {
+                    "description": "Cue number (like 1, 2 or 2.5).  Must go up through the list.  If not set, it's one more than the cue before it",
+                    "type": "number"
+                },
+                "presets": {
+                    "description": "Names of presets whose channels to set for the cue (optional)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "api.UpdateDefaultUSBRequest": {
+        "data.Effect": {
             "type": "object",
             "properties": {
-                "devicepath": {
-                    "description": "Unique USB device path",
+                "attribute": {
+                    "description": "The fixture attribute the effect runs on (optional) Defaults to dimmer",
+                    "type": "string"
+                },
+                "bpm": {
+                    "description": "Cycles per minute (optional) Either rate or bpm is required",
+                    "type": "number"
+                },
+                "duration": {
+                    "description": "How long the effect runs in milliseconds (optional) If not set, runs through any sleep frames that follow until the next scene, fade or effect frame",
+                    "type": "integer"
+                },
+                "max": {
+                    "description": "Highest level (0.0 - 1.0) If min and max are both 0, max is 1.0",
+                    "type": "number"
+                },
+                "min": {
+                    "description": "Lowest level (0.0 - 1.0)",
+                    "type": "number"
+                },
+                "phase": {
+                    "description": "Phase offset in degrees between each channel or fixture (optional)",
+                    "type": "number"
+                },
+                "rate": {
+                    "description": "Cycles per second (Hz) (optional) Either rate or bpm is required, except for natural effects (which have a default)",
+                    "type": "number"
+                },
+                "seed": {
+                    "description": "Seeds the random and natural effects (optional) An effect with the same seed plays the same way every time",
+                    "type": "integer"
+                },
+                "shape": {
+                    "description": "Waveform (sine/saw/square/random/strobe) or natural effect (candle/fire/lightning/tv/fluorescent)",
                     "type": "string"
                 }
             }
         },
-        "api.UpdateTimelineRequest": {
+        "data.FixtureChannel": {
+            "type": "object",
+            "properties": {
+                "attribute": {
+                    "description": "Attribute type (dimmer/red/green/blue/pan/tilt/strobe/gobo ... or generic)",
+                    "type": "string"
+                },
+                "default": {
+                    "description": "Default channel value",
+                    "type": "integer"
+                },
+                "fine": {
+                    "description": "This is the fine (low byte) channel of a 16 bit attribute",
+                    "type": "boolean"
+                },
+                "functions": {
+                    "description": "What the channel does across its DMX range (optional)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.FixtureChannelFunction"
+                    }
+                },
+                "name": {
+                    "description": "Channel name",
+                    "type": "string"
+                }
+            }
+        },
+        "data.FixtureChannelFunction": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "description": "The first DMX value of the range",
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "Function name (like 'Strobe slow to fast')",
+                    "type": "string"
+                },
+                "physicalfrom": {
+                    "description": "The physical value at the start of the range (like -270 degrees).  Optional",
+                    "type": "number"
+                },
+                "physicalto": {
+                    "description": "The physical value at the end of the range (like 270 degrees).  Optional",
+                    "type": "number"
+                },
+                "to": {
+                    "description": "The last DMX value of the range",
+                    "type": "integer"
+                }
+            }
+        },
+        "data.FixtureMode": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "description": "Ordered channel list.  The first channel is at the fixture start address",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.FixtureChannel"
+                    }
+                },
+                "name": {
+                    "description": "Mode name (like '7 channel')",
+                    "type": "string"
+                }
+            }
+        },
+        "data.FixtureValue": {
+            "type": "object",
+            "properties": {
+                "attributes": {
+                    "description": "Attribute values (like 'dimmer').  Levels are 0.0 - 1.0.  'color' is a hex, HSV or color temperature color (like '#ff8800', 'hsv(30, 100%, 100%)' or '3200K')",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "fixture": {
+                    "description": "The patched fixture name (optional) Either fixture or group is required",
+                    "type": "string"
+                },
+                "group": {
+                    "description": "The fixture group name (optional) Sets the attributes on every fixture in the group",
+                    "type": "string"
+                },
+                "offset": {
+                    "description": "Delay in milliseconds between each fixture in the group when fading, in group order (optional)",
+                    "type": "integer"
+                },
+                "preset": {
+                    "description": "The name of a preset whose attributes to set (optional) Attributes set here win over the preset's",
+                    "type": "string"
+                },
+                "spread": {
+                    "description": "Attribute values for the last fixture in the group (optional) Fixtures in between get values spread evenly from attributes to spread",
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "data.Keyframe": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "description": "Channel information to set for the keyframe (optional) Channels, fixtures or presets are required",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.ChannelValue"
+                    }
+                },
+                "fixtures": {
+                    "description": "Fixture attributes to set for the keyframe (optional)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.FixtureValue"
+                    }
+                },
+                "presets": {
+                    "description": "Names of presets whose channels to set for the keyframe (optional)",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "snap": {
+                    "description": "Jump to the keyframe at its time instead of fading from the channel's keyframe before it (optional)",
+                    "type": "boolean"
+                },
+                "time": {
+                    "description": "When the look is reached, in milliseconds from the start of the frame",
+                    "type": "integer"
+                }
+            }
+        },
+        "data.Timeline": {
             "type": "object",
             "properties": {
+                "created": {
+                    "description": "Timeline create time",
+                    "type": "string"
+                },
+                "devpath": {
+                    "description": "The USB device to play the timeline on.  Optional.  If not set, uses the default",
+                    "type": "string"
+                },
                 "enabled": {
                     "description": "Timeline enabled or not",
                     "type": "boolean"
                 },
                 "frames": {
-                    "description": "The frame sequence to progress through",
+                    "description": "Frames for the timeline",
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/data.TimelineFrame"
@@ -534,40 +2889,124 @@ var doc = `{
                     "type": "string"
                 },
                 "name": {
-                    "description": "The timeline name",
+                    "description": "Timeline name",
                     "type": "string"
+                },
+                "tracks": {
+                    "description": "More frames that play alongside the timeline's frames (optional) Every track starts at the same time",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.Track"
+                    }
                 }
             }
         },
-        "data.ChannelValue": {
+        "data.TimelineFrame": {
             "type": "object",
             "properties": {
-                "channel": {
-                    "description": "Unique Fixture ID",
+                "channels": {
+                    "description": "Channel information to set for the scene (optional) Channels, fixtures or presets are required if type = scene or fade",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.ChannelValue"
+                    }
+                },
+                "chase": {
+                    "description": "The steps to chase through (optional) Required if type = chase",
+                    "$ref": "#/definitions/data.Chase"
+                },
+                "colorspace": {
+                    "description": "How fixture colors fade (rgb/hsv/perceptual) (optional) If not set, colors fade channel by channel",
+                    "type": "string"
+                },
+                "effect": {
+                    "description": "The waveform to run on the frame's channels and fixtures (optional) Required if type = effect",
+                    "$ref": "#/definitions/data.Effect"
+                },
+                "fadetime": {
+                    "description": "Fade time in milliseconds (optional) If not set, fades move one step every millisecond",
                     "type": "integer"
                 },
-                "value": {
-                    "description": "Optional fixture name",
+                "fixtures": {
+                    "description": "Fixture attributes to set for the scene (optional) Resolved to channels using the patch when the timeline is played",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.FixtureValue"
+                    }
+                },
+                "keyframes": {
+                    "description": "Looks pinned to times from the start of the frame (optional) Required if type = keyframes",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.Keyframe"
+                    }
+                },
+                "parallel": {
+                    "description": "Play the other timeline alongside this one instead of waiting for it to finish (optional)",
+                    "type": "boolean"
+                },
+                "presets": {
+                    "description": "Names of presets whose channels to set for the scene (optional) Resolved when the timeline is played",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sleeptime": {
+                    "description": "Sleep type in seconds (optional) Required if type = sleep",
                     "type": "integer"
+                },
+                "timeline": {
+                    "description": "The ID of another timeline to play (optional) Required if type = timeline",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "Timeline frame type (scene/sleep/fade/effect/chase/keyframes/timeline) Fade 'fades' between the previous channel state and this frame",
+                    "type": "string"
                 }
             }
         },
-        "data.TimelineFrame": {
+        "data.Track": {
+            "type": "object",
+            "properties": {
+                "frames": {
+                    "description": "Frames for the track",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/data.TimelineFrame"
+                    }
+                },
+                "name": {
+                    "description": "Track name (optional)",
+                    "type": "string"
+                }
+            }
+        },
+        "dmx.InputChange": {
             "type": "object",
             "properties": {
                 "channels": {
-                    "description": "Channel information to set for the scene (optional) Required if type = scene or fade",
+                    "description": "The channels that changed (and their new values)",
                     "type": "array",
                     "items": {
                         "$ref": "#/definitions/data.ChannelValue"
                     }
                 },
-                "sleeptime": {
-                    "description": "Sleep type in seconds (optional) Required if type = sleep",
-                    "type": "integer"
+                "received": {
+                    "description": "When the change was received",
+                    "type": "string"
+                }
+            }
+        },
+        "dmx.ValidationError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "The path to the field (like 'frames[3].channels[1].channel')",
+                    "type": "string"
                 },
-                "type": {
-                    "description": "Timeline frame type (scene/sleep/fade) Fade 'fades' between the previous channel state and this frame",
+                "message": {
+                    "description": "What's wrong with it",
                     "type": "string"
                 }
             }
@@ -0,0 +1,329 @@
+package color
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+// Color spaces colors can be blended (faded) in
+const (
+	SpaceRGB        = "rgb"        // Channel by channel
+	SpaceHSV        = "hsv"        // Around the hue wheel (the shortest way)
+	SpacePerceptual = "perceptual" // In Oklab, so brightness changes evenly
+)
+
+// RGB is a color with red, green and blue levels from 0.0 to 1.0
+type RGB struct {
+	R float64 `json:"r"`
+	G float64 `json:"g"`
+	B float64 `json:"b"`
+}
+
+var (
+	hsvPattern    = regexp.MustCompile(`^hsv\(\s*([0-9.]+)\s*,\s*([0-9.]+%?)\s*,\s*([0-9.]+%?)\s*\)$`)
+	kelvinPattern = regexp.MustCompile(`^([0-9]+)\s*k$`)
+)
+
+// Parse parses a color.  Colors can be hex (like '#ff8800' or '#f80'),
+// HSV (like 'hsv(30, 100%, 100%)' or 'hsv(30, 1, 1)') or a color
+// temperature in kelvin (like '3200K').
+func Parse(value string) (RGB, error) {
+	text := strings.ToLower(strings.TrimSpace(value))
+
+	if match := hsvPattern.FindStringSubmatch(text); match != nil {
+		h, _ := strconv.ParseFloat(match[1], 64)
+		s, sErr := fraction(match[2])
+		v, vErr := fraction(match[3])
+		if sErr != nil || vErr != nil || h > 360 {
+			return RGB{}, fmt.Errorf("color '%s' isn't a valid HSV color (hue is 0-360, saturation and value are 0-100%%)", value)
+		}
+		return FromHSV(h, s, v), nil
+	}
+
+	if match := kelvinPattern.FindStringSubmatch(text); match != nil {
+		kelvin, _ := strconv.ParseFloat(match[1], 64)
+		if kelvin < 1000 || kelvin > 40000 {
+			return RGB{}, fmt.Errorf("color temperature '%s' must be between 1000K and 40000K", value)
+		}
+		return FromKelvin(kelvin), nil
+	}
+
+	digits := strings.TrimPrefix(text, "#")
+	if len(digits) == 3 {
+		digits = string([]byte{digits[0], digits[0], digits[1], digits[1], digits[2], digits[2]})
+	}
+
+	hex, err := strconv.ParseUint(digits, 16, 32)
+	if len(digits) != 6 || err != nil {
+		return RGB{}, fmt.Errorf("color '%s' isn't a valid color (like '#ff8800', 'hsv(30, 100%%, 100%%)' or '3200K')", value)
+	}
+
+	return RGB{
+		R: float64(hex>>16&0xff) / 255,
+		G: float64(hex>>8&0xff) / 255,
+		B: float64(hex&0xff) / 255,
+	}, nil
+}
+
+// fraction parses a level that is either 0.0 - 1.0 or a percentage
+func fraction(value string) (float64, error) {
+	if strings.HasSuffix(value, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+		if err != nil || percent > 100 {
+			return 0, fmt.Errorf("invalid percentage")
+		}
+		return percent / 100, nil
+	}
+
+	level, err := strconv.ParseFloat(value, 64)
+	if err != nil || level > 1 {
+		return 0, fmt.Errorf("invalid level")
+	}
+	return level, nil
+}
+
+// Hex formats the color as a hex color (like '#ff8800')
+func (c RGB) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", to8(c.R), to8(c.G), to8(c.B))
+}
+
+// FromHSV creates a color from a hue (0-360 degrees), saturation and value (0.0 - 1.0)
+func FromHSV(h, s, v float64) RGB {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+
+	chroma := v * s
+	x := chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := v - chroma
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = chroma, x, 0
+	case h < 120:
+		r, g, b = x, chroma, 0
+	case h < 180:
+		r, g, b = 0, chroma, x
+	case h < 240:
+		r, g, b = 0, x, chroma
+	case h < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+
+	return RGB{R: r + m, G: g + m, B: b + m}
+}
+
+// HSV converts the color to a hue (0-360 degrees), saturation and value (0.0 - 1.0)
+func (c RGB) HSV() (h, s, v float64) {
+	max := math.Max(c.R, math.Max(c.G, c.B))
+	min := math.Min(c.R, math.Min(c.G, c.B))
+	delta := max - min
+
+	v = max
+	if max > 0 {
+		s = delta / max
+	}
+
+	switch {
+	case delta == 0:
+		h = 0
+	case max == c.R:
+		h = 60 * math.Mod((c.G-c.B)/delta, 6)
+	case max == c.G:
+		h = 60 * ((c.B-c.R)/delta + 2)
+	default:
+		h = 60 * ((c.R-c.G)/delta + 4)
+	}
+	if h < 0 {
+		h += 360
+	}
+
+	return h, s, v
+}
+
+// FromKelvin approximates the color of a black body at a color temperature
+// (from 1000K to 40000K)
+func FromKelvin(kelvin float64) RGB {
+	t := kelvin / 100
+
+	var r, g, b float64
+	if t <= 66 {
+		r = 255
+		g = 99.4708025861*math.Log(t) - 161.1195681661
+	} else {
+		r = 329.698727446 * math.Pow(t-60, -0.1332047592)
+		g = 288.1221695283 * math.Pow(t-60, -0.0755148492)
+	}
+
+	switch {
+	case t >= 66:
+		b = 255
+	case t <= 19:
+		b = 0
+	default:
+		b = 138.5177312231*math.Log(t-10) - 305.0447927307
+	}
+
+	return RGB{R: clamp(r / 255), G: clamp(g / 255), B: clamp(b / 255)}
+}
+
+// Blend blends two colors in a color space.  Progress is 0.0 (from) to 1.0 (to)
+func Blend(from, to RGB, progress float64, space string) RGB {
+	switch {
+	case progress <= 0:
+		return from
+	case progress >= 1:
+		return to
+	}
+
+	switch space {
+	case SpaceHSV:
+		fh, fs, fv := from.HSV()
+		th, ts, tv := to.HSV()
+
+		//	Colors without a hue (black, white and grays) take the hue of the other color
+		if fs == 0 || fv == 0 {
+			fh = th
+		}
+		if ts == 0 || tv == 0 {
+			th = fh
+		}
+
+		//	Go around the hue wheel the shortest way
+		dh := th - fh
+		if dh > 180 {
+			dh -= 360
+		} else if dh < -180 {
+			dh += 360
+		}
+
+		return FromHSV(fh+dh*progress, lerp(fs, ts, progress), lerp(fv, tv, progress))
+
+	case SpacePerceptual:
+		fl, fa, fb := from.oklab()
+		tl, ta, tb := to.oklab()
+		return fromOklab(lerp(fl, tl, progress), lerp(fa, ta, progress), lerp(fb, tb, progress))
+	}
+
+	return RGB{R: lerp(from.R, to.R, progress), G: lerp(from.G, to.G, progress), B: lerp(from.B, to.B, progress)}
+}
+
+// ValidSpace returns true if the color space is one colors can be blended in
+// (an empty space means channel by channel)
+func ValidSpace(space string) bool {
+	switch space {
+	case "", SpaceRGB, SpaceHSV, SpacePerceptual:
+		return true
+	}
+	return false
+}
+
+// Emitters converts the color to levels for the emitters a fixture has
+// (keyed by fixture attribute).  Fixtures with red, green and blue emitters
+// use their white and amber emitters for as much of the color as they can.
+// Fixtures with cyan, magenta and yellow flags subtract the color from white.
+// UV is outside the visible color, so it's never set here -- set the uv
+// attribute directly.
+func (c RGB) Emitters(available map[string]bool) (map[string]float64, error) {
+	retval := map[string]float64{}
+	r, g, b := clamp(c.R), clamp(c.G), clamp(c.B)
+
+	switch {
+	case available[data2.AttributeRed] || available[data2.AttributeGreen] || available[data2.AttributeBlue]:
+		//	White is the part of the color all three channels share
+		if available[data2.AttributeWhite] {
+			w := math.Min(r, math.Min(g, b))
+			r, g, b = r-w, g-w, b-w
+			retval[data2.AttributeWhite] = w
+		}
+
+		//	Amber is (roughly) full red with half green
+		if available[data2.AttributeAmber] {
+			a := math.Min(r, g*2)
+			r, g = r-a, g-a/2
+			retval[data2.AttributeAmber] = a
+		}
+
+		retval[data2.AttributeRed], retval[data2.AttributeGreen], retval[data2.AttributeBlue] = r, g, b
+
+	case available[data2.AttributeCyan] || available[data2.AttributeMagenta] || available[data2.AttributeYellow]:
+		retval[data2.AttributeCyan], retval[data2.AttributeMagenta], retval[data2.AttributeYellow] = 1-r, 1-g, 1-b
+
+	default:
+		return nil, fmt.Errorf("doesn't have any red, green, blue or cyan, magenta, yellow channels")
+	}
+
+	//	Only return the emitters the fixture has
+	for attribute := range retval {
+		if !available[attribute] {
+			delete(retval, attribute)
+		}
+	}
+
+	return retval, nil
+}
+
+// oklab converts the color to Oklab (see https://bottosson.github.io/posts/oklab/)
+func (c RGB) oklab() (l, a, b float64) {
+	r, g, bl := linear(c.R), linear(c.G), linear(c.B)
+
+	lc := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*bl)
+	mc := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*bl)
+	sc := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*bl)
+
+	return 0.2104542553*lc + 0.7936177850*mc - 0.0040720468*sc,
+		1.9779984951*lc - 2.4285922050*mc + 0.4505937099*sc,
+		0.0259040371*lc + 0.7827717662*mc - 0.8086757660*sc
+}
+
+// fromOklab converts an Oklab color back to RGB
+func fromOklab(l, a, b float64) RGB {
+	lc := l + 0.3963377774*a + 0.2158037573*b
+	mc := l - 0.1055613458*a - 0.0638541728*b
+	sc := l - 0.0894841775*a - 1.2914855480*b
+
+	lc, mc, sc = lc*lc*lc, mc*mc*mc, sc*sc*sc
+
+	return RGB{
+		R: clamp(gamma(4.0767416621*lc - 3.3077115913*mc + 0.2309699292*sc)),
+		G: clamp(gamma(-1.2684380046*lc + 2.6097574011*mc - 0.3413193965*sc)),
+		B: clamp(gamma(-0.0041960863*lc - 0.7034186147*mc + 1.7076147010*sc)),
+	}
+}
+
+// linear converts an sRGB level to linear light
+func linear(c float64) float64 {
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// gamma converts a linear light level to sRGB
+func gamma(c float64) float64 {
+	if c <= 0.0031308 {
+		return 12.92 * c
+	}
+	return 1.055*math.Pow(c, 1/2.4) - 0.055
+}
+
+func lerp(from, to, progress float64) float64 {
+	return from + (to-from)*progress
+}
+
+func clamp(level float64) float64 {
+	return math.Max(0, math.Min(1, level))
+}
+
+func to8(level float64) byte {
+	return byte(math.Round(clamp(level) * 255))
+}
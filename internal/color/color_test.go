@@ -0,0 +1,150 @@
+package color
+
+import (
+	"math"
+	"testing"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+func TestColor_Parse_Formats_Successful(t *testing.T) {
+
+	//	Arrange
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{"#ff8800", "#ff8800"},
+		{"F80", "#ff8800"},
+		{"hsv(120, 100%, 100%)", "#00ff00"},
+		{"hsv(240, 1, 0.5)", "#000080"},
+		{"6600K", "#ffffff"},
+	}
+
+	for _, test := range tests {
+		//	Act
+		c, err := Parse(test.value)
+
+		//	Assert
+		if err != nil {
+			t.Errorf("Parse - Should parse '%s' without error, but got: %s", test.value, err)
+			continue
+		}
+		if c.Hex() != test.expected {
+			t.Errorf("Parse failed: Should parse '%s' as %s but got: %s", test.value, test.expected, c.Hex())
+		}
+	}
+}
+
+func TestColor_Parse_Invalid_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	tests := []string{"orange", "#ff88", "hsv(400, 50%, 50%)", "hsv(30, 150%, 50%)", "500K"}
+
+	for _, test := range tests {
+		//	Act
+		_, err := Parse(test)
+
+		//	Assert
+		if err == nil {
+			t.Errorf("Parse - Should return error for '%s', but got none", test)
+		}
+	}
+}
+
+func TestColor_FromKelvin_WarmIsRedder_Successful(t *testing.T) {
+
+	//	Act
+	warm := FromKelvin(2700)
+	cool := FromKelvin(9000)
+
+	//	Assert
+	if warm.R != 1 || warm.B >= warm.G || cool.B != 1 || cool.R >= 1 {
+		t.Errorf("FromKelvin failed: Should be warm (red) at 2700K and cool (blue) at 9000K but got: %+v / %+v", warm, cool)
+	}
+}
+
+func TestColor_Blend_HSV_GoesAroundTheHueWheel(t *testing.T) {
+
+	//	Arrange
+	red := RGB{R: 1}
+	blue := RGB{B: 1}
+
+	//	Act
+	middle := Blend(red, blue, 0.5, SpaceHSV)
+	h, s, v := middle.HSV()
+
+	//	Assert
+	if math.Abs(h-300) > 0.001 || s != 1 || v != 1 {
+		t.Errorf("Blend failed: Should go the short way through magenta (hue 300) at full saturation but got: %v, %v, %v", h, s, v)
+	}
+
+	if Blend(red, blue, 0.5, SpaceRGB).Hex() != "#800080" {
+		t.Errorf("Blend failed: Should blend channel by channel in RGB but got: %s", Blend(red, blue, 0.5, SpaceRGB).Hex())
+	}
+}
+
+func TestColor_Blend_Perceptual_EndsAtTarget(t *testing.T) {
+
+	//	Arrange
+	from := RGB{R: 1, G: 0.5}
+	to := RGB{G: 0.25, B: 1}
+
+	//	Act
+	start := Blend(from, to, 0, SpacePerceptual)
+	end := Blend(from, to, 1, SpacePerceptual)
+
+	//	Assert
+	if start.Hex() != from.Hex() || end.Hex() != to.Hex() {
+		t.Errorf("Blend failed: Should start and end at the colors but got: %s / %s", start.Hex(), end.Hex())
+	}
+}
+
+func TestColor_Emitters_FixtureTypes_Successful(t *testing.T) {
+
+	//	Arrange
+	c := RGB{R: 1, G: 0.75, B: 0.5}
+	rgbw := map[string]bool{data2.AttributeRed: true, data2.AttributeGreen: true, data2.AttributeBlue: true, data2.AttributeWhite: true}
+	cmy := map[string]bool{data2.AttributeCyan: true, data2.AttributeMagenta: true, data2.AttributeYellow: true}
+
+	//	Act
+	rgbwLevels, rgbwErr := c.Emitters(rgbw)
+	cmyLevels, cmyErr := c.Emitters(cmy)
+	_, noneErr := c.Emitters(map[string]bool{data2.AttributeDimmer: true})
+
+	//	Assert
+	if rgbwErr != nil || rgbwLevels[data2.AttributeWhite] != 0.5 || rgbwLevels[data2.AttributeRed] != 0.5 || rgbwLevels[data2.AttributeGreen] != 0.25 || rgbwLevels[data2.AttributeBlue] != 0 {
+		t.Errorf("Emitters failed: Should extract white for an RGBW fixture but got: %v (%v)", rgbwLevels, rgbwErr)
+	}
+
+	if cmyErr != nil || cmyLevels[data2.AttributeCyan] != 0 || cmyLevels[data2.AttributeMagenta] != 0.25 || cmyLevels[data2.AttributeYellow] != 0.5 {
+		t.Errorf("Emitters failed: Should subtract the color for a CMY fixture but got: %v (%v)", cmyLevels, cmyErr)
+	}
+
+	if noneErr == nil {
+		t.Errorf("Emitters - Should return error for a fixture without color emitters, but got none")
+	}
+}
+
+func TestColor_Emitters_UV_IsLeftAlone(t *testing.T) {
+
+	//	Arrange
+	c := RGB{R: 0.5, G: 0, B: 1}
+	rgbuv := map[string]bool{data2.AttributeRed: true, data2.AttributeGreen: true, data2.AttributeBlue: true, data2.AttributeUV: true}
+
+	//	Act
+	levels, err := c.Emitters(rgbuv)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("Emitters - Should not return error, but got: %s", err)
+	}
+
+	if _, ok := levels[data2.AttributeUV]; ok {
+		t.Errorf("Emitters failed: Should leave the UV emitter alone but got: %v", levels)
+	}
+
+	if levels[data2.AttributeRed] != 0.5 || levels[data2.AttributeGreen] != 0 || levels[data2.AttributeBlue] != 1 {
+		t.Errorf("Emitters failed: Should set the RGB emitters but got: %v", levels)
+	}
+}
@@ -0,0 +1,275 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/tidwall/buntdb"
+)
+
+// CueList is an ordered list of cues that are run one at a time by an
+// operator (GO, BACK or GOTO a cue) instead of playing straight through like
+// a timeline
+type CueList struct {
+	ID            string    `json:"id"`                // Unique cue list ID
+	Created       time.Time `json:"created"`           // Cue list create time
+	Name          string    `json:"name"`              // Unique cue list name
+	USBDevicePath string    `json:"devpath,omitempty"` // The USB device to run the cue list on.  Optional.  If not set, uses the default
+	Cues          []Cue     `json:"cues"`              // The cues, in order
+}
+
+// Cue is a single look in a cue list, and how to get to it
+type Cue struct {
+	Number     float64        `json:"number"`               // Cue number (like 1, 2 or 2.5).  Must go up through the list.  If not set, it's one more than the cue before it
+	Name       string         `json:"name,omitempty"`       // Cue name (optional)
+	Channels   []ChannelValue `json:"channels,omitempty"`   // Channel information to set for the cue (optional) Channels, fixtures or presets are required
+	Fixtures   []FixtureValue `json:"fixtures,omitempty"`   // Fixture attributes to set for the cue (optional)
+	Presets    []string       `json:"presets,omitempty"`    // Names of presets whose channels to set for the cue (optional)
+	FadeIn     int            `json:"fadein,omitempty"`     // How long channels going up take to fade in milliseconds (optional) If not set, they snap
+	FadeOut    int            `json:"fadeout,omitempty"`    // How long channels going down take to fade in milliseconds (optional) If not set, they snap
+	Delay      int            `json:"delay,omitempty"`      // How long to wait after GO before the cue starts fading in milliseconds (optional)
+	AutoFollow bool           `json:"autofollow,omitempty"` // Run the next cue automatically once this one has finished fading (optional)
+	FollowTime int            `json:"followtime,omitempty"` // How long to wait before running the next cue automatically in milliseconds (optional)
+}
+
+// checkCueList numbers a cue list's cues, makes sure their channels are in
+// range and makes sure the cue list has a unique name
+func (store Manager) checkCueList(cueList *CueList) error {
+
+	if strings.TrimSpace(cueList.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if len(cueList.Cues) < 1 {
+		return fmt.Errorf("cues must contain at least one item")
+	}
+
+	//	Number any cues that don't have a number, and make sure the numbers go up
+	last := 0.0
+	for i := range cueList.Cues {
+		cue := &cueList.Cues[i]
+		if cue.Number == 0 {
+			cue.Number = math.Floor(last) + 1
+		}
+
+		if cue.Number <= last {
+			return fmt.Errorf("cue %v must have a number greater than the cue before it (%v)", cue.Number, last)
+		}
+		last = cue.Number
+
+		if len(cue.Channels) == 0 && len(cue.Fixtures) == 0 && len(cue.Presets) == 0 {
+			return fmt.Errorf("cue %v needs channels, fixtures or presets", cue.Number)
+		}
+
+		if cue.FadeIn < 0 || cue.FadeOut < 0 || cue.Delay < 0 || cue.FollowTime < 0 {
+			return fmt.Errorf("cue %v times can't be negative", cue.Number)
+		}
+
+		for _, channel := range cue.Channels {
+			if channel.Channel < 1 || channel.Channel > 512 {
+				return fmt.Errorf("cue %v channel %v must be between 1 and 512", cue.Number, channel.Channel)
+			}
+			if channel.Fine != 0 && (channel.Fine < 1 || channel.Fine > 512) {
+				return fmt.Errorf("cue %v fine channel %v must be between 1 and 512", cue.Number, channel.Fine)
+			}
+		}
+	}
+
+	//	Make sure the name is unique
+	cueLists, err := store.GetAllCueLists()
+	if err != nil {
+		return err
+	}
+
+	for _, other := range cueLists {
+		if other.ID != cueList.ID && strings.EqualFold(other.Name, cueList.Name) {
+			return fmt.Errorf("there is already a cue list named '%s'", other.Name)
+		}
+	}
+
+	return nil
+}
+
+// Cue finds a cue by its number
+func (cueList CueList) Cue(number float64) (Cue, bool) {
+	for _, cue := range cueList.Cues {
+		if cue.Number == number {
+			return cue, true
+		}
+	}
+	return Cue{}, false
+}
+
+// AddCueList adds a cue list to the system
+func (store Manager) AddCueList(name, devpath string, cues []Cue) (CueList, error) {
+
+	//	Our return item
+	retval := CueList{}
+
+	//	Create our new cue list
+	newCueList := CueList{
+		ID:            xid.New().String(), // Generate a new id
+		Created:       time.Now(),
+		Name:          name,
+		USBDevicePath: devpath,
+		Cues:          cues,
+	}
+
+	//	Make sure it makes sense
+	if err := store.checkCueList(&newCueList); err != nil {
+		return retval, err
+	}
+
+	//	Serialize to JSON format
+	encoded, err := json.Marshal(newCueList)
+	if err != nil {
+		return retval, fmt.Errorf("problem serializing the data: %s", err)
+	}
+
+	//	Save it to the database:
+	err = store.systemdb.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(GetKey("CueList", newCueList.ID), string(encoded), &buntdb.SetOptions{})
+		return err
+	})
+
+	//	If there was an error saving the data, report it:
+	if err != nil {
+		return retval, fmt.Errorf("problem saving the cue list: %s", err)
+	}
+
+	//	Set our retval:
+	retval = newCueList
+
+	//	Return our data:
+	return retval, nil
+}
+
+// UpdateCueList updates a cue list in the system
+func (store Manager) UpdateCueList(updatedCueList CueList) (CueList, error) {
+
+	//	Our return item
+	retval := CueList{}
+
+	//	Make sure it makes sense
+	if err := store.checkCueList(&updatedCueList); err != nil {
+		return retval, err
+	}
+
+	//	Serialize to JSON format
+	encoded, err := json.Marshal(updatedCueList)
+	if err != nil {
+		return retval, fmt.Errorf("problem serializing the data: %s", err)
+	}
+
+	//	Save it to the database:
+	err = store.systemdb.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(GetKey("CueList", updatedCueList.ID), string(encoded), &buntdb.SetOptions{})
+		return err
+	})
+
+	//	If there was an error saving the data, report it:
+	if err != nil {
+		return retval, fmt.Errorf("problem saving the cue list: %s", err)
+	}
+
+	//	Set our retval:
+	retval = updatedCueList
+
+	//	Return our data:
+	return retval, nil
+}
+
+// GetCueList gets information about a single cue list in the system based on its id
+func (store Manager) GetCueList(id string) (CueList, error) {
+	//	Our return item
+	retval := CueList{}
+
+	//	Find the item:
+	err := store.systemdb.View(func(tx *buntdb.Tx) error {
+
+		val, err := tx.Get(GetKey("CueList", id))
+		if err != nil {
+			return err
+		}
+
+		if len(val) > 0 {
+			//	Unmarshal data into our item
+			if err := json.Unmarshal([]byte(val), &retval); err != nil {
+				return err
+			}
+		}
+
+		//	If we get to this point and there is no error...
+		return nil
+	})
+
+	//	If there was an error, report it:
+	if err != nil {
+		return retval, fmt.Errorf("problem getting the cue list: %s", err)
+	}
+
+	//	Return our data:
+	return retval, nil
+}
+
+// GetAllCueLists gets all cue lists in the system
+func (store Manager) GetAllCueLists() ([]CueList, error) {
+	//	Our return item
+	retval := []CueList{}
+
+	//	Set our prefix
+	prefix := GetKey("CueList")
+
+	//	Iterate over our values:
+	err := store.systemdb.View(func(tx *buntdb.Tx) error {
+		tx.Descend(prefix, func(key, val string) bool {
+
+			if len(val) > 0 {
+				//	Create our item:
+				item := CueList{}
+
+				//	Unmarshal data into our item
+				bval := []byte(val)
+				if err := json.Unmarshal(bval, &item); err != nil {
+					return false
+				}
+
+				//	Add to the array of returned cue lists:
+				retval = append(retval, item)
+			}
+
+			return true
+		})
+		return nil
+	})
+
+	//	If there was an error, report it:
+	if err != nil {
+		return retval, fmt.Errorf("problem getting the list of cue lists: %s", err)
+	}
+
+	//	Return our data:
+	return retval, nil
+}
+
+// DeleteCueList deletes a cue list from the system
+func (store Manager) DeleteCueList(id string) error {
+
+	//	Remove it from the database:
+	err := store.systemdb.Update(func(tx *buntdb.Tx) error {
+		_, err := tx.Delete(GetKey("CueList", id))
+		return err
+	})
+
+	//	If there was an error removing the data, report it:
+	if err != nil {
+		return fmt.Errorf("problem removing the cue list: %s", err)
+	}
+
+	//	Return our data:
+	return nil
+}
@@ -0,0 +1,97 @@
+package data_test
+
+import (
+	data2 "github.com/danesparza/fxdmx/internal/data"
+	"os"
+	"testing"
+)
+
+func TestCueList_AddCueList_NumbersCues_Successful(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	cues := []data2.Cue{
+		{Name: "Preshow", Channels: []data2.ChannelValue{{Channel: 1, Value: 80}}},
+		{Number: 2.5, Name: "Lights up", Channels: []data2.ChannelValue{{Channel: 1, Value: 255}}, FadeIn: 3000},
+		{Name: "Blackout", Channels: []data2.ChannelValue{{Channel: 1, Value: 0}}},
+	}
+
+	//	Act
+	newCueList, err := db.AddCueList("Act 1", "/dev/ttyUSB0", cues)
+	gotCueList, _ := db.GetCueList(newCueList.ID)
+	_, found := gotCueList.Cue(2.5)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("AddCueList - Should add cue list without error, but got: %s", err)
+	}
+
+	if gotCueList.Cues[0].Number != 1 || gotCueList.Cues[2].Number != 3 || !found {
+		t.Errorf("AddCueList failed: Should number the cues that don't have numbers but got: %+v", gotCueList.Cues)
+	}
+
+	if gotCueList.USBDevicePath != "/dev/ttyUSB0" {
+		t.Errorf("AddCueList failed: Should save the device path but got: %s", gotCueList.USBDevicePath)
+	}
+}
+
+func TestCueList_AddCueList_Invalid_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	look := []data2.ChannelValue{{Channel: 1, Value: 255}}
+	db.AddCueList("Act 1", "", []data2.Cue{{Channels: look}})
+
+	//	Act
+	_, emptyErr := db.AddCueList("Act 2", "", []data2.Cue{})
+	_, orderErr := db.AddCueList("Act 2", "", []data2.Cue{{Number: 5, Channels: look}, {Number: 4, Channels: look}})
+	_, lookErr := db.AddCueList("Act 2", "", []data2.Cue{{Number: 1}})
+	_, duplicateErr := db.AddCueList("act 1", "", []data2.Cue{{Channels: look}})
+	_, channelErr := db.AddCueList("Act 2", "", []data2.Cue{{Channels: []data2.ChannelValue{{Channel: 600, Value: 255}}}})
+	_, fineErr := db.AddCueList("Act 2", "", []data2.Cue{{Channels: []data2.ChannelValue{{Channel: 1, Fine: -1, Value16: 300}}}})
+
+	//	Assert
+	if emptyErr == nil {
+		t.Errorf("AddCueList - Should return error for a cue list without cues, but got none")
+	}
+
+	if orderErr == nil {
+		t.Errorf("AddCueList - Should return error for cue numbers that go down, but got none")
+	}
+
+	if lookErr == nil {
+		t.Errorf("AddCueList - Should return error for a cue without a look, but got none")
+	}
+
+	if duplicateErr == nil {
+		t.Errorf("AddCueList - Should return error for a duplicate cue list name, but got none")
+	}
+
+	if channelErr == nil {
+		t.Errorf("AddCueList - Should return error for an out of range channel, but got none")
+	}
+
+	if fineErr == nil {
+		t.Errorf("AddCueList - Should return error for an out of range fine channel, but got none")
+	}
+}
@@ -0,0 +1,264 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/tidwall/buntdb"
+)
+
+// Fixture is a fixture instance patched to a universe address
+type Fixture struct {
+	ID        string    `json:"id"`        // Unique fixture ID
+	Created   time.Time `json:"created"`   // Fixture create time
+	Name      string    `json:"name"`      // Unique fixture name (like 'Stage Left Par').  Timelines use this to refer to the fixture
+	ProfileID string    `json:"profileid"` // The fixture profile
+	Mode      string    `json:"mode"`      // The fixture profile mode.  Optional if the profile only has one mode
+	Universe  int       `json:"universe"`  // The universe the fixture is patched to.  Optional.  Defaults to 1 (the only universe supported)
+	Address   int       `json:"address"`   // The DMX start address (1-512)
+	Footprint int       `json:"footprint"` // The number of channels the fixture uses (from the profile mode)
+}
+
+// LastChannel is the last DMX channel used by the fixture
+func (f Fixture) LastChannel() int {
+	return f.Address + f.Footprint - 1
+}
+
+// Channel returns the DMX channel for the given (1 based) fixture channel offset
+func (f Fixture) Channel(offset int) int {
+	return f.Address + offset - 1
+}
+
+// checkPatch makes sure a fixture can be patched where it says it is: the
+// profile and mode exist, the name is unique, it fits in the universe and it
+// doesn't overlap any other patched fixture.  It returns the fixture with its
+// mode and footprint filled in from the profile.  Run it in the transaction
+// that saves the fixture, so nothing can be patched over it in between.
+func checkPatch(tx *buntdb.Tx, fixture Fixture) (Fixture, error) {
+
+	if strings.TrimSpace(fixture.Name) == "" {
+		return fixture, fmt.Errorf("name is required")
+	}
+
+	if fixture.Universe == 0 {
+		fixture.Universe = 1
+	}
+	//	Everything plays on a single output device, so only universe 1 can be
+	//	patched until universes are routed to outputs
+	if fixture.Universe != 1 {
+		return fixture, fmt.Errorf("universe %v is invalid (only universe 1 is supported)", fixture.Universe)
+	}
+
+	//	Find the profile and mode
+	profile, err := getFixtureProfile(tx, fixture.ProfileID)
+	if err != nil {
+		return fixture, fmt.Errorf("fixture profile '%s' was not found", fixture.ProfileID)
+	}
+
+	if strings.TrimSpace(fixture.Mode) == "" && len(profile.Modes) == 1 {
+		fixture.Mode = profile.Modes[0].Name
+	}
+
+	mode, found := profile.Mode(fixture.Mode)
+	if !found {
+		return fixture, fmt.Errorf("fixture profile '%s %s' doesn't have a mode named '%s'", profile.Manufacturer, profile.Model, fixture.Mode)
+	}
+	fixture.Mode = mode.Name
+	fixture.Footprint = mode.Footprint()
+
+	//	Make sure it fits in the universe
+	if fixture.Address < 1 || fixture.LastChannel() > 512 {
+		return fixture, fmt.Errorf("fixture '%s' (channels %v-%v) doesn't fit in channels 1-512", fixture.Name, fixture.Address, fixture.LastChannel())
+	}
+
+	//	Make sure it doesn't collide with any other fixture
+	fixtures, err := getFixtures(tx)
+	if err != nil {
+		return fixture, err
+	}
+
+	for _, other := range fixtures {
+		if other.ID == fixture.ID {
+			continue
+		}
+
+		if strings.EqualFold(other.Name, fixture.Name) {
+			return fixture, fmt.Errorf("there is already a fixture named '%s'", other.Name)
+		}
+
+		//	Use the other fixture's footprint from its profile as it is now
+		if otherProfile, err := getFixtureProfile(tx, other.ProfileID); err == nil {
+			if otherMode, found := otherProfile.Mode(other.Mode); found {
+				other.Footprint = otherMode.Footprint()
+			}
+		}
+
+		if other.Universe == fixture.Universe && fixture.Address <= other.LastChannel() && other.Address <= fixture.LastChannel() {
+			return fixture, fmt.Errorf("fixture '%s' (channels %v-%v) overlaps fixture '%s' (channels %v-%v) in universe %v",
+				fixture.Name, fixture.Address, fixture.LastChannel(), other.Name, other.Address, other.LastChannel(), fixture.Universe)
+		}
+	}
+
+	return fixture, nil
+}
+
+// savePatch checks a fixture can be patched and saves it (in the same transaction)
+func (store Manager) savePatch(fixture Fixture) (Fixture, error) {
+
+	var checkErr error
+	err := store.systemdb.Update(func(tx *buntdb.Tx) error {
+		fixture, checkErr = checkPatch(tx, fixture)
+		if checkErr != nil {
+			return checkErr
+		}
+
+		//	Serialize to JSON format
+		encoded, err := json.Marshal(fixture)
+		if err != nil {
+			return err
+		}
+
+		_, _, err = tx.Set(GetKey("Fixture", fixture.ID), string(encoded), &buntdb.SetOptions{})
+		return err
+	})
+
+	//	If it couldn't be patched, say why
+	if checkErr != nil {
+		return Fixture{}, checkErr
+	}
+
+	//	If there was an error saving the data, report it:
+	if err != nil {
+		return Fixture{}, fmt.Errorf("problem saving the fixture: %s", err)
+	}
+
+	return fixture, nil
+}
+
+// AddFixture patches a new fixture to a universe address
+func (store Manager) AddFixture(name, profileID, mode string, universe, address int) (Fixture, error) {
+
+	//	Create our new fixture (and make sure it can be patched)
+	return store.savePatch(Fixture{
+		ID:        xid.New().String(), // Generate a new id
+		Created:   time.Now(),
+		Name:      name,
+		ProfileID: profileID,
+		Mode:      mode,
+		Universe:  universe,
+		Address:   address,
+	})
+}
+
+// UpdateFixture updates (re-patches) a fixture in the system
+func (store Manager) UpdateFixture(updatedFixture Fixture) (Fixture, error) {
+
+	//	Make sure it can be patched and save it
+	return store.savePatch(updatedFixture)
+}
+
+// GetFixture gets information about a single fixture in the system based on its id
+func (store Manager) GetFixture(id string) (Fixture, error) {
+	//	Our return item
+	retval := Fixture{}
+
+	//	Find the item:
+	err := store.systemdb.View(func(tx *buntdb.Tx) error {
+
+		val, err := tx.Get(GetKey("Fixture", id))
+		if err != nil {
+			return err
+		}
+
+		if len(val) > 0 {
+			//	Unmarshal data into our item
+			if err := json.Unmarshal([]byte(val), &retval); err != nil {
+				return err
+			}
+		}
+
+		//	If we get to this point and there is no error...
+		return nil
+	})
+
+	//	If there was an error, report it:
+	if err != nil {
+		return retval, fmt.Errorf("problem getting the fixture: %s", err)
+	}
+
+	//	Return our data:
+	return retval, nil
+}
+
+// GetAllFixtures gets all patched fixtures in the system
+func (store Manager) GetAllFixtures() ([]Fixture, error) {
+	//	Our return item
+	retval := []Fixture{}
+
+	//	Iterate over our values:
+	err := store.systemdb.View(func(tx *buntdb.Tx) error {
+		var err error
+		retval, err = getFixtures(tx)
+		return err
+	})
+
+	//	If there was an error, report it:
+	if err != nil {
+		return retval, fmt.Errorf("problem getting the list of fixtures: %s", err)
+	}
+
+	//	Return our data:
+	return retval, nil
+}
+
+// getFixtures gets all patched fixtures in a transaction
+func getFixtures(tx *buntdb.Tx) ([]Fixture, error) {
+	//	Our return item
+	retval := []Fixture{}
+
+	//	Set our prefix
+	prefix := GetKey("Fixture")
+
+	//	Iterate over our values:
+	var err error
+	tx.Descend(prefix, func(key, val string) bool {
+
+		if len(val) > 0 {
+			//	Create our item:
+			item := Fixture{}
+
+			//	Unmarshal data into our item
+			if err = json.Unmarshal([]byte(val), &item); err != nil {
+				return false
+			}
+
+			//	Add to the array of returned fixtures:
+			retval = append(retval, item)
+		}
+
+		return true
+	})
+
+	return retval, err
+}
+
+// DeleteFixture deletes a fixture from the system
+func (store Manager) DeleteFixture(id string) error {
+
+	//	Remove it from the database:
+	err := store.systemdb.Update(func(tx *buntdb.Tx) error {
+		_, err := tx.Delete(GetKey("Fixture", id))
+		return err
+	})
+
+	//	If there was an error removing the data, report it:
+	if err != nil {
+		return fmt.Errorf("problem removing the fixture: %s", err)
+	}
+
+	//	Return our data:
+	return nil
+}
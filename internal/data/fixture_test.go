@@ -0,0 +1,235 @@
+package data_test
+
+import (
+	"fmt"
+	data2 "github.com/danesparza/fxdmx/internal/data"
+	"os"
+	"sync"
+	"testing"
+)
+
+func TestFixture_AddFixture_ValidPatch_Successful(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	profile, _ := db.AddFixtureProfile("Unit test lighting", "Par 64", getTestProfileModes())
+
+	//	Act
+	newFixture, err := db.AddFixture("Stage Left Par", profile.ID, "7 channel", 0, 17)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("AddFixture - Should add fixture without error, but got: %s", err)
+	}
+
+	if newFixture.ID == "" || newFixture.Universe != 1 || newFixture.Footprint != 7 || newFixture.LastChannel() != 23 {
+		t.Errorf("AddFixture failed: Should set an id, the default universe and the footprint but got: %+v", newFixture)
+	}
+}
+
+func TestFixture_AddFixture_Overlap_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	profile, _ := db.AddFixtureProfile("Unit test lighting", "Par 64", getTestProfileModes())
+	db.AddFixture("Stage Left Par", profile.ID, "7 channel", 1, 17)
+
+	//	Act
+	_, overlapErr := db.AddFixture("Stage Right Par", profile.ID, "7 channel", 1, 23)
+	_, otherUniverseErr := db.AddFixture("Stage Right Par", profile.ID, "7 channel", 2, 23)
+	_, adjacentErr := db.AddFixture("Center Par", profile.ID, "3 channel", 1, 24)
+
+	//	Assert
+	if overlapErr == nil {
+		t.Errorf("AddFixture - Should return error for an overlapping fixture, but got none")
+	}
+
+	if otherUniverseErr == nil {
+		t.Errorf("AddFixture - Should return error for another universe (everything plays on one device), but got none")
+	}
+
+	if adjacentErr != nil {
+		t.Errorf("AddFixture - Should patch an adjacent fixture without error, but got: %s", adjacentErr)
+	}
+}
+
+func TestFixture_AddFixture_OverlapAfterProfileEdit_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	profile, _ := db.AddFixtureProfile("Unit test lighting", "Par 64", getTestProfileModes())
+	db.AddFixture("Stage Left Par", profile.ID, "3 channel", 1, 17)
+
+	//	The 3 channel mode gets a dimmer (so the fixture now uses channels 17-20)
+	profile.Modes[0].Channels = append(profile.Modes[0].Channels, data2.FixtureChannel{Name: "Dimmer", Attribute: data2.AttributeDimmer})
+	db.UpdateFixtureProfile(profile)
+
+	//	Act
+	_, err = db.AddFixture("Stage Right Par", profile.ID, "3 channel", 1, 20)
+
+	//	Assert
+	if err == nil {
+		t.Errorf("AddFixture - Should return error for a fixture overlapping the edited profile's footprint, but got none")
+	}
+}
+
+func TestFixture_AddFixture_AtTheSameTime_OnlyOnePatched(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	profile, _ := db.AddFixtureProfile("Unit test lighting", "Par 64", getTestProfileModes())
+
+	//	Act
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			db.AddFixture(fmt.Sprintf("Par %v", i), profile.ID, "7 channel", 1, 17+i%3)
+		}(i)
+	}
+	wg.Wait()
+
+	gotFixtures, _ := db.GetAllFixtures()
+
+	//	Assert
+	if len(gotFixtures) != 1 {
+		t.Errorf("AddFixture failed: Should patch only one of the overlapping fixtures but got: %v", len(gotFixtures))
+	}
+}
+
+func TestFixture_AddFixture_PastChannel512_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	profile, _ := db.AddFixtureProfile("Unit test lighting", "Par 64", getTestProfileModes())
+
+	//	Act
+	_, err = db.AddFixture("Stage Left Par", profile.ID, "7 channel", 1, 507)
+
+	//	Assert
+	if err == nil {
+		t.Errorf("AddFixture - Should return error for a fixture past channel 512, but got none")
+	}
+}
+
+func TestFixture_AddFixture_DuplicateName_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	profile, _ := db.AddFixtureProfile("Unit test lighting", "Par 64", getTestProfileModes())
+	db.AddFixture("Stage Left Par", profile.ID, "3 channel", 1, 1)
+
+	//	Act
+	_, err = db.AddFixture("stage left par", profile.ID, "3 channel", 1, 100)
+
+	//	Assert
+	if err == nil {
+		t.Errorf("AddFixture - Should return error for a duplicate fixture name, but got none")
+	}
+}
+
+func TestFixture_UpdateAndDeleteFixture_Successful(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	profile, _ := db.AddFixtureProfile("Unit test lighting", "Par 64", getTestProfileModes())
+	newFixture1, _ := db.AddFixture("Stage Left Par", profile.ID, "7 channel", 1, 17)
+	newFixture2, _ := db.AddFixture("Stage Right Par", profile.ID, "7 channel", 1, 24)
+
+	//	Act
+	newFixture1.Address = 18
+	newFixture1.Mode = "3 channel"
+	_, err = db.UpdateFixture(newFixture1)
+	gotFixture, _ := db.GetFixture(newFixture1.ID)
+
+	deleteErr := db.DeleteFixture(newFixture2.ID)
+	gotFixtures, _ := db.GetAllFixtures()
+
+	//	Assert
+	if err != nil {
+		t.Errorf("UpdateFixture - Should re-patch over its own channels without error, but got: %s", err)
+	}
+
+	if gotFixture.Address != 18 || gotFixture.Footprint != 3 {
+		t.Errorf("UpdateFixture failed: Should get the updated address and footprint but got: %+v", gotFixture)
+	}
+
+	if deleteErr != nil {
+		t.Errorf("DeleteFixture - Should delete fixture without error, but got: %s", deleteErr)
+	}
+
+	if len(gotFixtures) != 1 {
+		t.Errorf("DeleteFixture failed: Should remove an item but got: %v", len(gotFixtures))
+	}
+}
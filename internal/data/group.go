@@ -0,0 +1,227 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/tidwall/buntdb"
+)
+
+// FixtureGroup is a named, ordered group of patched fixtures
+type FixtureGroup struct {
+	ID       string    `json:"id"`       // Unique group ID
+	Created  time.Time `json:"created"`  // Group create time
+	Name     string    `json:"name"`     // Unique group name (like 'wash' or 'uplights').  Timelines use this to refer to the group
+	Fixtures []string  `json:"fixtures"` // The fixture ids in the group (in group order)
+}
+
+// checkGroup makes sure a group has a unique name and that every fixture in it is patched
+func (store Manager) checkGroup(group FixtureGroup) error {
+
+	if strings.TrimSpace(group.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if len(group.Fixtures) < 1 {
+		return fmt.Errorf("fixtures must contain at least one item")
+	}
+
+	//	Make sure every fixture is patched (and only listed once)
+	seen := map[string]bool{}
+	for _, id := range group.Fixtures {
+		if seen[id] {
+			return fmt.Errorf("fixture '%s' is listed more than once", id)
+		}
+		seen[id] = true
+
+		if _, err := store.GetFixture(id); err != nil {
+			return fmt.Errorf("fixture '%s' was not found", id)
+		}
+	}
+
+	//	Make sure the name is unique
+	groups, err := store.GetAllFixtureGroups()
+	if err != nil {
+		return err
+	}
+
+	for _, other := range groups {
+		if other.ID != group.ID && strings.EqualFold(other.Name, group.Name) {
+			return fmt.Errorf("there is already a group named '%s'", other.Name)
+		}
+	}
+
+	return nil
+}
+
+// AddFixtureGroup adds a fixture group to the system
+func (store Manager) AddFixtureGroup(name string, fixtures []string) (FixtureGroup, error) {
+
+	//	Our return item
+	retval := FixtureGroup{}
+
+	//	Create our new group
+	newGroup := FixtureGroup{
+		ID:       xid.New().String(), // Generate a new id
+		Created:  time.Now(),
+		Name:     name,
+		Fixtures: fixtures,
+	}
+
+	//	Make sure it makes sense
+	if err := store.checkGroup(newGroup); err != nil {
+		return retval, err
+	}
+
+	//	Serialize to JSON format
+	encoded, err := json.Marshal(newGroup)
+	if err != nil {
+		return retval, fmt.Errorf("problem serializing the data: %s", err)
+	}
+
+	//	Save it to the database:
+	err = store.systemdb.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(GetKey("FixtureGroup", newGroup.ID), string(encoded), &buntdb.SetOptions{})
+		return err
+	})
+
+	//	If there was an error saving the data, report it:
+	if err != nil {
+		return retval, fmt.Errorf("problem saving the fixture group: %s", err)
+	}
+
+	//	Set our retval:
+	retval = newGroup
+
+	//	Return our data:
+	return retval, nil
+}
+
+// UpdateFixtureGroup updates a fixture group in the system
+func (store Manager) UpdateFixtureGroup(updatedGroup FixtureGroup) (FixtureGroup, error) {
+
+	//	Our return item
+	retval := FixtureGroup{}
+
+	//	Make sure it makes sense
+	if err := store.checkGroup(updatedGroup); err != nil {
+		return retval, err
+	}
+
+	//	Serialize to JSON format
+	encoded, err := json.Marshal(updatedGroup)
+	if err != nil {
+		return retval, fmt.Errorf("problem serializing the data: %s", err)
+	}
+
+	//	Save it to the database:
+	err = store.systemdb.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(GetKey("FixtureGroup", updatedGroup.ID), string(encoded), &buntdb.SetOptions{})
+		return err
+	})
+
+	//	If there was an error saving the data, report it:
+	if err != nil {
+		return retval, fmt.Errorf("problem saving the fixture group: %s", err)
+	}
+
+	//	Set our retval:
+	retval = updatedGroup
+
+	//	Return our data:
+	return retval, nil
+}
+
+// GetFixtureGroup gets information about a single fixture group in the system based on its id
+func (store Manager) GetFixtureGroup(id string) (FixtureGroup, error) {
+	//	Our return item
+	retval := FixtureGroup{}
+
+	//	Find the item:
+	err := store.systemdb.View(func(tx *buntdb.Tx) error {
+
+		val, err := tx.Get(GetKey("FixtureGroup", id))
+		if err != nil {
+			return err
+		}
+
+		if len(val) > 0 {
+			//	Unmarshal data into our item
+			if err := json.Unmarshal([]byte(val), &retval); err != nil {
+				return err
+			}
+		}
+
+		//	If we get to this point and there is no error...
+		return nil
+	})
+
+	//	If there was an error, report it:
+	if err != nil {
+		return retval, fmt.Errorf("problem getting the fixture group: %s", err)
+	}
+
+	//	Return our data:
+	return retval, nil
+}
+
+// GetAllFixtureGroups gets all fixture groups in the system
+func (store Manager) GetAllFixtureGroups() ([]FixtureGroup, error) {
+	//	Our return item
+	retval := []FixtureGroup{}
+
+	//	Set our prefix
+	prefix := GetKey("FixtureGroup")
+
+	//	Iterate over our values:
+	err := store.systemdb.View(func(tx *buntdb.Tx) error {
+		tx.Descend(prefix, func(key, val string) bool {
+
+			if len(val) > 0 {
+				//	Create our item:
+				item := FixtureGroup{}
+
+				//	Unmarshal data into our item
+				bval := []byte(val)
+				if err := json.Unmarshal(bval, &item); err != nil {
+					return false
+				}
+
+				//	Add to the array of returned groups:
+				retval = append(retval, item)
+			}
+
+			return true
+		})
+		return nil
+	})
+
+	//	If there was an error, report it:
+	if err != nil {
+		return retval, fmt.Errorf("problem getting the list of fixture groups: %s", err)
+	}
+
+	//	Return our data:
+	return retval, nil
+}
+
+// DeleteFixtureGroup deletes a fixture group from the system
+func (store Manager) DeleteFixtureGroup(id string) error {
+
+	//	Remove it from the database:
+	err := store.systemdb.Update(func(tx *buntdb.Tx) error {
+		_, err := tx.Delete(GetKey("FixtureGroup", id))
+		return err
+	})
+
+	//	If there was an error removing the data, report it:
+	if err != nil {
+		return fmt.Errorf("problem removing the fixture group: %s", err)
+	}
+
+	//	Return our data:
+	return nil
+}
@@ -0,0 +1,122 @@
+package data_test
+
+import (
+	data2 "github.com/danesparza/fxdmx/internal/data"
+	"os"
+	"testing"
+)
+
+func TestGroup_AddFixtureGroup_ValidGroup_Successful(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	profile, _ := db.AddFixtureProfile("Unit test lighting", "Par 64", getTestProfileModes())
+	fixture1, _ := db.AddFixture("Uplight 1", profile.ID, "3 channel", 1, 1)
+	fixture2, _ := db.AddFixture("Uplight 2", profile.ID, "3 channel", 1, 4)
+
+	//	Act
+	newGroup, err := db.AddFixtureGroup("uplights", []string{fixture2.ID, fixture1.ID})
+	gotGroup, _ := db.GetFixtureGroup(newGroup.ID)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("AddFixtureGroup - Should add group without error, but got: %s", err)
+	}
+
+	if len(gotGroup.Fixtures) != 2 || gotGroup.Fixtures[0] != fixture2.ID {
+		t.Errorf("AddFixtureGroup failed: Should keep the fixtures in group order but got: %+v", gotGroup)
+	}
+}
+
+func TestGroup_AddFixtureGroup_Invalid_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	profile, _ := db.AddFixtureProfile("Unit test lighting", "Par 64", getTestProfileModes())
+	fixture1, _ := db.AddFixture("Uplight 1", profile.ID, "3 channel", 1, 1)
+	db.AddFixtureGroup("uplights", []string{fixture1.ID})
+
+	//	Act
+	_, unknownErr := db.AddFixtureGroup("wash", []string{"not-a-fixture"})
+	_, emptyErr := db.AddFixtureGroup("wash", []string{})
+	_, duplicateErr := db.AddFixtureGroup("Uplights", []string{fixture1.ID})
+
+	//	Assert
+	if unknownErr == nil {
+		t.Errorf("AddFixtureGroup - Should return error for a fixture that isn't patched, but got none")
+	}
+
+	if emptyErr == nil {
+		t.Errorf("AddFixtureGroup - Should return error for a group without fixtures, but got none")
+	}
+
+	if duplicateErr == nil {
+		t.Errorf("AddFixtureGroup - Should return error for a duplicate group name, but got none")
+	}
+}
+
+func TestGroup_UpdateAndDeleteFixtureGroup_Successful(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	profile, _ := db.AddFixtureProfile("Unit test lighting", "Par 64", getTestProfileModes())
+	fixture1, _ := db.AddFixture("Uplight 1", profile.ID, "3 channel", 1, 1)
+	fixture2, _ := db.AddFixture("Uplight 2", profile.ID, "3 channel", 1, 4)
+	newGroup1, _ := db.AddFixtureGroup("uplights", []string{fixture1.ID})
+	newGroup2, _ := db.AddFixtureGroup("wash", []string{fixture2.ID})
+
+	//	Act
+	newGroup1.Fixtures = append(newGroup1.Fixtures, fixture2.ID)
+	_, err = db.UpdateFixtureGroup(newGroup1)
+	gotGroup, _ := db.GetFixtureGroup(newGroup1.ID)
+
+	deleteErr := db.DeleteFixtureGroup(newGroup2.ID)
+	gotGroups, _ := db.GetAllFixtureGroups()
+
+	//	Assert
+	if err != nil {
+		t.Errorf("UpdateFixtureGroup - Should update group without error, but got: %s", err)
+	}
+
+	if len(gotGroup.Fixtures) != 2 {
+		t.Errorf("UpdateFixtureGroup failed: Should get the updated fixtures but got: %+v", gotGroup)
+	}
+
+	if deleteErr != nil {
+		t.Errorf("DeleteFixtureGroup - Should delete group without error, but got: %s", deleteErr)
+	}
+
+	if len(gotGroups) != 1 {
+		t.Errorf("DeleteFixtureGroup failed: Should remove an item but got: %v", len(gotGroups))
+	}
+}
@@ -0,0 +1,227 @@
+package data
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/tidwall/buntdb"
+)
+
+// Preset is a reusable, named set of attribute and/or channel values (like
+// 'warm white' or 'blackout').  Timelines refer to presets by name, so
+// updating a preset changes every timeline that uses it the next time it plays.
+type Preset struct {
+	ID         string                 `json:"id"`                   // Unique preset ID
+	Created    time.Time              `json:"created"`              // Preset create time
+	Name       string                 `json:"name"`                 // Unique preset name.  Timelines use this to refer to the preset
+	Attributes map[string]interface{} `json:"attributes,omitempty"` // Fixture attribute values (like 'dimmer' or 'color') to set on the fixtures or groups that use the preset
+	Channels   []ChannelValue         `json:"channels,omitempty"`   // Raw channel values to set in the frames that use the preset
+}
+
+// checkPreset makes sure a preset has a unique name and sets something
+func (store Manager) checkPreset(preset Preset) error {
+
+	if strings.TrimSpace(preset.Name) == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	if len(preset.Attributes) < 1 && len(preset.Channels) < 1 {
+		return fmt.Errorf("attributes or channels are required")
+	}
+
+	for _, channel := range preset.Channels {
+		if channel.Channel < 1 || channel.Channel > 512 {
+			return fmt.Errorf("channel %v must be between 1 and 512", channel.Channel)
+		}
+		if channel.Fine != 0 && (channel.Fine < 1 || channel.Fine > 512) {
+			return fmt.Errorf("fine channel %v must be between 1 and 512", channel.Fine)
+		}
+	}
+
+	//	Make sure the name is unique
+	presets, err := store.GetAllPresets()
+	if err != nil {
+		return err
+	}
+
+	for _, other := range presets {
+		if other.ID != preset.ID && strings.EqualFold(other.Name, preset.Name) {
+			return fmt.Errorf("there is already a preset named '%s'", other.Name)
+		}
+	}
+
+	return nil
+}
+
+// AddPreset adds a preset to the system
+func (store Manager) AddPreset(name string, attributes map[string]interface{}, channels []ChannelValue) (Preset, error) {
+
+	//	Our return item
+	retval := Preset{}
+
+	//	Create our new preset
+	newPreset := Preset{
+		ID:         xid.New().String(), // Generate a new id
+		Created:    time.Now(),
+		Name:       name,
+		Attributes: attributes,
+		Channels:   channels,
+	}
+
+	//	Make sure it makes sense
+	if err := store.checkPreset(newPreset); err != nil {
+		return retval, err
+	}
+
+	//	Serialize to JSON format
+	encoded, err := json.Marshal(newPreset)
+	if err != nil {
+		return retval, fmt.Errorf("problem serializing the data: %s", err)
+	}
+
+	//	Save it to the database:
+	err = store.systemdb.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(GetKey("Preset", newPreset.ID), string(encoded), &buntdb.SetOptions{})
+		return err
+	})
+
+	//	If there was an error saving the data, report it:
+	if err != nil {
+		return retval, fmt.Errorf("problem saving the preset: %s", err)
+	}
+
+	//	Set our retval:
+	retval = newPreset
+
+	//	Return our data:
+	return retval, nil
+}
+
+// UpdatePreset updates a preset in the system
+func (store Manager) UpdatePreset(updatedPreset Preset) (Preset, error) {
+
+	//	Our return item
+	retval := Preset{}
+
+	//	Make sure it makes sense
+	if err := store.checkPreset(updatedPreset); err != nil {
+		return retval, err
+	}
+
+	//	Serialize to JSON format
+	encoded, err := json.Marshal(updatedPreset)
+	if err != nil {
+		return retval, fmt.Errorf("problem serializing the data: %s", err)
+	}
+
+	//	Save it to the database:
+	err = store.systemdb.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(GetKey("Preset", updatedPreset.ID), string(encoded), &buntdb.SetOptions{})
+		return err
+	})
+
+	//	If there was an error saving the data, report it:
+	if err != nil {
+		return retval, fmt.Errorf("problem saving the preset: %s", err)
+	}
+
+	//	Set our retval:
+	retval = updatedPreset
+
+	//	Return our data:
+	return retval, nil
+}
+
+// GetPreset gets information about a single preset in the system based on its id
+func (store Manager) GetPreset(id string) (Preset, error) {
+	//	Our return item
+	retval := Preset{}
+
+	//	Find the item:
+	err := store.systemdb.View(func(tx *buntdb.Tx) error {
+
+		val, err := tx.Get(GetKey("Preset", id))
+		if err != nil {
+			return err
+		}
+
+		if len(val) > 0 {
+			//	Unmarshal data into our item
+			if err := json.Unmarshal([]byte(val), &retval); err != nil {
+				return err
+			}
+		}
+
+		//	If we get to this point and there is no error...
+		return nil
+	})
+
+	//	If there was an error, report it:
+	if err != nil {
+		return retval, fmt.Errorf("problem getting the preset: %s", err)
+	}
+
+	//	Return our data:
+	return retval, nil
+}
+
+// GetAllPresets gets all presets in the system
+func (store Manager) GetAllPresets() ([]Preset, error) {
+	//	Our return item
+	retval := []Preset{}
+
+	//	Set our prefix
+	prefix := GetKey("Preset")
+
+	//	Iterate over our values:
+	err := store.systemdb.View(func(tx *buntdb.Tx) error {
+		tx.Descend(prefix, func(key, val string) bool {
+
+			if len(val) > 0 {
+				//	Create our item:
+				item := Preset{}
+
+				//	Unmarshal data into our item
+				bval := []byte(val)
+				if err := json.Unmarshal(bval, &item); err != nil {
+					return false
+				}
+
+				//	Add to the array of returned presets:
+				retval = append(retval, item)
+			}
+
+			return true
+		})
+		return nil
+	})
+
+	//	If there was an error, report it:
+	if err != nil {
+		return retval, fmt.Errorf("problem getting the list of presets: %s", err)
+	}
+
+	//	Return our data:
+	return retval, nil
+}
+
+// DeletePreset deletes a preset from the system
+func (store Manager) DeletePreset(id string) error {
+
+	//	Remove it from the database:
+	err := store.systemdb.Update(func(tx *buntdb.Tx) error {
+		_, err := tx.Delete(GetKey("Preset", id))
+		return err
+	})
+
+	//	If there was an error removing the data, report it:
+	if err != nil {
+		return fmt.Errorf("problem removing the preset: %s", err)
+	}
+
+	//	Return our data:
+	return nil
+}
@@ -0,0 +1,70 @@
+package data_test
+
+import (
+	data2 "github.com/danesparza/fxdmx/internal/data"
+	"os"
+	"testing"
+)
+
+func TestPreset_AddPreset_ValidPreset_Successful(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	//	Act
+	newPreset, err := db.AddPreset("Warm white", map[string]interface{}{"dimmer": 1.0, "color": "3200K"}, nil)
+	gotPreset, _ := db.GetPreset(newPreset.ID)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("AddPreset - Should add preset without error, but got: %s", err)
+	}
+
+	if gotPreset.Name != "Warm white" || gotPreset.Attributes["color"] != "3200K" {
+		t.Errorf("AddPreset failed: Should get the preset back but got: %+v", gotPreset)
+	}
+}
+
+func TestPreset_AddPreset_Invalid_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	db.AddPreset("Blackout", nil, []data2.ChannelValue{{Channel: 1, Value: 0}})
+
+	//	Act
+	_, emptyErr := db.AddPreset("Nothing", nil, nil)
+	_, channelErr := db.AddPreset("Too far", nil, []data2.ChannelValue{{Channel: 513, Value: 0}})
+	_, duplicateErr := db.AddPreset("blackout", map[string]interface{}{"dimmer": 0.0}, nil)
+
+	//	Assert
+	if emptyErr == nil {
+		t.Errorf("AddPreset - Should return error for a preset that doesn't set anything, but got none")
+	}
+
+	if channelErr == nil {
+		t.Errorf("AddPreset - Should return error for a channel outside 1-512, but got none")
+	}
+
+	if duplicateErr == nil {
+		t.Errorf("AddPreset - Should return error for a duplicate preset name, but got none")
+	}
+}
@@ -0,0 +1,418 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/xid"
+	"github.com/tidwall/buntdb"
+)
+
+// Fixture channel attribute types
+const (
+	AttributeDimmer     = "dimmer"
+	AttributeRed        = "red"
+	AttributeGreen      = "green"
+	AttributeBlue       = "blue"
+	AttributeWhite      = "white"
+	AttributeAmber      = "amber"
+	AttributeUV         = "uv"
+	AttributeCyan       = "cyan"
+	AttributeMagenta    = "magenta"
+	AttributeYellow     = "yellow"
+	AttributeColorWheel = "colorwheel"
+	AttributeColorTemp  = "colortemp"
+	AttributePan        = "pan"
+	AttributeTilt       = "tilt"
+	AttributeSpeed      = "speed"
+	AttributeStrobe     = "strobe"
+	AttributeShutter    = "shutter"
+	AttributeGobo       = "gobo"
+	AttributeGoboRotate = "goborotate"
+	AttributePrism      = "prism"
+	AttributeFocus      = "focus"
+	AttributeZoom       = "zoom"
+	AttributeIris       = "iris"
+	AttributeFrost      = "frost"
+	AttributeFog        = "fog"
+	AttributeFan        = "fan"
+	AttributeEffect     = "effect"
+	AttributeControl    = "control"
+	AttributeGeneric    = "generic"
+)
+
+// FixtureAttributes is the list of known fixture channel attribute types
+var FixtureAttributes = []string{
+	AttributeDimmer, AttributeRed, AttributeGreen, AttributeBlue, AttributeWhite, AttributeAmber, AttributeUV,
+	AttributeCyan, AttributeMagenta, AttributeYellow, AttributeColorWheel, AttributeColorTemp,
+	AttributePan, AttributeTilt, AttributeSpeed, AttributeStrobe, AttributeShutter,
+	AttributeGobo, AttributeGoboRotate, AttributePrism, AttributeFocus, AttributeZoom, AttributeIris, AttributeFrost,
+	AttributeFog, AttributeFan, AttributeEffect, AttributeControl, AttributeGeneric,
+}
+
+// ErrProfileInUse is returned when deleting a fixture profile that patched fixtures use
+var ErrProfileInUse = errors.New("fixture profile is in use")
+
+// FixtureProfile describes a type of fixture and the channel layout of each of its modes
+type FixtureProfile struct {
+	ID           string        `json:"id"`           // Unique profile ID
+	Created      time.Time     `json:"created"`      // Profile create time
+	Manufacturer string        `json:"manufacturer"` // Fixture manufacturer
+	Model        string        `json:"model"`        // Fixture model
+	Modes        []FixtureMode `json:"modes"`        // The DMX modes (personalities) the fixture supports
+}
+
+// FixtureMode is a DMX mode (personality) of a fixture
+type FixtureMode struct {
+	Name     string           `json:"name"`     // Mode name (like '7 channel')
+	Channels []FixtureChannel `json:"channels"` // Ordered channel list.  The first channel is at the fixture start address
+}
+
+// FixtureChannel is a single channel in a fixture mode
+type FixtureChannel struct {
+	Name      string `json:"name"`           // Channel name
+	Attribute string `json:"attribute"`      // Attribute type (dimmer/red/green/blue/pan/tilt/strobe/gobo ... or generic)
+	Fine      bool   `json:"fine,omitempty"` // This is the fine (low byte) channel of a 16 bit attribute
+	Default   byte   `json:"default"`        // Default channel value
+
+	Functions []FixtureChannelFunction `json:"functions,omitempty"` // What the channel does across its DMX range (optional)
+}
+
+// FixtureChannelFunction is what a channel does over part of its DMX range
+type FixtureChannelFunction struct {
+	Name         string  `json:"name"`                   // Function name (like 'Strobe slow to fast')
+	From         byte    `json:"from"`                   // The first DMX value of the range
+	To           byte    `json:"to"`                     // The last DMX value of the range
+	PhysicalFrom float64 `json:"physicalfrom,omitempty"` // The physical value at the start of the range (like -270 degrees).  Optional
+	PhysicalTo   float64 `json:"physicalto,omitempty"`   // The physical value at the end of the range (like 270 degrees).  Optional
+}
+
+// Mode finds a mode by name (case insensitive)
+func (p FixtureProfile) Mode(name string) (FixtureMode, bool) {
+	for _, mode := range p.Modes {
+		if strings.EqualFold(mode.Name, name) {
+			return mode, true
+		}
+	}
+	return FixtureMode{}, false
+}
+
+// Footprint is the number of DMX channels used by the mode
+func (m FixtureMode) Footprint() int {
+	return len(m.Channels)
+}
+
+// validateFixtureModes makes sure a list of fixture modes makes sense
+func validateFixtureModes(modes []FixtureMode) error {
+	if len(modes) < 1 {
+		return fmt.Errorf("modes must contain at least one item")
+	}
+
+	known := map[string]bool{}
+	for _, attribute := range FixtureAttributes {
+		known[attribute] = true
+	}
+
+	names := map[string]bool{}
+	for _, mode := range modes {
+		if strings.TrimSpace(mode.Name) == "" {
+			return fmt.Errorf("every mode must have a name")
+		}
+		if names[strings.ToLower(mode.Name)] {
+			return fmt.Errorf("mode '%s' is listed more than once", mode.Name)
+		}
+		names[strings.ToLower(mode.Name)] = true
+
+		if len(mode.Channels) < 1 || len(mode.Channels) > 512 {
+			return fmt.Errorf("mode '%s' must have between 1 and 512 channels", mode.Name)
+		}
+
+		coarse := map[string]bool{}
+		for i, channel := range mode.Channels {
+			if !known[channel.Attribute] {
+				return fmt.Errorf("mode '%s' channel %v has an unknown attribute '%s'", mode.Name, i+1, channel.Attribute)
+			}
+
+			//	Fine channels need a coarse channel to go with
+			if channel.Fine && !coarse[channel.Attribute] {
+				return fmt.Errorf("mode '%s' channel %v is a fine '%s' channel without a coarse channel before it", mode.Name, i+1, channel.Attribute)
+			}
+			if !channel.Fine {
+				coarse[channel.Attribute] = true
+			}
+
+			for _, function := range channel.Functions {
+				if function.From > function.To {
+					return fmt.Errorf("mode '%s' channel %v function '%s' starts after it ends", mode.Name, i+1, function.Name)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Validate makes sure a fixture profile makes sense (it has a model and its modes are valid)
+func (p FixtureProfile) Validate() error {
+
+	//	If we don't have a model, return an error
+	if strings.TrimSpace(p.Model) == "" {
+		return fmt.Errorf("model is required")
+	}
+
+	//	Make sure the modes make sense
+	return validateFixtureModes(p.Modes)
+}
+
+// AddFixtureProfile adds a fixture profile to the system
+func (store Manager) AddFixtureProfile(manufacturer, model string, modes []FixtureMode) (FixtureProfile, error) {
+
+	profiles, err := store.AddFixtureProfiles([]FixtureProfile{{Manufacturer: manufacturer, Model: model, Modes: modes}})
+	if err != nil {
+		return FixtureProfile{}, err
+	}
+
+	return profiles[0], nil
+}
+
+// AddFixtureProfiles adds a set of fixture profiles to the system.  Every
+// profile is checked before any is saved, and they're saved together, so
+// either they're all added or none are.
+func (store Manager) AddFixtureProfiles(profiles []FixtureProfile) ([]FixtureProfile, error) {
+
+	//	Our return items
+	retval := []FixtureProfile{}
+
+	//	Make sure every profile makes sense
+	for _, profile := range profiles {
+		if err := profile.Validate(); err != nil {
+			return retval, err
+		}
+	}
+
+	//	Create our new profiles
+	newProfiles := []FixtureProfile{}
+	for _, profile := range profiles {
+		newProfiles = append(newProfiles, FixtureProfile{
+			ID:           xid.New().String(), // Generate a new id
+			Created:      time.Now(),
+			Manufacturer: profile.Manufacturer,
+			Model:        profile.Model,
+			Modes:        profile.Modes,
+		})
+	}
+
+	//	Save them to the database:
+	err := store.systemdb.Update(func(tx *buntdb.Tx) error {
+		for _, newProfile := range newProfiles {
+			//	Serialize to JSON format
+			encoded, err := json.Marshal(newProfile)
+			if err != nil {
+				return err
+			}
+
+			if _, _, err := tx.Set(GetKey("FixtureProfile", newProfile.ID), string(encoded), &buntdb.SetOptions{}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	//	If there was an error saving the data, report it:
+	if err != nil {
+		return retval, fmt.Errorf("problem saving the fixture profile: %s", err)
+	}
+
+	//	Set our retval:
+	retval = newProfiles
+
+	//	Return our data:
+	return retval, nil
+}
+
+// UpdateFixtureProfile updates a fixture profile in the system.  Every
+// fixture patched with the profile is checked (and its footprint updated)
+// against the new modes, so an edit can't leave fixtures overlapping.
+func (store Manager) UpdateFixtureProfile(updatedProfile FixtureProfile) (FixtureProfile, error) {
+
+	//	Our return item
+	retval := FixtureProfile{}
+
+	//	Make sure the modes make sense
+	if err := validateFixtureModes(updatedProfile.Modes); err != nil {
+		return retval, err
+	}
+
+	//	Serialize to JSON format
+	encoded, err := json.Marshal(updatedProfile)
+	if err != nil {
+		return retval, fmt.Errorf("problem serializing the data: %s", err)
+	}
+
+	//	Save it to the database (along with the fixtures that use it):
+	var patchErr error
+	err = store.systemdb.Update(func(tx *buntdb.Tx) error {
+		_, _, err := tx.Set(GetKey("FixtureProfile", updatedProfile.ID), string(encoded), &buntdb.SetOptions{})
+		if err != nil {
+			return err
+		}
+
+		fixtures, err := getFixtures(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, fixture := range fixtures {
+			if fixture.ProfileID != updatedProfile.ID {
+				continue
+			}
+
+			fixture, patchErr = checkPatch(tx, fixture)
+			if patchErr != nil {
+				return patchErr
+			}
+
+			encodedFixture, err := json.Marshal(fixture)
+			if err != nil {
+				return err
+			}
+
+			if _, _, err := tx.Set(GetKey("Fixture", fixture.ID), string(encodedFixture), &buntdb.SetOptions{}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	//	If a patched fixture doesn't work with the new modes, say why
+	if patchErr != nil {
+		return retval, fmt.Errorf("the profile change doesn't fit the patch: %s", patchErr)
+	}
+
+	//	If there was an error saving the data, report it:
+	if err != nil {
+		return retval, fmt.Errorf("problem saving the fixture profile: %s", err)
+	}
+
+	//	Set our retval:
+	retval = updatedProfile
+
+	//	Return our data:
+	return retval, nil
+}
+
+// GetFixtureProfile gets information about a single fixture profile in the system based on its id
+func (store Manager) GetFixtureProfile(id string) (FixtureProfile, error) {
+	//	Our return item
+	retval := FixtureProfile{}
+
+	//	Find the item:
+	err := store.systemdb.View(func(tx *buntdb.Tx) error {
+		var err error
+		retval, err = getFixtureProfile(tx, id)
+		return err
+	})
+
+	//	If there was an error, report it:
+	if err != nil {
+		return retval, fmt.Errorf("problem getting the fixture profile: %s", err)
+	}
+
+	//	Return our data:
+	return retval, nil
+}
+
+// getFixtureProfile gets a single fixture profile in a transaction
+func getFixtureProfile(tx *buntdb.Tx, id string) (FixtureProfile, error) {
+	//	Our return item
+	retval := FixtureProfile{}
+
+	val, err := tx.Get(GetKey("FixtureProfile", id))
+	if err != nil {
+		return retval, err
+	}
+
+	if len(val) > 0 {
+		//	Unmarshal data into our item
+		if err := json.Unmarshal([]byte(val), &retval); err != nil {
+			return retval, err
+		}
+	}
+
+	return retval, nil
+}
+
+// GetAllFixtureProfiles gets all fixture profiles in the system
+func (store Manager) GetAllFixtureProfiles() ([]FixtureProfile, error) {
+	//	Our return item
+	retval := []FixtureProfile{}
+
+	//	Set our prefix
+	prefix := GetKey("FixtureProfile")
+
+	//	Iterate over our values:
+	err := store.systemdb.View(func(tx *buntdb.Tx) error {
+		tx.Descend(prefix, func(key, val string) bool {
+
+			if len(val) > 0 {
+				//	Create our item:
+				item := FixtureProfile{}
+
+				//	Unmarshal data into our item
+				bval := []byte(val)
+				if err := json.Unmarshal(bval, &item); err != nil {
+					return false
+				}
+
+				//	Add to the array of returned profiles:
+				retval = append(retval, item)
+			}
+
+			return true
+		})
+		return nil
+	})
+
+	//	If there was an error, report it:
+	if err != nil {
+		return retval, fmt.Errorf("problem getting the list of fixture profiles: %s", err)
+	}
+
+	//	Return our data:
+	return retval, nil
+}
+
+// DeleteFixtureProfile deletes a fixture profile from the system.  Profiles
+// that patched fixtures use can't be deleted.
+func (store Manager) DeleteFixtureProfile(id string) error {
+
+	//	Remove it from the database (unless a fixture uses it):
+	err := store.systemdb.Update(func(tx *buntdb.Tx) error {
+		fixtures, err := getFixtures(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, fixture := range fixtures {
+			if fixture.ProfileID == id {
+				return fmt.Errorf("%w: fixture '%s' uses it (unpatch it first)", ErrProfileInUse, fixture.Name)
+			}
+		}
+
+		_, err = tx.Delete(GetKey("FixtureProfile", id))
+		return err
+	})
+
+	//	If there was an error removing the data, report it:
+	if err != nil {
+		return fmt.Errorf("problem removing the fixture profile: %w", err)
+	}
+
+	//	Return our data:
+	return nil
+}
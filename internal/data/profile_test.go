@@ -0,0 +1,321 @@
+package data_test
+
+import (
+	"errors"
+	data2 "github.com/danesparza/fxdmx/internal/data"
+	"os"
+	"testing"
+)
+
+func getTestProfileModes() []data2.FixtureMode {
+	return []data2.FixtureMode{
+		{
+			Name: "3 channel",
+			Channels: []data2.FixtureChannel{
+				{Name: "Red", Attribute: data2.AttributeRed},
+				{Name: "Green", Attribute: data2.AttributeGreen},
+				{Name: "Blue", Attribute: data2.AttributeBlue},
+			},
+		},
+		{
+			Name: "7 channel",
+			Channels: []data2.FixtureChannel{
+				{Name: "Dimmer", Attribute: data2.AttributeDimmer},
+				{Name: "Red", Attribute: data2.AttributeRed},
+				{Name: "Green", Attribute: data2.AttributeGreen},
+				{Name: "Blue", Attribute: data2.AttributeBlue},
+				{Name: "Strobe", Attribute: data2.AttributeStrobe},
+				{Name: "Program", Attribute: data2.AttributeEffect},
+				{Name: "Speed", Attribute: data2.AttributeSpeed},
+			},
+		},
+	}
+}
+
+func TestProfile_AddFixtureProfile_ValidProfile_Successful(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	//	Act
+	newProfile, err := db.AddFixtureProfile("Unit test lighting", "Par 64", getTestProfileModes())
+
+	//	Assert
+	if err != nil {
+		t.Errorf("AddFixtureProfile - Should add profile without error, but got: %s", err)
+	}
+
+	if newProfile.Created.IsZero() || newProfile.ID == "" {
+		t.Errorf("AddFixtureProfile failed: Should have set an id and the correct datetime: %+v", newProfile)
+	}
+
+	mode, found := newProfile.Mode("7 Channel")
+	if !found || mode.Footprint() != 7 {
+		t.Errorf("AddFixtureProfile failed: Should find the 7 channel mode: %+v", newProfile)
+	}
+}
+
+func TestProfile_AddFixtureProfile_UnknownAttribute_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	modes := []data2.FixtureMode{
+		{Name: "1 channel", Channels: []data2.FixtureChannel{{Name: "Brightness", Attribute: "brightness"}}},
+	}
+
+	//	Act
+	_, err = db.AddFixtureProfile("Unit test lighting", "Par 64", modes)
+
+	//	Assert
+	if err == nil {
+		t.Errorf("AddFixtureProfile - Should return error for an unknown attribute, but got none")
+	}
+}
+
+func TestProfile_AddFixtureProfile_FineWithoutCoarse_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	modes := []data2.FixtureMode{
+		{Name: "2 channel", Channels: []data2.FixtureChannel{
+			{Name: "Pan fine", Attribute: data2.AttributePan, Fine: true},
+			{Name: "Pan", Attribute: data2.AttributePan},
+		}},
+	}
+
+	//	Act
+	_, err = db.AddFixtureProfile("Unit test lighting", "Mover", modes)
+
+	//	Assert
+	if err == nil {
+		t.Errorf("AddFixtureProfile - Should return error for a fine channel without a coarse channel, but got none")
+	}
+}
+
+func TestProfile_AddFixtureProfiles_OneInvalid_AddsNone(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	profiles := []data2.FixtureProfile{
+		{Manufacturer: "Unit test lighting", Model: "Par 64", Modes: getTestProfileModes()},
+		{Manufacturer: "Unit test lighting", Model: "Par 56", Modes: getTestProfileModes()},
+		{Manufacturer: "Unit test lighting", Model: "Broken", Modes: []data2.FixtureMode{{Name: "1 channel"}}},
+	}
+
+	//	Act
+	_, err = db.AddFixtureProfiles(profiles)
+	got, _ := db.GetAllFixtureProfiles()
+	added, addErr := db.AddFixtureProfiles(profiles[:2])
+
+	//	Assert
+	if err == nil {
+		t.Errorf("AddFixtureProfiles - Should return error for an invalid profile, but got none")
+	}
+
+	if len(got) != 0 {
+		t.Errorf("AddFixtureProfiles failed: Should not add any profile when one is invalid, but added %v", len(got))
+	}
+
+	if addErr != nil || len(added) != 2 || added[0].ID == "" || added[0].ID == added[1].ID {
+		t.Errorf("AddFixtureProfiles failed: Should add every valid profile with its own id but got: %+v (%v)", added, addErr)
+	}
+}
+
+func TestProfile_GetAllFixtureProfiles_Successful(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	//	Act
+	db.AddFixtureProfile("Unit test lighting", "Par 64", getTestProfileModes())
+	newProfile2, _ := db.AddFixtureProfile("Unit test lighting", "Par 56", getTestProfileModes())
+	db.AddFixtureProfile("Unit test fog", "Fogger", getTestProfileModes())
+
+	gotProfile, err := db.GetFixtureProfile(newProfile2.ID)
+	gotProfiles, _ := db.GetAllFixtureProfiles()
+
+	//	Assert
+	if err != nil {
+		t.Errorf("GetFixtureProfile - Should get profile without error, but got: %s", err)
+	}
+
+	if gotProfile.Model != newProfile2.Model {
+		t.Errorf("GetFixtureProfile failed: Should get valid model but got: %v", gotProfile.Model)
+	}
+
+	if len(gotProfiles) != 3 {
+		t.Errorf("GetAllFixtureProfiles failed: Should get all items but got: %v", len(gotProfiles))
+	}
+}
+
+func TestProfile_UpdateAndDeleteFixtureProfile_Successful(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	newProfile1, _ := db.AddFixtureProfile("Unit test lighting", "Par 64", getTestProfileModes())
+	newProfile2, _ := db.AddFixtureProfile("Unit test lighting", "Par 56", getTestProfileModes())
+
+	//	Act
+	newProfile1.Model = "Par 64 (updated)"
+	_, err = db.UpdateFixtureProfile(newProfile1)
+	gotProfile, _ := db.GetFixtureProfile(newProfile1.ID)
+
+	deleteErr := db.DeleteFixtureProfile(newProfile2.ID)
+	gotProfiles, _ := db.GetAllFixtureProfiles()
+
+	//	Assert
+	if err != nil {
+		t.Errorf("UpdateFixtureProfile - Should update profile without error, but got: %s", err)
+	}
+
+	if gotProfile.Model != "Par 64 (updated)" {
+		t.Errorf("UpdateFixtureProfile failed: Should get the updated model but got: %v", gotProfile.Model)
+	}
+
+	if deleteErr != nil {
+		t.Errorf("DeleteFixtureProfile - Should delete profile without error, but got: %s", deleteErr)
+	}
+
+	if len(gotProfiles) != 1 {
+		t.Errorf("DeleteFixtureProfile failed: Should remove an item but got: %v", len(gotProfiles))
+	}
+}
+
+func TestProfile_UpdateFixtureProfile_PatchedFixtures_CheckedAndUpdated(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	profile, _ := db.AddFixtureProfile("Unit test lighting", "Par 64", getTestProfileModes())
+	left, _ := db.AddFixture("Stage Left Par", profile.ID, "3 channel", 1, 17)
+	db.AddFixture("Stage Right Par", profile.ID, "3 channel", 1, 21)
+
+	//	Act
+	grown := profile
+	grown.Modes = getTestProfileModes()
+	grown.Modes[0].Channels = append(grown.Modes[0].Channels, data2.FixtureChannel{Name: "Dimmer", Attribute: data2.AttributeDimmer})
+	_, grownErr := db.UpdateFixtureProfile(grown)
+	afterGrown, _ := db.GetFixture(left.ID)
+
+	overlapping := profile
+	overlapping.Modes = getTestProfileModes()
+	overlapping.Modes[0].Channels = append(overlapping.Modes[0].Channels, data2.FixtureChannel{Name: "Dimmer", Attribute: data2.AttributeDimmer}, data2.FixtureChannel{Name: "Strobe", Attribute: data2.AttributeStrobe})
+	_, overlapErr := db.UpdateFixtureProfile(overlapping)
+	gotProfile, _ := db.GetFixtureProfile(profile.ID)
+
+	//	Assert
+	if grownErr != nil {
+		t.Errorf("UpdateFixtureProfile - Should grow the mode into free channels without error, but got: %s", grownErr)
+	}
+
+	if afterGrown.Footprint != 4 {
+		t.Errorf("UpdateFixtureProfile failed: Should update the footprint of patched fixtures to 4 but got: %v", afterGrown.Footprint)
+	}
+
+	if overlapErr == nil {
+		t.Errorf("UpdateFixtureProfile - Should return error when patched fixtures would overlap, but got none")
+	}
+
+	if len(gotProfile.Modes[0].Channels) != 4 {
+		t.Errorf("UpdateFixtureProfile failed: Should keep the profile as it was when the patch doesn't fit but got %v channels", len(gotProfile.Modes[0].Channels))
+	}
+}
+
+func TestProfile_DeleteFixtureProfile_PatchedFixture_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	profile, _ := db.AddFixtureProfile("Unit test lighting", "Par 64", getTestProfileModes())
+	fixture, _ := db.AddFixture("Stage Left Par", profile.ID, "3 channel", 1, 17)
+
+	//	Act
+	inUseErr := db.DeleteFixtureProfile(profile.ID)
+	db.DeleteFixture(fixture.ID)
+	unusedErr := db.DeleteFixtureProfile(profile.ID)
+
+	//	Assert
+	if !errors.Is(inUseErr, data2.ErrProfileInUse) {
+		t.Errorf("DeleteFixtureProfile - Should return ErrProfileInUse while a fixture uses the profile, but got: %v", inUseErr)
+	}
+
+	if unusedErr != nil {
+		t.Errorf("DeleteFixtureProfile - Should delete the profile once it's unpatched without error, but got: %s", unusedErr)
+	}
+}
@@ -33,6 +33,11 @@ func NewManager(systemdbpath string) (*Manager, error) {
 	sysdb.CreateIndex("Event", "Event:*", buntdb.IndexString)
 	sysdb.CreateIndex("Timeline", "Timeline:*", buntdb.IndexString)
 	sysdb.CreateIndex("Config", "Config:*", buntdb.IndexString)
+	sysdb.CreateIndex("FixtureProfile", "FixtureProfile:*", buntdb.IndexString)
+	sysdb.CreateIndex("Fixture", "Fixture:*", buntdb.IndexString)
+	sysdb.CreateIndex("FixtureGroup", "FixtureGroup:*", buntdb.IndexString)
+	sysdb.CreateIndex("Preset", "Preset:*", buntdb.IndexString)
+	sysdb.CreateIndex("CueList", "CueList:*", buntdb.IndexString)
 
 	//	Return our Manager reference
 	return retval, nil
@@ -16,30 +16,97 @@ type Timeline struct {
 	Name          string          `json:"name"`              // Timeline name
 	USBDevicePath string          `json:"devpath,omitempty"` // The USB device to play the timeline on.  Optional.  If not set, uses the default
 	Frames        []TimelineFrame `json:"frames"`            // Frames for the timeline
+	Tracks        []Track         `json:"tracks,omitempty"`  // More frames that play alongside the timeline's frames (optional) Every track starts at the same time
+}
+
+type Track struct {
+	Name   string          `json:"name,omitempty"` // Track name (optional)
+	Frames []TimelineFrame `json:"frames"`         // Frames for the track
 }
 
 type TimelineFrame struct {
-	Type      string         `json:"type"`               // Timeline frame type (scene/sleep/fade) Fade 'fades' between the previous channel state and this frame
-	Channels  []ChannelValue `json:"channels,omitempty"` // Channel information to set for the scene (optional) Required if type = scene or fade
-	SleepTime int            `json:"sleeptime"`          // Sleep type in seconds (optional) Required if type = sleep
+	Type       string         `json:"type"`                 // Timeline frame type (scene/sleep/fade/effect/chase/keyframes/timeline) Fade 'fades' between the previous channel state and this frame
+	Channels   []ChannelValue `json:"channels,omitempty"`   // Channel information to set for the scene (optional) Channels, fixtures or presets are required if type = scene or fade
+	Fixtures   []FixtureValue `json:"fixtures,omitempty"`   // Fixture attributes to set for the scene (optional) Resolved to channels using the patch when the timeline is played
+	Presets    []string       `json:"presets,omitempty"`    // Names of presets whose channels to set for the scene (optional) Resolved when the timeline is played
+	SleepTime  int            `json:"sleeptime"`            // Sleep type in seconds (optional) Required if type = sleep
+	FadeTime   int            `json:"fadetime,omitempty"`   // Fade time in milliseconds (optional) If not set, fades move one step every millisecond
+	ColorSpace string         `json:"colorspace,omitempty"` // How fixture colors fade (rgb/hsv/perceptual) (optional) If not set, colors fade channel by channel
+	Effect     *Effect        `json:"effect,omitempty"`     // The waveform to run on the frame's channels and fixtures (optional) Required if type = effect
+	Chase      *Chase         `json:"chase,omitempty"`      // The steps to chase through (optional) Required if type = chase
+	Keyframes  []Keyframe     `json:"keyframes,omitempty"`  // Looks pinned to times from the start of the frame (optional) Required if type = keyframes
+	Timeline   string         `json:"timeline,omitempty"`   // The ID of another timeline to play (optional) Required if type = timeline
+	Parallel   bool           `json:"parallel,omitempty"`   // Play the other timeline alongside this one instead of waiting for it to finish (optional)
+}
+
+type Effect struct {
+	Shape     string  `json:"shape"`               // Waveform (sine/saw/square/random/strobe) or natural effect (candle/fire/lightning/tv/fluorescent)
+	Rate      float64 `json:"rate,omitempty"`      // Cycles per second (Hz) (optional) Either rate or bpm is required, except for natural effects (which have a default)
+	BPM       float64 `json:"bpm,omitempty"`       // Cycles per minute (optional) Either rate or bpm is required
+	Min       float64 `json:"min"`                 // Lowest level (0.0 - 1.0)
+	Max       float64 `json:"max"`                 // Highest level (0.0 - 1.0) If min and max are both 0, max is 1.0
+	Phase     float64 `json:"phase,omitempty"`     // Phase offset in degrees between each channel or fixture (optional)
+	Attribute string  `json:"attribute,omitempty"` // The fixture attribute the effect runs on (optional) Defaults to dimmer
+	Seed      int64   `json:"seed,omitempty"`      // Seeds the random and natural effects (optional) An effect with the same seed plays the same way every time
+	Duration  int     `json:"duration,omitempty"`  // How long the effect runs in milliseconds (optional) If not set, runs through any sleep frames that follow until the next scene, fade or effect frame
+}
+
+type FixtureValue struct {
+	Fixture    string                 `json:"fixture,omitempty"` // The patched fixture name (optional) Either fixture or group is required
+	Group      string                 `json:"group,omitempty"`   // The fixture group name (optional) Sets the attributes on every fixture in the group
+	Preset     string                 `json:"preset,omitempty"`  // The name of a preset whose attributes to set (optional) Attributes set here win over the preset's
+	Attributes map[string]interface{} `json:"attributes"`        // Attribute values (like 'dimmer').  Levels are 0.0 - 1.0.  'color' is a hex, HSV or color temperature color (like '#ff8800', 'hsv(30, 100%, 100%)' or '3200K')
+	Spread     map[string]interface{} `json:"spread,omitempty"`  // Attribute values for the last fixture in the group (optional) Fixtures in between get values spread evenly from attributes to spread
+	Offset     int                    `json:"offset,omitempty"`  // Delay in milliseconds between each fixture in the group when fading, in group order (optional)
+}
+
+type Chase struct {
+	Steps     []ChaseStep `json:"steps"`               // The chase steps, in order
+	StepTime  int         `json:"steptime"`            // How long each step lasts in milliseconds
+	FadeRatio float64     `json:"faderatio,omitempty"` // How much of each step is spent fading into it (0.0 - 1.0) (optional) If not set, the chase snaps from step to step
+	Direction string      `json:"direction,omitempty"` // The order to run the steps in (forward/reverse/bounce/random) (optional) Defaults to forward
+	Repeat    int         `json:"repeat,omitempty"`    // How many times to run through the steps (optional) Defaults to 1
+	Seed      int64       `json:"seed,omitempty"`      // Seeds the random direction (optional) A chase with the same seed plays the same way every time
+}
+
+type Keyframe struct {
+	Time     int            `json:"time"`               // When the look is reached, in milliseconds from the start of the frame
+	Channels []ChannelValue `json:"channels,omitempty"` // Channel information to set for the keyframe (optional) Channels, fixtures or presets are required
+	Fixtures []FixtureValue `json:"fixtures,omitempty"` // Fixture attributes to set for the keyframe (optional)
+	Presets  []string       `json:"presets,omitempty"`  // Names of presets whose channels to set for the keyframe (optional)
+	Snap     bool           `json:"snap,omitempty"`     // Jump to the keyframe at its time instead of fading from the channel's keyframe before it (optional)
+}
+
+type ChaseStep struct {
+	Channels []ChannelValue `json:"channels,omitempty"` // Channel information to set for the step (optional) Channels, fixtures or presets are required
+	Fixtures []FixtureValue `json:"fixtures,omitempty"` // Fixture attributes to set for the step (optional)
+	Presets  []string       `json:"presets,omitempty"`  // Names of presets whose channels to set for the step (optional)
 }
 
 type ChannelValue struct {
-	Channel int  `json:"channel"` // Unique Fixture ID
-	Value   byte `json:"value"`   // Optional fixture name
+	Channel int    `json:"channel"`           // DMX channel (the coarse channel of a 16 bit value)
+	Value   byte   `json:"value"`             // Channel value (0 - 255)
+	Fine    int    `json:"fine,omitempty"`    // The fine channel of a 16 bit value (optional)
+	Value16 uint16 `json:"value16,omitempty"` // 16 bit value (0 - 65535) split across the channel and fine channel (optional) Used instead of value if fine is set
 }
 
 // AddTimeline adds a timeline to the system
-func (store Manager) AddTimeline(name, devpath string, frames []TimelineFrame) (Timeline, error) {
+func (store Manager) AddTimeline(name, devpath string, frames []TimelineFrame, tracks []Track) (Timeline, error) {
 
 	//	Our return item
 	retval := Timeline{}
 
 	//	If we don't have any frames, return an error
-	if len(frames) < 1 {
+	if len(frames) < 1 && len(tracks) < 1 {
 		return retval, fmt.Errorf("frames must contain at least one item")
 	}
 
+	for i, track := range tracks {
+		if len(track.Frames) < 1 {
+			return retval, fmt.Errorf("track %v frames must contain at least one item", i+1)
+		}
+	}
+
 	//	Create our new timeline
 	newTimeline := Timeline{
 		ID:      xid.New().String(), // Generate a new id
@@ -47,6 +114,7 @@ func (store Manager) AddTimeline(name, devpath string, frames []TimelineFrame) (
 		Enabled: true,
 		Name:    name,
 		Frames:  frames,
+		Tracks:  tracks,
 	}
 
 	//	Serialize to JSON format
@@ -46,7 +46,7 @@ func TestTimeline_AddTimeline_ValidTimeline_Successful(t *testing.T) {
 	}
 
 	//	Act
-	newTimeline, err := db.AddTimeline("unittest_timeline1", "", testTimelineFrames)
+	newTimeline, err := db.AddTimeline("unittest_timeline1", "", testTimelineFrames, nil)
 
 	//	Assert
 	if err != nil {
@@ -84,7 +84,7 @@ func TestTimeline_AddTimeline_NoFrames_ReturnsError(t *testing.T) {
 	testTimelineFrames := []data2.TimelineFrame{} // No items
 
 	//	Act
-	_, err = db.AddTimeline("unittest_timeline1", "", testTimelineFrames)
+	_, err = db.AddTimeline("unittest_timeline1", "", testTimelineFrames, nil)
 
 	//	Assert
 	if err == nil {
@@ -92,6 +92,44 @@ func TestTimeline_AddTimeline_NoFrames_ReturnsError(t *testing.T) {
 	}
 }
 
+func TestTimeline_AddTimeline_Tracks_Successful(t *testing.T) {
+
+	//	Arrange
+	systemdb := getTestFiles()
+
+	db, err := data2.NewManager(systemdb)
+	if err != nil {
+		t.Fatalf("NewManager failed: %s", err)
+	}
+	defer func() {
+		db.Close()
+		os.RemoveAll(systemdb)
+	}()
+
+	testTracks := []data2.Track{
+		{Name: "Wash", Frames: []data2.TimelineFrame{{Type: "fade", FadeTime: 5000, Channels: []data2.ChannelValue{{Channel: 1, Value: 255}}}}},
+		{Name: "Strobe", Frames: []data2.TimelineFrame{{Type: "scene", Channels: []data2.ChannelValue{{Channel: 2, Value: 255}}}}},
+	}
+
+	//	Act
+	newTimeline, err := db.AddTimeline("unittest_timeline1", "", nil, testTracks)
+
+	//	Assert
+	if err != nil {
+		t.Errorf("AddTimeline - Should add timeline with only tracks without error, but got: %s", err)
+	}
+
+	gotTimeline, _ := db.GetTimeline(newTimeline.ID)
+	if len(gotTimeline.Tracks) != 2 || gotTimeline.Tracks[1].Name != "Strobe" {
+		t.Errorf("AddTimeline failed: Should have saved the tracks: %+v", gotTimeline)
+	}
+
+	_, err = db.AddTimeline("unittest_timeline2", "", nil, []data2.Track{{Name: "Empty"}})
+	if err == nil {
+		t.Errorf("AddTimeline - Should return error for a track without frames, but got none")
+	}
+}
+
 func TestTimeline_GetTimeline_ValidTimeline_Successful(t *testing.T) {
 
 	//	Arrange
@@ -171,9 +209,9 @@ func TestTimeline_GetTimeline_ValidTimeline_Successful(t *testing.T) {
 	}}
 
 	//	Act
-	db.AddTimeline(testTimeline1.Name, "", testTimeline1.Frames)
-	newTimeline2, _ := db.AddTimeline(testTimeline2.Name, "", testTimeline2.Frames)
-	db.AddTimeline(testTimeline3.Name, "", testTimeline3.Frames)
+	db.AddTimeline(testTimeline1.Name, "", testTimeline1.Frames, nil)
+	newTimeline2, _ := db.AddTimeline(testTimeline2.Name, "", testTimeline2.Frames, nil)
+	db.AddTimeline(testTimeline3.Name, "", testTimeline3.Frames, nil)
 
 	gotTimeline, err := db.GetTimeline(newTimeline2.ID)
 
@@ -273,9 +311,9 @@ func TestTimeline_GetAllTimelines_Successful(t *testing.T) {
 	}}
 
 	//	Act
-	db.AddTimeline(testTimeline1.Name, "", testTimeline1.Frames)
-	newTimeline2, _ := db.AddTimeline(testTimeline2.Name, "", testTimeline2.Frames)
-	db.AddTimeline(testTimeline3.Name, "", testTimeline3.Frames)
+	db.AddTimeline(testTimeline1.Name, "", testTimeline1.Frames, nil)
+	newTimeline2, _ := db.AddTimeline(testTimeline2.Name, "", testTimeline2.Frames, nil)
+	db.AddTimeline(testTimeline3.Name, "", testTimeline3.Frames, nil)
 
 	gotTimelines, err := db.GetAllTimelines()
 
@@ -372,9 +410,9 @@ func TestTimeline_UpdateTimeline_ValidTimelines_Successful(t *testing.T) {
 	}}
 
 	//	Act
-	db.AddTimeline(testTimeline1.Name, "", testTimeline1.Frames)
-	newTimeline2, _ := db.AddTimeline(testTimeline2.Name, "", testTimeline2.Frames)
-	db.AddTimeline(testTimeline3.Name, "", testTimeline3.Frames)
+	db.AddTimeline(testTimeline1.Name, "", testTimeline1.Frames, nil)
+	newTimeline2, _ := db.AddTimeline(testTimeline2.Name, "", testTimeline2.Frames, nil)
+	db.AddTimeline(testTimeline3.Name, "", testTimeline3.Frames, nil)
 
 	//	Update the 2nd trigger:
 	newTimeline2.Enabled = false
@@ -472,9 +510,9 @@ func TestTimeline_DeleteTimeline_ValidTimeline_Successful(t *testing.T) {
 	}}
 
 	//	Act
-	db.AddTimeline(testTimeline1.Name, "", testTimeline1.Frames)
-	newTimeline2, _ := db.AddTimeline(testTimeline2.Name, "", testTimeline2.Frames)
-	db.AddTimeline(testTimeline3.Name, "", testTimeline3.Frames)
+	db.AddTimeline(testTimeline1.Name, "", testTimeline1.Frames, nil)
+	newTimeline2, _ := db.AddTimeline(testTimeline2.Name, "", testTimeline2.Frames, nil)
+	db.AddTimeline(testTimeline3.Name, "", testTimeline3.Frames, nil)
 
 	err = db.DeleteTimeline(newTimeline2.ID) //	Delete the 2nd timeline
 
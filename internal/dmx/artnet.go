@@ -0,0 +1,81 @@
+package dmx
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+)
+
+const (
+	artNetPort     = "6454"
+	artNetOpDmx    = 0x5000
+	artNetHeaderSz = 18
+)
+
+var artNetID = []byte("Art-Net\x00")
+
+// parseArtDmx parses an ArtDmx packet and returns the 15 bit port-address
+// (universe) and the channel values (starting with channel 1)
+func parseArtDmx(packet []byte) (int, []byte, bool) {
+	if len(packet) < artNetHeaderSz || !bytes.Equal(packet[:8], artNetID) {
+		return 0, nil, false
+	}
+
+	//	The opcode is little endian, everything after it is big endian
+	if int(packet[8])|int(packet[9])<<8 != artNetOpDmx {
+		return 0, nil, false
+	}
+
+	universe := int(packet[14]) | int(packet[15]&0x7F)<<8
+	length := int(packet[16])<<8 | int(packet[17])
+	if length > 512 || len(packet) < artNetHeaderSz+length {
+		return 0, nil, false
+	}
+
+	return universe, packet[artNetHeaderSz : artNetHeaderSz+length], true
+}
+
+// receiveArtNet listens for ArtDmx packets for the requested universe and
+// applies them to the input universe until the context is canceled
+func (bp *BackgroundProcess) receiveArtNet(ctx context.Context, req InputRequest) error {
+	bind := req.Bind
+	if strings.TrimSpace(bind) == "" {
+		bind = ":" + artNetPort
+	}
+
+	conn, err := net.ListenPacket("udp4", bind)
+	if err != nil {
+		return err
+	}
+
+	return readPackets(ctx, conn, func(packet []byte) {
+		universe, values, ok := parseArtDmx(packet)
+		if ok && universe == req.Universe {
+			bp.Input.Update(InputSourceArtNet, channelMap(values))
+		}
+	})
+}
+
+// readPackets reads packets from the connection and hands them to the handler
+// until the context is canceled
+func readPackets(ctx context.Context, conn net.PacketConn, handle func(packet []byte)) error {
+	//	Closing the connection unblocks the read loop below
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	buf := make([]byte, 1500)
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		handle(buf[:n])
+	}
+}
@@ -0,0 +1,173 @@
+package dmx
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/danesparza/fxdmx/internal/color"
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+// Chase directions
+const (
+	DirectionForward = "forward" // First step to last
+	DirectionReverse = "reverse" // Last step to first
+	DirectionBounce  = "bounce"  // First step to last and back again
+	DirectionRandom  = "random"  // A random step each time (never the same step twice in a row)
+)
+
+// resolveChase checks a chase frame and resolves its steps
+func (p Patch) resolveChase(frame data2.TimelineFrame, colors map[string]color.RGB) ([]playFrame, error) {
+	settings := frame.Chase
+	if settings == nil {
+		return nil, fmt.Errorf("chase frames need a chase")
+	}
+
+	if len(frame.Channels) > 0 || len(frame.Fixtures) > 0 || len(frame.Presets) > 0 {
+		return nil, fmt.Errorf("chase frames set channels, fixtures and presets in their steps")
+	}
+
+	if len(settings.Steps) < 1 {
+		return nil, fmt.Errorf("chase steps must contain at least one item")
+	}
+
+	if settings.StepTime <= 0 {
+		return nil, fmt.Errorf("chase steptime must be greater than 0")
+	}
+
+	if settings.FadeRatio < 0 || settings.FadeRatio > 1 {
+		return nil, fmt.Errorf("chase faderatio must be between 0.0 and 1.0")
+	}
+
+	if !validDirection(strings.ToLower(settings.Direction)) {
+		return nil, fmt.Errorf("chase direction '%s' %s", settings.Direction, directionChoices)
+	}
+
+	if settings.Repeat < 0 {
+		return nil, fmt.Errorf("chase repeat can't be negative")
+	}
+
+	//	Steps fade channel by channel (the order they play in isn't known until the chase plays)
+	retval := make([]playFrame, len(settings.Steps))
+	for i, step := range settings.Steps {
+		if len(step.Channels) == 0 && len(step.Fixtures) == 0 && len(step.Presets) == 0 {
+			return nil, fmt.Errorf("chase step %v needs channels, fixtures or presets", i+1)
+		}
+
+		resolved, err := p.resolveFrame(data2.TimelineFrame{Type: "scene", Channels: step.Channels, Fixtures: step.Fixtures, Presets: step.Presets}, colors)
+		if err != nil {
+			return nil, fmt.Errorf("chase step %v: %v", i+1, err)
+		}
+		retval[i] = resolved
+	}
+
+	return retval, nil
+}
+
+// directionChoices describes the directions a chase can run in
+const directionChoices = "must be one of forward, reverse, bounce or random"
+
+// validDirection returns true if a chase can run in the (lower case) direction
+// (an empty direction means forward)
+func validDirection(direction string) bool {
+	switch direction {
+	case "", DirectionForward, DirectionReverse, DirectionBounce, DirectionRandom:
+		return true
+	}
+	return false
+}
+
+// chaseOrder is the order a chase runs through its steps (for every
+// repeat).  Steps are worked out one at a time as the chase plays, so a chase
+// with lots of repeats doesn't need them all up front.
+type chaseOrder struct {
+	steps     int
+	direction string
+	total     int // How many steps the chase plays (over every repeat)
+	seed      int64
+	played    int
+	last      int // The last step played
+}
+
+// newChaseOrder creates the order a chase runs through its steps
+func newChaseOrder(steps int, direction string, repeat int, seed int64) *chaseOrder {
+	if repeat < 1 {
+		repeat = 1
+	}
+
+	//	Bounce goes up and back down again, without playing the end steps twice
+	direction = strings.ToLower(direction)
+	cycle := steps
+	if direction == DirectionBounce && steps > 2 {
+		cycle = 2*steps - 2
+	}
+
+	return &chaseOrder{steps: steps, direction: direction, total: cycle * repeat, seed: seed}
+}
+
+// next is the next step to play.  It returns false once the chase is done.
+func (o *chaseOrder) next() (int, bool) {
+	if o.played >= o.total || o.steps < 1 {
+		return 0, false
+	}
+
+	n := o.played
+	o.played++
+
+	switch o.direction {
+	case DirectionReverse:
+		o.last = o.steps - 1 - n%o.steps
+
+	case DirectionBounce:
+		o.last = n % max(2*o.steps-2, o.steps)
+		if o.last >= o.steps {
+			o.last = 2*o.steps - 2 - o.last
+		}
+
+	case DirectionRandom:
+		if n == 0 || o.steps == 1 {
+			o.last = int(noise(o.seed, int64(n)) * float64(o.steps))
+			break
+		}
+
+		//	Pick from every step except the last one played
+		pick := int(noise(o.seed, int64(n)) * float64(o.steps-1))
+		if pick >= o.last {
+			pick++
+		}
+		o.last = pick
+
+	default:
+		o.last = n % o.steps
+	}
+
+	return o.last, true
+}
+
+// playChase plays a chase frame's steps.  Each step fades in for its share
+// of the step time, then holds for the rest.  It returns false if it was
+// stopped before it finished.
+func (p *player) playChase(ctx context.Context, frame playFrame) bool {
+	settings := frame.Chase
+	fadeTime := int(math.Round(float64(settings.StepTime) * settings.FadeRatio))
+
+	order := newChaseOrder(len(frame.steps), settings.Direction, settings.Repeat, settings.Seed)
+	for i, more := order.next(); more; i, more = order.next() {
+		step := frame.steps[i]
+
+		step.Type, step.FadeTime = "fade", fadeTime
+		if fadeTime == 0 {
+			step.Type = "scene"
+		}
+
+		hold := playFrame{TimelineFrame: data2.TimelineFrame{Type: "sleep", SleepTime: settings.StepTime - fadeTime}}
+
+		if !p.play(ctx, []playFrame{step, hold}) {
+			return false
+		}
+	}
+
+	return true
+}
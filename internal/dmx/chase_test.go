@@ -0,0 +1,161 @@
+package dmx
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+// allSteps plays through a chase order
+func allSteps(order *chaseOrder) []int {
+	retval := []int{}
+	for i, more := order.next(); more; i, more = order.next() {
+		retval = append(retval, i)
+	}
+	return retval
+}
+
+func TestChase_ChaseOrder_Directions_Successful(t *testing.T) {
+
+	//	Arrange
+	tests := []struct {
+		direction string
+		repeat    int
+		want      []int
+	}{
+		{"", 0, []int{0, 1, 2, 3}},
+		{DirectionForward, 2, []int{0, 1, 2, 3, 0, 1, 2, 3}},
+		{DirectionReverse, 1, []int{3, 2, 1, 0}},
+		{"Bounce", 2, []int{0, 1, 2, 3, 2, 1, 0, 1, 2, 3, 2, 1}},
+	}
+
+	for _, test := range tests {
+		//	Act
+		got := allSteps(newChaseOrder(4, test.direction, test.repeat, 0))
+
+		//	Assert
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("chaseOrder failed: %s x%v should be %v but got %v", test.direction, test.repeat, test.want, got)
+		}
+	}
+}
+
+func TestChase_ChaseOrder_LotsOfRepeats_WorksOutStepsAsItGoes(t *testing.T) {
+
+	//	Arrange
+	order := newChaseOrder(4, DirectionBounce, 1000000000, 0)
+
+	//	Act
+	allocs := testing.AllocsPerRun(100, func() { order.next() })
+	first, _ := newChaseOrder(4, DirectionBounce, 1000000000, 0).next()
+
+	//	Assert
+	if allocs != 0 || first != 0 {
+		t.Errorf("chaseOrder failed: Should work out each step without allocating but got step %v after %v allocations", first, allocs)
+	}
+
+	if order.total != 6000000000 {
+		t.Errorf("chaseOrder failed: Should play 6000000000 steps but got %v", order.total)
+	}
+}
+
+func TestChase_ChaseOrder_Random_NeverRepeatsAStep(t *testing.T) {
+
+	//	Act
+	got := allSteps(newChaseOrder(3, DirectionRandom, 20, 9))
+	again := allSteps(newChaseOrder(3, DirectionRandom, 20, 9))
+
+	//	Assert
+	if len(got) != 60 || !reflect.DeepEqual(got, again) {
+		t.Fatalf("chaseOrder failed: Should pick the same 60 random steps for the same seed but got %v and %v", got, again)
+	}
+
+	seen := map[int]int{}
+	for i, step := range got {
+		if i > 0 && step == got[i-1] {
+			t.Fatalf("chaseOrder failed: Should never play the same step twice in a row but got %v", got)
+		}
+		seen[step]++
+	}
+
+	if len(seen) != 3 {
+		t.Errorf("chaseOrder failed: Should pick every step but got %v", seen)
+	}
+}
+
+func TestChase_ResolveFrames_Errors_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	patch := getTestPatch()
+	steps := []data2.ChaseStep{{Channels: []data2.ChannelValue{{Channel: 1, Value: 255}}}}
+	tests := []data2.TimelineFrame{
+		{Type: "chase"},
+		{Type: "chase", Chase: &data2.Chase{StepTime: 100}},
+		{Type: "chase", Chase: &data2.Chase{Steps: steps}},
+		{Type: "chase", Chase: &data2.Chase{Steps: steps, StepTime: 100, FadeRatio: 1.5}},
+		{Type: "chase", Chase: &data2.Chase{Steps: steps, StepTime: 100, Direction: "sideways"}},
+		{Type: "chase", Chase: &data2.Chase{Steps: []data2.ChaseStep{{}}, StepTime: 100}},
+		{Type: "chase", Chase: &data2.Chase{Steps: []data2.ChaseStep{{Fixtures: []data2.FixtureValue{{Fixture: "Nobody"}}}}, StepTime: 100}},
+		{Type: "chase", Channels: []data2.ChannelValue{{Channel: 2}}, Chase: &data2.Chase{Steps: steps, StepTime: 100}},
+	}
+
+	for _, test := range tests {
+		//	Act
+		_, err := patch.resolveFrames([]data2.TimelineFrame{test})
+
+		//	Assert
+		if err == nil {
+			t.Errorf("resolveFrames - Should return error for %+v, but got none", test.Chase)
+		}
+	}
+}
+
+func TestChase_PlayFrames_Steps_Successful(t *testing.T) {
+
+	//	Arrange
+	out := &testOutput{}
+	frames, err := getTestPatch().resolveFrames([]data2.TimelineFrame{
+		{Type: "chase", Chase: &data2.Chase{
+			Steps: []data2.ChaseStep{
+				{Fixtures: []data2.FixtureValue{{Fixture: "Stage Left Par", Attributes: map[string]interface{}{"dimmer": 1.0}}, {Fixture: "Stage Right Par", Attributes: map[string]interface{}{"dimmer": 0.0}}}},
+				{Fixtures: []data2.FixtureValue{{Fixture: "Stage Left Par", Attributes: map[string]interface{}{"dimmer": 0.0}}, {Fixture: "Stage Right Par", Attributes: map[string]interface{}{"dimmer": 1.0}}}},
+			},
+			StepTime:  60,
+			FadeRatio: 0.5,
+			Direction: "reverse",
+			Repeat:    2,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("resolveFrames - Should resolve without error, but got: %s", err)
+	}
+
+	//	Act
+	finished := playFrames(context.Background(), out, frames)
+
+	//	Assert
+	if !finished {
+		t.Fatalf("playFrames - Should finish playing, but it was stopped")
+	}
+
+	//	Count the steps we landed on (right, then left, twice)
+	landed := []string{}
+	for _, render := range out.renders {
+		step := ""
+		switch {
+		case render[17] == 255 && render[21] == 0:
+			step = "left"
+		case render[17] == 0 && render[21] == 255:
+			step = "right"
+		}
+		if step != "" && (len(landed) == 0 || landed[len(landed)-1] != step) {
+			landed = append(landed, step)
+		}
+	}
+
+	if !reflect.DeepEqual(landed, []string{"right", "left", "right", "left"}) {
+		t.Errorf("playFrames failed: Should chase through the steps in reverse twice but got %v", landed)
+	}
+}
@@ -0,0 +1,191 @@
+package dmx
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock tells the time and waits.  Timelines play on a clock, so they can be
+// played against virtual time (in tests, or to render a timeline without
+// any hardware) as well as the system clock.
+type Clock interface {
+	// Now is the current time
+	Now() time.Time
+
+	// After sends the current time once the duration has passed
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the system's (monotonic) clock
+type SystemClock struct{}
+
+// Now is the current time
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After sends the current time once the duration has passed
+func (SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// VirtualClock is a clock that only moves when everything playing on it is
+// waiting.  It then jumps straight to the next time something is waiting
+// for, so a timeline plays as fast as it can be rendered, and renders at
+// exactly the same (virtual) times every time it plays.  Play one timeline
+// (or cue list) at a time on a virtual clock.
+type VirtualClock struct {
+	now     time.Time
+	running int // Players that are running (not waiting on the clock)
+	timers  []virtualTimer
+	mutex   sync.Mutex
+}
+
+type virtualTimer struct {
+	at    time.Time
+	fired chan time.Time
+}
+
+// NewVirtualClock creates a virtual clock starting at a time
+func NewVirtualClock(start time.Time) *VirtualClock {
+	return &VirtualClock{now: start, running: 1}
+}
+
+// Now is the current (virtual) time
+func (c *VirtualClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+// After sends the current (virtual) time once the duration has passed
+func (c *VirtualClock) After(d time.Duration) <-chan time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	timer := virtualTimer{at: c.now.Add(max(d, 0)), fired: make(chan time.Time, 1)}
+	c.timers = append(c.timers, timer)
+	c.running--
+	c.advance()
+
+	return timer.fired
+}
+
+// setRunning changes how many players are running on the clock
+func (c *VirtualClock) setRunning(n int) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.running += n
+	c.advance()
+}
+
+// advance moves to the next time something is waiting for (if nothing is
+// running) and wakes up everything waiting for it
+func (c *VirtualClock) advance() {
+	if c.running > 0 || len(c.timers) == 0 {
+		return
+	}
+
+	sort.SliceStable(c.timers, func(i, j int) bool { return c.timers[i].at.Before(c.timers[j].at) })
+	if c.timers[0].at.After(c.now) {
+		c.now = c.timers[0].at
+	}
+
+	for len(c.timers) > 0 && !c.timers[0].at.After(c.now) {
+		c.timers[0].fired <- c.now
+		c.timers = c.timers[1:]
+		c.running++
+	}
+}
+
+// stop stops waiting for a timer.  If the timer has already fired, whatever
+// was waiting for it isn't running any more.
+func (c *VirtualClock) stop(timer <-chan time.Time) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for i, waiting := range c.timers {
+		if waiting.fired == timer {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return
+		}
+	}
+
+	c.running--
+	c.advance()
+}
+
+// runningClock is a clock that needs to know how many players are running
+// on it (like a virtual clock)
+type runningClock interface {
+	setRunning(n int)
+	stop(timer <-chan time.Time)
+}
+
+func setRunning(c Clock, n int) {
+	if counted, ok := c.(runningClock); ok {
+		counted.setRunning(n)
+	}
+}
+
+// stopTimer stops waiting for a timer from the clock (that hasn't been
+// received from yet)
+func stopTimer(c Clock, timer <-chan time.Time) {
+	if counted, ok := c.(runningClock); ok && timer != nil {
+		counted.stop(timer)
+	}
+}
+
+// playerGroup is players running alongside a player, which it can wait for
+type playerGroup struct {
+	remaining int
+	waiting   bool          // Set while the player is waiting for the group
+	done      chan struct{} // Closed when the last player in the group finishes (if the player is waiting)
+	mutex     sync.Mutex
+}
+
+// add adds a player (that's about to start running) to the group
+func (g *playerGroup) add(c Clock) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.remaining++
+	setRunning(c, 1)
+}
+
+// finished records a player in the group has finished.  If the last player
+// finishes while the player is waiting for the group, it hands over to the
+// waiting player (which carries on running in its place).
+func (g *playerGroup) finished(c Clock) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	g.remaining--
+	if g.remaining == 0 && g.waiting {
+		close(g.done)
+		return
+	}
+	setRunning(c, -1)
+}
+
+// wait waits for every player in the group to finish
+func (g *playerGroup) wait(c Clock) {
+	g.mutex.Lock()
+	if g.remaining == 0 {
+		g.mutex.Unlock()
+		return
+	}
+
+	g.waiting, g.done = true, make(chan struct{})
+	done := g.done
+	setRunning(c, -1)
+	g.mutex.Unlock()
+
+	<-done
+
+	g.mutex.Lock()
+	g.waiting = false
+	g.mutex.Unlock()
+}
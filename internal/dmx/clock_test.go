@@ -0,0 +1,237 @@
+package dmx
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+// clockedRender is a rendered frame (channels 1 - 4) and when it was rendered
+type clockedRender struct {
+	at       time.Duration
+	channels [4]byte
+}
+
+// clockedOutput records every rendered frame with the (virtual) time since it started
+type clockedOutput struct {
+	clock   Clock
+	start   time.Time
+	frame   [513]byte
+	renders []clockedRender
+}
+
+func newClockedOutput(clock Clock) *clockedOutput {
+	return &clockedOutput{clock: clock, start: clock.Now()}
+}
+
+func (o *clockedOutput) SetChannel(channel int, value byte) error {
+	o.frame[channel] = value
+	return nil
+}
+
+func (o *clockedOutput) Render() error {
+	render := clockedRender{at: o.clock.Now().Sub(o.start)}
+	copy(render.channels[:], o.frame[1:5])
+	o.renders = append(o.renders, render)
+	return nil
+}
+
+// playVirtual plays frames on a virtual clock, returning if they finished and what was rendered
+func playVirtual(t *testing.T, ctx context.Context, timeline data2.Timeline) (bool, []clockedRender) {
+	tracks, err := getTestNestedPatch().resolveTimeline(timeline)
+	if err != nil {
+		t.Fatalf("resolveTimeline - Should resolve without error, but got: %s", err)
+	}
+
+	clock := NewVirtualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	out := newClockedOutput(clock)
+
+	finished := playTimeline(ctx, clock, out, tracks)
+	return finished, out.renders
+}
+
+func TestClock_VirtualClock_ScenesAndSleeps_RenderOnTime(t *testing.T) {
+
+	//	Arrange
+	timeline := data2.Timeline{ID: "show", Frames: []data2.TimelineFrame{
+		{Type: "scene", Channels: []data2.ChannelValue{{Channel: 1, Value: 255}}},
+		{Type: "sleep", SleepTime: 1000},
+		{Type: "scene", Channels: []data2.ChannelValue{{Channel: 2, Value: 128}}},
+		{Type: "sleep", SleepTime: 60000},
+		{Type: "scene", Channels: []data2.ChannelValue{{Channel: 1, Value: 0}}},
+	}}
+
+	//	Act
+	started := time.Now()
+	finished, renders := playVirtual(t, context.Background(), timeline)
+
+	//	Assert
+	if !finished {
+		t.Fatalf("playTimeline - Should finish playing, but it was stopped")
+	}
+
+	if time.Since(started) > time.Second {
+		t.Errorf("playTimeline failed: Should play a minute of virtual time without waiting for it")
+	}
+
+	want := []clockedRender{
+		{0, [4]byte{255, 0, 0, 0}},
+		{time.Second, [4]byte{255, 128, 0, 0}},
+		{61 * time.Second, [4]byte{0, 128, 0, 0}},
+	}
+	if !reflect.DeepEqual(renders, want) {
+		t.Errorf("playTimeline failed: Should render %v but got %v", want, renders)
+	}
+}
+
+func TestClock_VirtualClock_Fade_RendersEveryInterval(t *testing.T) {
+
+	//	Arrange
+	timeline := data2.Timeline{ID: "show", Frames: []data2.TimelineFrame{
+		{Type: "fade", FadeTime: 100, Channels: []data2.ChannelValue{{Channel: 1, Value: 200}}},
+		{Type: "sleep", SleepTime: 10},
+		{Type: "scene", Channels: []data2.ChannelValue{{Channel: 2, Value: 1}}},
+	}}
+
+	//	Act
+	finished, renders := playVirtual(t, context.Background(), timeline)
+
+	//	Assert
+	if !finished {
+		t.Fatalf("playTimeline - Should finish playing, but it was stopped")
+	}
+
+	want := []clockedRender{
+		{0, [4]byte{0, 0, 0, 0}},
+		{25 * time.Millisecond, [4]byte{50, 0, 0, 0}},
+		{50 * time.Millisecond, [4]byte{100, 0, 0, 0}},
+		{75 * time.Millisecond, [4]byte{150, 0, 0, 0}},
+		{100 * time.Millisecond, [4]byte{200, 0, 0, 0}},
+		{110 * time.Millisecond, [4]byte{200, 1, 0, 0}},
+	}
+	if !reflect.DeepEqual(renders, want) {
+		t.Errorf("playTimeline failed: Should render %v but got %v", want, renders)
+	}
+}
+
+func TestClock_VirtualClock_ChaseRepeats_RenderOnTime(t *testing.T) {
+
+	//	Arrange
+	timeline := data2.Timeline{ID: "show", Frames: []data2.TimelineFrame{
+		{Type: "chase", Chase: &data2.Chase{
+			Steps: []data2.ChaseStep{
+				{Channels: []data2.ChannelValue{{Channel: 1, Value: 255}, {Channel: 2, Value: 0}}},
+				{Channels: []data2.ChannelValue{{Channel: 1, Value: 0}, {Channel: 2, Value: 255}}},
+			},
+			StepTime: 500,
+			Repeat:   3,
+		}},
+	}}
+
+	//	Act
+	finished, renders := playVirtual(t, context.Background(), timeline)
+
+	//	Assert
+	if !finished {
+		t.Fatalf("playTimeline - Should finish playing, but it was stopped")
+	}
+
+	if len(renders) != 6 {
+		t.Fatalf("playTimeline failed: Should render each of the 6 steps once but got %v", renders)
+	}
+
+	for i, render := range renders {
+		if render.at != time.Duration(i)*500*time.Millisecond {
+			t.Errorf("playTimeline failed: Step %v should render at %v but rendered at %v", i+1, time.Duration(i)*500*time.Millisecond, render.at)
+		}
+		if on := render.channels[i%2]; on != 255 {
+			t.Errorf("playTimeline failed: Step %v should turn channel %v on but got %v", i+1, i%2+1, render.channels)
+		}
+	}
+}
+
+func TestClock_VirtualClock_TracksAndParallelTimelines_ShareTheClock(t *testing.T) {
+
+	//	Arrange
+	timeline := data2.Timeline{
+		ID: "show",
+		Frames: []data2.TimelineFrame{
+			{Type: "sleep", SleepTime: 300},
+			{Type: "timeline", Timeline: "flash", Parallel: true},
+			{Type: "sleep", SleepTime: 100},
+			{Type: "scene", Channels: []data2.ChannelValue{{Channel: 3, Value: 3}}},
+		},
+		Tracks: []data2.Track{
+			{Frames: []data2.TimelineFrame{
+				{Type: "sleep", SleepTime: 200},
+				{Type: "scene", Channels: []data2.ChannelValue{{Channel: 4, Value: 4}}},
+			}},
+		},
+	}
+
+	//	Act
+	finished, renders := playVirtual(t, context.Background(), timeline)
+
+	//	Assert
+	if !finished {
+		t.Fatalf("playTimeline - Should finish playing, but it was stopped")
+	}
+
+	//	The flash timeline sets channel 1 on, waits 40ms and sets it off again
+	want := []clockedRender{
+		{200 * time.Millisecond, [4]byte{0, 0, 0, 4}},
+		{300 * time.Millisecond, [4]byte{255, 0, 0, 4}},
+		{340 * time.Millisecond, [4]byte{0, 0, 0, 4}},
+		{400 * time.Millisecond, [4]byte{0, 0, 3, 4}},
+	}
+	if !reflect.DeepEqual(renders, want) {
+		t.Errorf("playTimeline failed: Should render %v but got %v", want, renders)
+	}
+}
+
+func TestClock_VirtualClock_Stopped_StopsRendering(t *testing.T) {
+
+	//	Arrange
+	tracks, err := getTestNestedPatch().resolveTimeline(data2.Timeline{ID: "show", Frames: []data2.TimelineFrame{
+		{Type: "effect", Channels: []data2.ChannelValue{{Channel: 1}}, Effect: &data2.Effect{Shape: "saw", Rate: 1, Duration: 10000}},
+	}})
+	if err != nil {
+		t.Fatalf("resolveTimeline - Should resolve without error, but got: %s", err)
+	}
+
+	clock := NewVirtualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	out := newClockedOutput(clock)
+	ctx, cancel := context.WithCancel(context.Background())
+	stopper := &stoppingOutput{clockedOutput: out, at: 500 * time.Millisecond, stop: cancel}
+
+	//	Act
+	finished := playTimeline(ctx, clock, stopper, tracks)
+
+	//	Assert
+	if finished {
+		t.Fatalf("playTimeline - Should be stopped, but it finished")
+	}
+
+	last := out.renders[len(out.renders)-1]
+	if last.at != 500*time.Millisecond || len(out.renders) != 21 {
+		t.Errorf("playTimeline failed: Should stop rendering at 500ms (after 21 renders) but got %v renders up to %v", len(out.renders), last.at)
+	}
+}
+
+// stoppingOutput stops playing once it has rendered a frame at a (virtual) time
+type stoppingOutput struct {
+	*clockedOutput
+	at   time.Duration
+	stop func()
+}
+
+func (o *stoppingOutput) Render() error {
+	o.clockedOutput.Render()
+	if o.renders[len(o.renders)-1].at >= o.at {
+		o.stop()
+	}
+	return nil
+}
@@ -0,0 +1,464 @@
+package dmx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danesparza/fxdmx/internal/color"
+	data2 "github.com/danesparza/fxdmx/internal/data"
+	"github.com/danesparza/fxdmx/internal/event"
+
+	"github.com/akualab/dmx"
+)
+
+const (
+	// CueGo runs the next cue
+	CueGo = "go"
+
+	// CueBack runs the cue before the current one
+	CueBack = "back"
+
+	// CueGoto runs a cue by its number
+	CueGoto = "goto"
+
+	// CueRelease stops running the cue list (the output holds its last look)
+	CueRelease = "release"
+)
+
+// cueCommandQueue is how many commands can wait for a cue player (while it
+// connects to its device, say) before more are turned away
+const cueCommandQueue = 16
+
+// errCueListStopped is returned when sending a command to a cue player that has stopped
+var errCueListStopped = errors.New("cue list has stopped")
+
+// CueCommand is an operator command for a cue list
+type CueCommand struct {
+	Command string        `json:"command"`       // The command (go/back/goto/release)
+	Cue     float64       `json:"cue,omitempty"` // The cue number to run.  Required if command = goto
+	CueList data2.CueList `json:"cuelist"`       // The cue list (as it is now).  Cue lists start running on their first GO (or GOTO), and pick up edits on every command after that
+}
+
+// CueListStatus is where a running cue list is up to
+type CueListStatus struct {
+	ID      string  `json:"id"`      // The cue list ID
+	Name    string  `json:"name"`    // The cue list name
+	Cue     float64 `json:"cue"`     // The current cue number (0 until the first cue runs)
+	CueName string  `json:"cuename"` // The current cue name
+	Next    float64 `json:"next"`    // The cue number GO runs next (0 after the last cue)
+	Fading  bool    `json:"fading"`  // True while the current cue is still fading in
+}
+
+// CueListPlayback tracks where each running cue list is up to
+type CueListPlayback struct {
+	statuses map[string]CueListStatus
+	rwMutex  sync.RWMutex
+}
+
+// NewCueListPlayback creates a new (empty) cue list playback tracker
+func NewCueListPlayback() *CueListPlayback {
+	return &CueListPlayback{
+		statuses: make(map[string]CueListStatus),
+	}
+}
+
+// Status gets where a cue list is up to.  It returns false if the cue list isn't running
+func (c *CueListPlayback) Status(id string) (CueListStatus, bool) {
+	c.rwMutex.RLock()
+	defer c.rwMutex.RUnlock()
+
+	status, running := c.statuses[id]
+	return status, running
+}
+
+// All gets where every running cue list is up to
+func (c *CueListPlayback) All() []CueListStatus {
+	c.rwMutex.RLock()
+	defer c.rwMutex.RUnlock()
+
+	retval := []CueListStatus{}
+	for _, status := range c.statuses {
+		retval = append(retval, status)
+	}
+	sort.Slice(retval, func(i, j int) bool { return retval[i].Name < retval[j].Name })
+
+	return retval
+}
+
+func (c *CueListPlayback) set(status CueListStatus) {
+	c.rwMutex.Lock()
+	defer c.rwMutex.Unlock()
+	c.statuses[status.ID] = status
+}
+
+func (c *CueListPlayback) remove(id string) {
+	c.rwMutex.Lock()
+	defer c.rwMutex.Unlock()
+	delete(c.statuses, id)
+}
+
+// runningCueList is a cue list that is running in the background
+type runningCueList struct {
+	commands chan CueCommand
+	cancel   func()
+	done     chan struct{}
+}
+
+// resolveCues resolves every cue in a cue list to its full look.  Cues track:
+// a cue only needs to set the channels that change, and every look sets every
+// channel the cue list uses (channels are 0 until a cue sets them), so any
+// cue can be run from any other.
+func (p Patch) resolveCues(cues []data2.Cue) ([][]data2.ChannelValue, error) {
+	colors := map[string]color.RGB{}
+
+	tracked := map[int]data2.ChannelValue{}
+	order := []int{}
+
+	changes := make([][]data2.ChannelValue, len(cues))
+	for i, cue := range cues {
+		resolved, err := p.resolveFrame(data2.TimelineFrame{Type: "scene", Channels: cue.Channels, Fixtures: cue.Fixtures, Presets: cue.Presets}, colors)
+		if err != nil {
+			return nil, fmt.Errorf("cue %v: %v", cue.Number, err)
+		}
+		changes[i] = resolved.Channels
+
+		for _, value := range resolved.Channels {
+			if _, seen := tracked[value.Channel]; !seen {
+				tracked[value.Channel] = data2.ChannelValue{Channel: value.Channel, Fine: value.Fine}
+				order = append(order, value.Channel)
+			}
+		}
+	}
+
+	retval := make([][]data2.ChannelValue, len(cues))
+	for i := range cues {
+		for _, value := range changes[i] {
+			tracked[value.Channel] = value
+		}
+
+		look := make([]data2.ChannelValue, len(order))
+		for j, channel := range order {
+			look[j] = tracked[channel]
+		}
+		retval[i] = look
+	}
+
+	return retval, nil
+}
+
+// cuePlayer runs a cue list's cues as they're called
+type cuePlayer struct {
+	cueList  data2.CueList
+	looks    [][]data2.ChannelValue
+	current  int // The index of the current cue (-1 until the first cue runs)
+	playback *CueListPlayback
+	clock    Clock
+
+	// load resolves a cue list's looks (with the patch as it is now).  If
+	// it's set, every command reloads the cue list it carries.
+	load func(data2.CueList) ([][]data2.ChannelValue, error)
+}
+
+// reload picks up edits to the cue list (and the patch), so every command
+// runs the cue list as it is now.  The current cue stays current (by
+// number), or the cue before it if it was removed.
+func (c *cuePlayer) reload(cueList data2.CueList) error {
+	if c.load == nil || len(cueList.Cues) == 0 {
+		return nil
+	}
+
+	looks, err := c.load(cueList)
+	if err != nil {
+		return err
+	}
+
+	current := -1
+	if c.current >= 0 {
+		number := c.cueList.Cues[c.current].Number
+		for i, cue := range cueList.Cues {
+			if cue.Number <= number {
+				current = i
+			}
+		}
+	}
+
+	c.cueList, c.looks, c.current = cueList, looks, current
+	return nil
+}
+
+// target finds the index of the cue a command runs
+func (c *cuePlayer) target(command CueCommand) (int, error) {
+	switch strings.ToLower(command.Command) {
+	case CueGo:
+		if c.current+1 >= len(c.cueList.Cues) {
+			return 0, fmt.Errorf("cue list '%s' is already on its last cue", c.cueList.Name)
+		}
+		return c.current + 1, nil
+
+	case CueBack:
+		if c.current < 1 {
+			return 0, fmt.Errorf("cue list '%s' is on its first cue", c.cueList.Name)
+		}
+		return c.current - 1, nil
+
+	case CueGoto:
+		for i, cue := range c.cueList.Cues {
+			if cue.Number == command.Cue {
+				return i, nil
+			}
+		}
+		return 0, fmt.Errorf("cue list '%s' doesn't have a cue %v", c.cueList.Name, command.Cue)
+	}
+
+	return 0, fmt.Errorf("cue command '%s' must be one of go, back, goto or release", command.Command)
+}
+
+// fadeTo creates the fades from the current state to a cue's look.  Channels
+// going up fade over the cue's fade in time, and channels going down over its
+// fade out time.
+func (c *cuePlayer) fadeTo(state channelState, index int) ([]fader, time.Duration) {
+	cue := c.cueList.Cues[index]
+	delay := time.Duration(cue.Delay) * time.Millisecond
+
+	fades := []fader{}
+	longest := delay
+	for _, value := range c.looks[index] {
+		fade := channelFade{value: value, from: state.level(value), to: target(value), delay: delay}
+
+		fade.duration = time.Duration(cue.FadeOut) * time.Millisecond
+		if fade.to > fade.from {
+			fade.duration = time.Duration(cue.FadeIn) * time.Millisecond
+		}
+
+		fades = append(fades, fade)
+		longest = max(longest, fade.length())
+	}
+
+	return fades, longest
+}
+
+// update records where the cue list is up to
+func (c *cuePlayer) update(fading bool) {
+	status := CueListStatus{ID: c.cueList.ID, Name: c.cueList.Name, Fading: fading}
+
+	if c.current >= 0 {
+		status.Cue = c.cueList.Cues[c.current].Number
+		status.CueName = c.cueList.Cues[c.current].Name
+	}
+	if c.current+1 < len(c.cueList.Cues) {
+		status.Next = c.cueList.Cues[c.current+1].Number
+	}
+
+	c.playback.set(status)
+}
+
+// run runs cues as commands come in (or as cues auto follow) until it's
+// stopped.  Errors (like GO on the last cue) are reported and otherwise ignored.
+//
+// On a virtual clock, the player is only running while it handles a command
+// (sent with sendCueCommand) or a fade or follow time coming up.
+func (c *cuePlayer) run(ctx context.Context, out dmxOutput, commands <-chan CueCommand, report func(error)) {
+	state := channelState{}
+	c.update(false)
+
+	var fades []fader
+	var started time.Time
+	var longest time.Duration
+
+	var render <-chan time.Time // Set while a cue is fading
+	var follow <-chan time.Time // Set while waiting to auto follow
+	running := false
+
+	wait := func(d time.Duration) <-chan time.Time {
+		running = false
+		return c.clock.After(d)
+	}
+
+	start := func(index int) {
+		stopTimer(c.clock, render)
+		stopTimer(c.clock, follow)
+
+		fades, longest = c.fadeTo(state, index)
+		started = c.clock.Now()
+		c.current = index
+		c.update(true)
+
+		render, follow = wait(0), nil
+	}
+
+	for {
+		if running {
+			setRunning(c.clock, -1)
+			running = false
+		}
+
+		select {
+		case <-ctx.Done():
+			stopTimer(c.clock, render)
+			stopTimer(c.clock, follow)
+			return
+
+		case command := <-commands:
+			running = true
+			if err := c.reload(command.CueList); err != nil {
+				report(err)
+				continue
+			}
+
+			index, err := c.target(command)
+			if err != nil {
+				report(err)
+				continue
+			}
+			start(index)
+
+		case <-follow:
+			running, follow = true, nil
+			start(c.current + 1)
+
+		case <-render:
+			running = true
+			elapsed := c.clock.Now().Sub(started)
+			for _, fade := range fades {
+				fade.renderAt(state, out, elapsed)
+			}
+			out.Render()
+
+			if elapsed < longest {
+				render = wait(min(longest-elapsed, renderInterval))
+				continue
+			}
+
+			//	The cue is done -- see if the next cue follows on from it
+			render = nil
+			c.update(false)
+
+			cue := c.cueList.Cues[c.current]
+			if cue.AutoFollow && c.current+1 < len(c.cueList.Cues) {
+				follow = wait(time.Duration(cue.FollowTime) * time.Millisecond)
+			}
+		}
+	}
+}
+
+// sendCueCommand queues a command for a cue player without waiting for the
+// player to take it.  It returns errCueListStopped if the player is done, or
+// an error if too many commands are already waiting.  On a virtual clock,
+// the command counts as running until the player has it (so the clock can't
+// move on in between).
+func sendCueCommand(clock Clock, commands chan<- CueCommand, done <-chan struct{}, command CueCommand) error {
+	select {
+	case <-done:
+		return errCueListStopped
+	default:
+	}
+
+	setRunning(clock, 1)
+
+	select {
+	case commands <- command:
+		return nil
+	default:
+		setRunning(clock, -1)
+		return fmt.Errorf("cue list '%s' is busy, so %s was dropped", command.CueList.Name, strings.ToUpper(command.Command))
+	}
+}
+
+// RunCueList runs a cue list on its DMX device, taking commands until it's
+// stopped (or released)
+func (bp *BackgroundProcess) RunCueList(ctx context.Context, cueList data2.CueList, commands <-chan CueCommand, done chan<- struct{}) {
+	defer close(done)
+	defer bp.CueLists.remove(cueList.ID)
+
+	bp.DB.AddEvent(event.CueListStarted, fmt.Sprintf("Running cue list %v / %v", cueList.ID, cueList.Name), "", bp.HistoryTTL)
+
+	//	See if the cue list has a device set on it (if it doesn't, use the default)
+	if strings.TrimSpace(cueList.USBDevicePath) == "" {
+		defaultDevice, err := bp.DB.GetDefaultUSBDev()
+		if err != nil {
+			bp.DB.AddEvent(event.CueListError, fmt.Sprintf("An error occurred trying to get the default USB device: %v", err), "", bp.HistoryTTL)
+			return
+		}
+		cueList.USBDevicePath = defaultDevice
+	}
+
+	//	Resolve every cue's look using the current patch (and again on every
+	//	command, so edits to the cue list and the patch are picked up)
+	load := func(cueList data2.CueList) ([][]data2.ChannelValue, error) {
+		patch, err := LoadPatch(bp.DB)
+		if err != nil {
+			return nil, fmt.Errorf("an error occurred trying to load the fixture patch: %v", err)
+		}
+
+		looks, err := patch.resolveCues(cueList.Cues)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve fixtures in cue list %v: %v", cueList.ID, err)
+		}
+
+		return looks, nil
+	}
+
+	looks, err := load(cueList)
+	if err != nil {
+		bp.DB.AddEvent(event.CueListError, err.Error(), "", bp.HistoryTTL)
+		return
+	}
+
+	//	Connect to the DMX controller
+	out, err := dmx.NewDMXConnection(cueList.USBDevicePath)
+	if err != nil {
+		bp.DB.AddEvent(event.CueListError, fmt.Sprintf("Unable to connect to DMX512 interface %v: %v", cueList.USBDevicePath, err), "", bp.HistoryTTL)
+		return
+	}
+	defer out.Close()
+
+	player := &cuePlayer{cueList: cueList, looks: looks, current: -1, playback: bp.CueLists, clock: bp.clock(), load: load}
+	player.run(ctx, out, commands, func(err error) {
+		bp.DB.AddEvent(event.CueListError, err.Error(), "", bp.HistoryTTL)
+	})
+}
+
+// runCueCommand sends a command to a running cue list, starting the cue list
+// if it isn't running yet.  It never waits on the cue list (which handles its
+// commands in its own goroutine), so a slow device can't hold up timelines.
+func (bp *BackgroundProcess) runCueCommand(systemctx context.Context, running map[string]runningCueList, command CueCommand) {
+	id := command.CueList.ID
+	cueList, exists := running[id]
+
+	if strings.EqualFold(command.Command, CueRelease) {
+		if exists {
+			cueList.cancel()
+			delete(running, id)
+			bp.DB.AddEvent(event.CueListReleased, fmt.Sprintf("Released cue list %v", id), "", bp.HistoryTTL)
+		}
+		return
+	}
+
+	//	Send the command to the cue list (if it's still running)
+	if exists {
+		err := sendCueCommand(bp.clock(), cueList.commands, cueList.done, command)
+		if err == nil {
+			return
+		}
+
+		if !errors.Is(err, errCueListStopped) {
+			bp.DB.AddEvent(event.CueListError, err.Error(), "", bp.HistoryTTL)
+			return
+		}
+
+		//	Start the cue list again (it stopped on its own)
+		cueList.cancel()
+	}
+
+	ctx, cancel := context.WithCancel(systemctx)
+	cueList = runningCueList{commands: make(chan CueCommand, cueCommandQueue), cancel: cancel, done: make(chan struct{})}
+	running[id] = cueList
+
+	go bp.RunCueList(ctx, command.CueList, cueList.commands, cueList.done)
+	sendCueCommand(bp.clock(), cueList.commands, cueList.done, command)
+}
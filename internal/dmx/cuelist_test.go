@@ -0,0 +1,200 @@
+package dmx
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+func getTestCueList() data2.CueList {
+	return data2.CueList{
+		ID:   "act1",
+		Name: "Act 1",
+		Cues: []data2.Cue{
+			{Number: 1, Name: "Preshow", Channels: []data2.ChannelValue{{Channel: 1, Value: 100}}},
+			{Number: 2, Name: "Lights up", Channels: []data2.ChannelValue{{Channel: 2, Value: 255}}, FadeIn: 50},
+			{Number: 2.5, Name: "Special", Fixtures: []data2.FixtureValue{{Fixture: "Stage Left Par", Attributes: map[string]interface{}{"dimmer": 1.0}}}, AutoFollow: true, FollowTime: 20},
+			{Number: 3, Name: "Blackout", Channels: []data2.ChannelValue{{Channel: 1, Value: 0}, {Channel: 2, Value: 0}}, FadeOut: 50},
+		},
+	}
+}
+
+func TestCueList_ResolveCues_Tracking_Successful(t *testing.T) {
+
+	//	Arrange
+	patch := getTestPatch()
+
+	//	Act
+	looks, err := patch.resolveCues(getTestCueList().Cues)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("resolveCues - Should resolve without error, but got: %s", err)
+	}
+
+	first, second, special := channelMapOf(looks[0]), channelMapOf(looks[1]), channelMapOf(looks[2])
+
+	if len(first) != 3 || first[1] != 100 || first[2] != 0 || first[17] != 0 {
+		t.Errorf("resolveCues failed: Should set every channel the cue list uses (0 until a cue sets it) but got: %v", looks[0])
+	}
+
+	if second[1] != 100 || second[2] != 255 {
+		t.Errorf("resolveCues failed: Should track channels from the cue before but got: %v", looks[1])
+	}
+
+	if special[1] != 100 || special[2] != 255 || special[17] != 255 {
+		t.Errorf("resolveCues failed: Should track channels and resolve fixtures but got: %v", looks[2])
+	}
+}
+
+func TestCueList_Run_GoBackGoto_Successful(t *testing.T) {
+
+	//	Arrange
+	cueList := getTestCueList()
+	looks, err := getTestPatch().resolveCues(cueList.Cues)
+	if err != nil {
+		t.Fatalf("resolveCues - Should resolve without error, but got: %s", err)
+	}
+
+	clock := NewVirtualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	playback := NewCueListPlayback()
+	player := &cuePlayer{cueList: cueList, looks: looks, current: -1, playback: playback, clock: clock}
+	out := newClockedOutput(clock)
+	commands := make(chan CueCommand, cueCommandQueue)
+	errors := make(chan error, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		player.run(ctx, out, commands, func(err error) { errors <- err })
+		close(done)
+	}()
+
+	send := func(command CueCommand) {
+		sendCueCommand(clock, commands, done, command)
+	}
+	advance := func(d time.Duration) {
+		<-clock.After(d)
+	}
+
+	//	Act
+	send(CueCommand{Command: CueBack})
+	send(CueCommand{Command: CueGo})
+	send(CueCommand{Command: "GO"})
+	advance(100 * time.Millisecond)
+	afterGo, _ := playback.Status("act1")
+
+	send(CueCommand{Command: CueGo})
+	advance(100 * time.Millisecond)
+	afterFollow, _ := playback.Status("act1")
+
+	send(CueCommand{Command: CueGoto, Cue: 2})
+	advance(100 * time.Millisecond)
+	send(CueCommand{Command: CueGoto, Cue: 7})
+	send(CueCommand{Command: CueBack})
+	advance(20 * time.Millisecond)
+	afterBack, _ := playback.Status("act1")
+
+	cancel()
+	<-done
+
+	//	Assert
+	if len(errors) != 2 {
+		t.Errorf("run failed: Should report BACK before the first cue and GOTO a missing cue but got %v errors", len(errors))
+	}
+
+	if afterGo.Cue != 2 || afterGo.Next != 2.5 || afterGo.Fading || afterGo.CueName != "Lights up" {
+		t.Errorf("run failed: Should be on cue 2 after two GOs but got: %+v", afterGo)
+	}
+
+	if afterFollow.Cue != 3 || afterFollow.Next != 0 {
+		t.Errorf("run failed: Should auto follow cue 2.5 into cue 3 but got: %+v", afterFollow)
+	}
+
+	if afterBack.Cue != 1 {
+		t.Errorf("run failed: Should be back on cue 1 but got: %+v", afterBack)
+	}
+
+	//	The second GO replaces the first before it renders, so cue 2 fades
+	//	everything in over 50ms.  Cue 2.5 follows into cue 3 after 20ms, which
+	//	fades out over 50ms.
+	want := []clockedRender{
+		{0, [4]byte{0, 0, 0, 0}},
+		{25 * time.Millisecond, [4]byte{50, 128, 0, 0}},
+		{50 * time.Millisecond, [4]byte{100, 255, 0, 0}},
+		{100 * time.Millisecond, [4]byte{100, 255, 0, 0}},
+		{120 * time.Millisecond, [4]byte{100, 255, 0, 0}},
+		{145 * time.Millisecond, [4]byte{50, 128, 0, 0}},
+		{170 * time.Millisecond, [4]byte{0, 0, 0, 0}},
+		{200 * time.Millisecond, [4]byte{0, 0, 0, 0}},
+		{225 * time.Millisecond, [4]byte{50, 128, 0, 0}},
+		{250 * time.Millisecond, [4]byte{100, 255, 0, 0}},
+		{300 * time.Millisecond, [4]byte{100, 0, 0, 0}},
+	}
+	if !reflect.DeepEqual(out.renders, want) {
+		t.Errorf("run failed: Should render %v but got %v", want, out.renders)
+	}
+
+	if out.frame[17] != 0 {
+		t.Errorf("run failed: Should go back to cue 1's look but got channel 17 at %v", out.frame[17])
+	}
+}
+
+func TestCueList_Run_EditedCueList_PickedUpOnNextCommand(t *testing.T) {
+
+	//	Arrange
+	cueList := getTestCueList()
+	patch := getTestPatch()
+	load := func(cueList data2.CueList) ([][]data2.ChannelValue, error) {
+		return patch.resolveCues(cueList.Cues)
+	}
+
+	looks, err := load(cueList)
+	if err != nil {
+		t.Fatalf("resolveCues - Should resolve without error, but got: %s", err)
+	}
+
+	clock := NewVirtualClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	playback := NewCueListPlayback()
+	player := &cuePlayer{cueList: cueList, looks: looks, current: -1, playback: playback, clock: clock, load: load}
+	out := newClockedOutput(clock)
+	commands := make(chan CueCommand, cueCommandQueue)
+	errors := make(chan error, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		player.run(ctx, out, commands, func(err error) { errors <- err })
+		close(done)
+	}()
+
+	//	Insert a cue after cue 1
+	edited := getTestCueList()
+	edited.Cues = append([]data2.Cue{edited.Cues[0], {Number: 1.5, Name: "Inserted", Channels: []data2.ChannelValue{{Channel: 3, Value: 77}}}}, edited.Cues[1:]...)
+
+	//	Act
+	sendCueCommand(clock, commands, done, CueCommand{Command: CueGo, CueList: cueList})
+	<-clock.After(10 * time.Millisecond)
+	sendCueCommand(clock, commands, done, CueCommand{Command: CueGo, CueList: edited})
+	<-clock.After(10 * time.Millisecond)
+	status, _ := playback.Status("act1")
+
+	cancel()
+	<-done
+
+	//	Assert
+	if len(errors) != 0 {
+		t.Errorf("run failed: Should not report any errors but got %v", len(errors))
+	}
+
+	if status.Cue != 1.5 || status.CueName != "Inserted" || status.Next != 2 {
+		t.Errorf("run failed: Should GO from cue 1 to the inserted cue but got: %+v", status)
+	}
+
+	if out.frame[1] != 100 || out.frame[3] != 77 {
+		t.Errorf("run failed: Should render the edited cue list's look but got channels 1 and 3 at %v and %v", out.frame[1], out.frame[3])
+	}
+}
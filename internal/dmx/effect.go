@@ -0,0 +1,208 @@
+package dmx
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+// Effect waveform shapes
+const (
+	ShapeSine   = "sine"   // Smoothly up and down (starting at min)
+	ShapeSaw    = "saw"    // Ramps from min to max, then jumps back to min
+	ShapeSquare = "square" // Max for the first half of each cycle, min for the second
+	ShapeRandom = "random" // A new random level each cycle
+	ShapeStrobe = "strobe" // A single short flash at the start of each cycle
+)
+
+// effectTarget sets something (a raw channel or a fixture attribute) to a level (0.0 - 1.0)
+type effectTarget func(level float64) []data2.ChannelValue
+
+// effect is a waveform running on a single target
+type effect struct {
+	shape    string
+	rate     float64 // Cycles per second
+	min, max float64
+	phase    float64 // Where in the cycle the target starts (0.0 - 1.0)
+	seed     int64   // Seeds the random shapes, so each target is different (but the same every time it plays)
+	duration time.Duration
+	target   effectTarget
+}
+
+// levelAt is the level of the effect after it has been running for the elapsed time
+func (e effect) levelAt(elapsed time.Duration) float64 {
+	position := elapsed.Seconds()*e.rate + e.phase
+	cycle := math.Floor(position)
+	progress := position - cycle
+
+	wave := 0.0
+	switch e.shape {
+	case ShapeSine:
+		wave = (1 - math.Cos(2*math.Pi*progress)) / 2
+	case ShapeSaw:
+		wave = progress
+	case ShapeSquare:
+		if progress < 0.5 {
+			wave = 1
+		}
+	case ShapeRandom:
+		wave = noise(e.seed, int64(cycle))
+	case ShapeStrobe:
+		//	Flash for a single render (the shortest flash DMX will show), but never more than half the cycle
+		if progress < math.Min(renderInterval.Seconds()*e.rate, 0.5) {
+			wave = 1
+		}
+	case ShapeCandle:
+		wave = candle(e.seed, position)
+	case ShapeFire:
+		wave = fire(e.seed, position)
+	case ShapeLightning:
+		wave = lightning(e.seed, position, e.rate)
+	case ShapeTV:
+		wave = tv(e.seed, position)
+	case ShapeFluorescent:
+		wave = fluorescent(e.seed, position)
+	}
+
+	return e.min + (e.max-e.min)*wave
+}
+
+func (e effect) renderAt(state channelState, out dmxOutput, elapsed time.Duration) {
+	for _, value := range e.target(e.levelAt(elapsed)) {
+		state.set(out, value, target(value))
+	}
+}
+
+func (e effect) length() time.Duration {
+	return e.duration
+}
+
+// shapeChoices describes the shapes an effect can have
+const shapeChoices = "must be one of sine, saw, square, random, strobe, candle, fire, lightning, tv or fluorescent"
+
+// validShape returns true if an effect can have the (lower case) shape
+func validShape(shape string) bool {
+	switch shape {
+	case ShapeSine, ShapeSaw, ShapeSquare, ShapeRandom, ShapeStrobe:
+	case ShapeCandle, ShapeFire, ShapeLightning, ShapeTV, ShapeFluorescent:
+	default:
+		return false
+	}
+	return true
+}
+
+// noise is a repeatable random number (0.0 - 1.0) for a seed and a step
+func noise(seed, step int64) float64 {
+	//	splitmix64 (see https://prng.di.unimi.it/splitmix64.c)
+	x := uint64(seed)*0x9e3779b97f4a7c15 + uint64(step)
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return float64(x>>11) / (1 << 53)
+}
+
+// channelTarget sets a raw channel (16 bit if it has a fine channel)
+func channelTarget(value data2.ChannelValue) effectTarget {
+	return func(level float64) []data2.ChannelValue {
+		value.Value = byte(math.Round(level * 255))
+		value.Value16 = uint16(math.Round(level * 65535))
+		return []data2.ChannelValue{value}
+	}
+}
+
+// resolveEffect resolves an effect frame's channels and fixtures to the
+// effects that run on them, in order.  Each target's phase is offset from
+// the one before it.
+func (p Patch) resolveEffect(frame data2.TimelineFrame) ([]effect, error) {
+	settings := frame.Effect
+	if settings == nil {
+		return nil, fmt.Errorf("effect frames need an effect")
+	}
+
+	shape := strings.ToLower(settings.Shape)
+	if !validShape(shape) {
+		return nil, fmt.Errorf("effect shape '%s' %s", settings.Shape, shapeChoices)
+	}
+
+	//	Natural shapes have a default rate
+	rate := settings.Rate
+	if settings.BPM != 0 {
+		rate = settings.BPM / 60
+	}
+	if settings.Rate == 0 && settings.BPM == 0 {
+		rate = naturalRates[shape]
+	}
+	if rate <= 0 || (settings.Rate != 0 && settings.BPM != 0) {
+		return nil, fmt.Errorf("effect needs either a rate (in Hz) or bpm greater than 0")
+	}
+
+	min, max := settings.Min, settings.Max
+	if min == 0 && max == 0 {
+		max = 1
+	}
+	if min < 0 || min > 1 || max < 0 || max > 1 {
+		return nil, fmt.Errorf("effect min and max must be between 0.0 and 1.0")
+	}
+
+	if settings.Duration < 0 {
+		return nil, fmt.Errorf("effect duration can't be negative")
+	}
+
+	attribute := strings.ToLower(settings.Attribute)
+	if attribute == "" {
+		attribute = data2.AttributeDimmer
+	}
+
+	targets := []effectTarget{}
+	for _, fixtureValue := range frame.Fixtures {
+		fixtures, err := p.targets(fixtureValue, "")
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fixture := range fixtures {
+			item := fixture.item
+			if len(item.levels(attribute, 0)) == 0 {
+				return nil, fmt.Errorf("fixture '%s' doesn't have a '%s' channel", item.fixture.Name, attribute)
+			}
+
+			targets = append(targets, func(level float64) []data2.ChannelValue {
+				return item.levels(attribute, level)
+			})
+		}
+	}
+
+	for _, value := range frame.Channels {
+		targets = append(targets, channelTarget(value))
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("effect needs channels or fixtures to run on")
+	}
+
+	retval := make([]effect, len(targets))
+	for i, target := range targets {
+		//	Each target flickers on its own, but lightning lights everything at once
+		seed := settings.Seed<<16 + int64(i)
+		if shape == ShapeLightning {
+			seed = settings.Seed << 16
+		}
+
+		retval[i] = effect{
+			shape:    shape,
+			rate:     rate,
+			min:      min,
+			max:      max,
+			phase:    float64(i) * settings.Phase / 360,
+			seed:     seed,
+			duration: time.Duration(settings.Duration) * time.Millisecond,
+			target:   target,
+		}
+	}
+
+	return retval, nil
+}
@@ -0,0 +1,162 @@
+package dmx
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+func TestEffect_LevelAt_Shapes_Successful(t *testing.T) {
+
+	//	Arrange
+	tests := []struct {
+		shape   string
+		elapsed time.Duration
+		want    float64
+	}{
+		{ShapeSine, 0, 0.2},
+		{ShapeSine, 250 * time.Millisecond, 0.6},
+		{ShapeSine, 500 * time.Millisecond, 1.0},
+		{ShapeSaw, 250 * time.Millisecond, 0.4},
+		{ShapeSaw, 1250 * time.Millisecond, 0.4},
+		{ShapeSquare, 100 * time.Millisecond, 1.0},
+		{ShapeSquare, 600 * time.Millisecond, 0.2},
+		{ShapeStrobe, 10 * time.Millisecond, 1.0},
+		{ShapeStrobe, 100 * time.Millisecond, 0.2},
+	}
+
+	for _, test := range tests {
+		fx := effect{shape: test.shape, rate: 1, min: 0.2, max: 1.0}
+
+		//	Act
+		got := fx.levelAt(test.elapsed)
+
+		//	Assert
+		if math.Abs(got-test.want) > 0.0001 {
+			t.Errorf("levelAt failed: %s at %v should be %v but got %v", test.shape, test.elapsed, test.want, got)
+		}
+	}
+}
+
+func TestEffect_LevelAt_Random_Repeatable(t *testing.T) {
+
+	//	Arrange
+	fx := effect{shape: ShapeRandom, rate: 10, max: 1, seed: 3}
+	other := effect{shape: ShapeRandom, rate: 10, max: 1, seed: 4}
+
+	//	Act
+	first, again := fx.levelAt(150*time.Millisecond), fx.levelAt(150*time.Millisecond)
+	sameCycle := fx.levelAt(190 * time.Millisecond)
+
+	//	Assert
+	if first != again || first != sameCycle {
+		t.Errorf("levelAt failed: Should get the same random level for the same cycle but got %v, %v and %v", first, again, sameCycle)
+	}
+
+	if first == other.levelAt(150*time.Millisecond) {
+		t.Errorf("levelAt failed: Should get a different random level for a different seed but got %v for both", first)
+	}
+}
+
+func TestEffect_ResolveFrames_GroupPhase_Successful(t *testing.T) {
+
+	//	Arrange
+	patch := getTestPatch()
+	frame := data2.TimelineFrame{
+		Type:     "effect",
+		Fixtures: []data2.FixtureValue{{Group: "Pars", Attributes: map[string]interface{}{"color": "#ff0000"}}},
+		Channels: []data2.ChannelValue{{Channel: 100, Fine: 101}},
+		Effect:   &data2.Effect{Shape: "sine", BPM: 120, Phase: 90},
+	}
+
+	//	Act
+	frames, err := patch.resolveFrames([]data2.TimelineFrame{frame})
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("resolveFrames - Should resolve without error, but got: %s", err)
+	}
+
+	effects := frames[0].effects
+	if len(effects) != 4 || effects[0].rate != 2 || effects[1].phase != 0.25 || effects[3].phase != 0.75 {
+		t.Fatalf("resolveFrames failed: Should run the effect on each fixture in the group (then each channel) with its phase offset but got: %+v", effects)
+	}
+
+	//	Group order is left (17), center (25), right (21)
+	if values := effects[1].target(1); len(values) != 1 || values[0].Channel != 25 || values[0].Value != 255 {
+		t.Errorf("resolveFrames failed: Should run the effect on the second fixture's dimmer but got: %v", values)
+	}
+
+	if values := effects[3].target(0.5); values[0].Fine != 101 || values[0].Value16 != 32768 {
+		t.Errorf("resolveFrames failed: Should run the effect on the 16 bit channel but got: %v", values)
+	}
+
+	if got := channelMapOf(frames[0].Channels); got[18] != 255 || len(got) != 9 {
+		t.Errorf("resolveFrames failed: Should set the other fixture attributes in the frame but got: %v", frames[0].Channels)
+	}
+}
+
+func TestEffect_ResolveFrames_Errors_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	patch := getTestPatch()
+	channels := []data2.ChannelValue{{Channel: 1}}
+	tests := []data2.TimelineFrame{
+		{Type: "effect", Channels: channels},
+		{Type: "effect", Channels: channels, Effect: &data2.Effect{Shape: "wobble", Rate: 1}},
+		{Type: "effect", Channels: channels, Effect: &data2.Effect{Shape: "sine"}},
+		{Type: "effect", Channels: channels, Effect: &data2.Effect{Shape: "sine", Rate: 1, BPM: 60}},
+		{Type: "effect", Channels: channels, Effect: &data2.Effect{Shape: "sine", Rate: 1, Max: 2}},
+		{Type: "effect", Effect: &data2.Effect{Shape: "sine", Rate: 1}},
+		{Type: "effect", Fixtures: []data2.FixtureValue{{Fixture: "Mover"}}, Effect: &data2.Effect{Shape: "sine", Rate: 1, Attribute: "tilt"}},
+	}
+
+	for _, test := range tests {
+		//	Act
+		_, err := patch.resolveFrames([]data2.TimelineFrame{test})
+
+		//	Assert
+		if err == nil {
+			t.Errorf("resolveFrames - Should return error for %+v, but got none", test.Effect)
+		}
+	}
+}
+
+func TestEffect_PlayFrames_RunsThroughSleep_Successful(t *testing.T) {
+
+	//	Arrange
+	out := &testOutput{}
+	frames := getTestPlayFrames(t, []data2.TimelineFrame{
+		{Type: "effect", Channels: []data2.ChannelValue{{Channel: 1}}, Effect: &data2.Effect{Shape: "square", Rate: 10}},
+		{Type: "sleep", SleepTime: 200},
+		{Type: "scene", Channels: []data2.ChannelValue{{Channel: 1, Value: 7}}},
+		{Type: "sleep", SleepTime: 50},
+	})
+
+	//	Act
+	started := time.Now()
+	playFrames(context.Background(), out, frames)
+	took := time.Since(started)
+
+	//	Assert
+	on, off := 0, 0
+	for _, render := range out.renders[:len(out.renders)-1] {
+		switch render[1] {
+		case 255:
+			on++
+		case 0:
+			off++
+		}
+	}
+
+	if on < 2 || off < 2 {
+		t.Errorf("playFrames failed: Should keep running the effect while sleeping but got %v on and %v off renders", on, off)
+	}
+
+	if last := out.renders[len(out.renders)-1]; last[1] != 7 || took < 250*time.Millisecond {
+		t.Errorf("playFrames failed: Should stop the effect at the next frame but got %v after %v", last[1], took)
+	}
+}
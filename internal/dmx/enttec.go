@@ -0,0 +1,117 @@
+package dmx
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Enttec DMX USB Pro (and compatible widget) message framing.  See the
+// "DMX USB Pro Widget API" specification for details.
+const (
+	enttecStartOfMessage = 0x7E
+	enttecEndOfMessage   = 0xE7
+
+	enttecLabelReceivedDMX     = 5 // Widget -> host: a received DMX packet
+	enttecLabelSendDMX         = 6 // Host -> widget: output a DMX packet
+	enttecLabelReceiveOnChange = 8 // Host -> widget: set 'receive DMX on change' mode
+	enttecLabelChangeOfState   = 9 // Widget -> host: changed DMX channels
+
+	enttecMaxPayload = 600
+)
+
+// errEnttecFraming is returned when a malformed message is read from the widget
+var errEnttecFraming = errors.New("malformed widget message")
+
+// writeEnttecMessage writes a single framed message to the widget
+func writeEnttecMessage(w io.Writer, label byte, payload []byte) error {
+	msg := make([]byte, 0, len(payload)+5)
+	msg = append(msg, enttecStartOfMessage, label, byte(len(payload)&0xFF), byte(len(payload)>>8&0xFF))
+	msg = append(msg, payload...)
+	msg = append(msg, enttecEndOfMessage)
+
+	_, err := w.Write(msg)
+	return err
+}
+
+// readEnttecMessage reads the next framed message from the widget, skipping
+// any bytes that arrive before a start of message delimiter
+func readEnttecMessage(r *bufio.Reader) (byte, []byte, error) {
+	//	Find the start of the next message
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		if b == enttecStartOfMessage {
+			break
+		}
+	}
+
+	//	Read the label and payload length
+	header := make([]byte, 3)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+	label := header[0]
+	length := int(header[1]) | int(header[2])<<8
+	if length > enttecMaxPayload {
+		return 0, nil, fmt.Errorf("%w: length %v is too long", errEnttecFraming, length)
+	}
+
+	//	Read the payload and the end of message delimiter
+	payload := make([]byte, length+1)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	if payload[length] != enttecEndOfMessage {
+		return 0, nil, fmt.Errorf("%w: label %v is missing the end of message delimiter", errEnttecFraming, label)
+	}
+
+	return label, payload[:length], nil
+}
+
+// parseEnttecReceivedDMX parses a 'received DMX packet' payload and returns the
+// channel values (starting with channel 1).  Packets with a receive error status
+// or a non-zero start code are ignored.
+func parseEnttecReceivedDMX(payload []byte) ([]byte, bool) {
+	//	The first byte is the receive status, followed by the start code
+	if len(payload) < 2 || payload[0] != 0 || payload[1] != 0 {
+		return nil, false
+	}
+
+	return payload[2:], true
+}
+
+// parseEnttecChangeOfState parses a 'received DMX change of state' payload and
+// returns a map of channel -> value for each changed channel
+func parseEnttecChangeOfState(payload []byte) (map[int]byte, bool) {
+	//	Start block (1 byte), changed bit array (5 bytes), then the changed values
+	if len(payload) < 6 {
+		return nil, false
+	}
+
+	retval := map[int]byte{}
+	start := int(payload[0]) * 8
+	values := payload[6:]
+
+	for bit := 0; bit < 40; bit++ {
+		if payload[1+bit/8]&(1<<uint(bit%8)) == 0 {
+			continue
+		}
+
+		if len(values) == 0 {
+			return nil, false
+		}
+
+		//	Slot 0 is the start code -- every other slot is a channel
+		slot := start + bit
+		if slot > 0 && slot <= 512 {
+			retval[slot] = values[0]
+		}
+		values = values[1:]
+	}
+
+	return retval, true
+}
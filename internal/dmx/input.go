@@ -0,0 +1,270 @@
+package dmx
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+	"github.com/danesparza/fxdmx/internal/event"
+
+	serial "github.com/tarm/goserial"
+)
+
+const (
+	// InputSourceEnttec receives DMX from an Enttec DMX USB Pro compatible widget's input port
+	InputSourceEnttec = "enttec"
+
+	// InputSourceArtNet receives DMX from Art-Net (ArtDmx) packets
+	InputSourceArtNet = "artnet"
+
+	// InputSourceSACN receives DMX from sACN (E1.31) multicast packets
+	InputSourceSACN = "sacn"
+
+	enttecBaud = 57600
+)
+
+// InputRequest is a request to start receiving DMX input
+type InputRequest struct {
+	Source        string `json:"source"`            // The input source (enttec/artnet/sacn)
+	USBDevicePath string `json:"devpath,omitempty"` // The USB device to receive from.  Required if source = enttec
+	Universe      int    `json:"universe"`          // The universe to listen to.  Art-Net port-addresses start at 0, sACN universes start at 1
+	Bind          string `json:"bind,omitempty"`    // The address to listen on for Art-Net.  Optional.  Defaults to :6454
+}
+
+// InputChange is a set of channels that changed on the input universe
+type InputChange struct {
+	Received time.Time            `json:"received"` // When the change was received
+	Channels []data2.ChannelValue `json:"channels"` // The channels that changed (and their new values)
+}
+
+// InputState is a point in time copy of the input universe
+type InputState struct {
+	Source  string    `json:"source"`  // The source that last updated the universe
+	Updated time.Time `json:"updated"` // When the universe was last updated
+	Values  []int     `json:"values"`  // The channel values (index 0 is channel 1)
+}
+
+// InputUniverse tracks the most recently received DMX input values and
+// notifies subscribers when they change
+type InputUniverse struct {
+	values      [512]byte
+	source      string
+	updated     time.Time
+	subscribers map[chan InputChange]struct{}
+	listeners   map[int]func(InputChange)
+	listenerID  int
+	rwMutex     sync.RWMutex
+}
+
+// NewInputUniverse creates a new (empty) input universe
+func NewInputUniverse() *InputUniverse {
+	return &InputUniverse{
+		subscribers: make(map[chan InputChange]struct{}),
+		listeners:   make(map[int]func(InputChange)),
+	}
+}
+
+// Update applies received channel values (channel -> value) and notifies
+// subscribers of any channels that actually changed
+func (u *InputUniverse) Update(source string, values map[int]byte) {
+	u.rwMutex.Lock()
+	defer u.rwMutex.Unlock()
+
+	change := InputChange{Received: time.Now()}
+
+	for channel := 1; channel <= 512; channel++ {
+		value, exists := values[channel]
+		if !exists || u.values[channel-1] == value {
+			continue
+		}
+
+		u.values[channel-1] = value
+		change.Channels = append(change.Channels, data2.ChannelValue{Channel: channel, Value: value})
+	}
+
+	u.source = source
+	u.updated = change.Received
+
+	if len(change.Channels) == 0 {
+		return
+	}
+
+	//	Tell listeners (they never miss a change)
+	for _, listener := range u.listeners {
+		listener(change)
+	}
+
+	//	Notify subscribers (but don't let a slow subscriber block input)
+	for sub := range u.subscribers {
+		select {
+		case sub <- change:
+		default:
+		}
+	}
+}
+
+// Values returns a copy of the current channel values (index 0 is channel 1)
+func (u *InputUniverse) Values() [512]byte {
+	u.rwMutex.RLock()
+	defer u.rwMutex.RUnlock()
+
+	return u.values
+}
+
+// State returns a point in time copy of the input universe
+func (u *InputUniverse) State() InputState {
+	u.rwMutex.RLock()
+	defer u.rwMutex.RUnlock()
+
+	retval := InputState{
+		Source:  u.source,
+		Updated: u.updated,
+		Values:  make([]int, len(u.values)),
+	}
+
+	for i, value := range u.values {
+		retval.Values[i] = int(value)
+	}
+
+	return retval
+}
+
+// Listen calls a function with every input change from now on (as the
+// change is made, so it never misses one and needs to be quick).  It returns
+// the channel values as listening starts, along with a function to call to
+// stop listening.  Once that returns, the function won't be called again.
+func (u *InputUniverse) Listen(listener func(InputChange)) ([512]byte, func()) {
+	u.rwMutex.Lock()
+	defer u.rwMutex.Unlock()
+
+	u.listenerID++
+	id := u.listenerID
+	u.listeners[id] = listener
+
+	return u.values, func() {
+		u.rwMutex.Lock()
+		delete(u.listeners, id)
+		u.rwMutex.Unlock()
+	}
+}
+
+// Subscribe returns a channel that receives input changes, along with a
+// function to call to unsubscribe.  Changes are dropped if the subscriber falls behind
+// (use Listen to get every change).
+func (u *InputUniverse) Subscribe() (<-chan InputChange, func()) {
+	sub := make(chan InputChange, 64)
+
+	u.rwMutex.Lock()
+	u.subscribers[sub] = struct{}{}
+	u.rwMutex.Unlock()
+
+	return sub, func() {
+		u.rwMutex.Lock()
+		delete(u.subscribers, sub)
+		u.rwMutex.Unlock()
+	}
+}
+
+// ReceiveInput receives DMX input from the requested source until the context is canceled
+func (bp *BackgroundProcess) ReceiveInput(ctx context.Context, req InputRequest) {
+	bp.DB.AddEvent(event.InputStarted, fmt.Sprintf("Receiving DMX input: %+v", req), "", bp.HistoryTTL)
+
+	var err error
+	switch strings.ToLower(req.Source) {
+	case InputSourceEnttec:
+		err = bp.receiveEnttec(ctx, req)
+	case InputSourceArtNet:
+		err = bp.receiveArtNet(ctx, req)
+	case InputSourceSACN:
+		err = bp.receiveSACN(ctx, req)
+	default:
+		err = fmt.Errorf("unknown input source '%v'", req.Source)
+	}
+
+	if err != nil {
+		bp.DB.AddEvent(event.InputError, fmt.Sprintf("An error occurred receiving DMX input: %v", err), "", bp.HistoryTTL)
+		return
+	}
+
+	bp.DB.AddEvent(event.InputStopped, fmt.Sprintf("Stopped receiving DMX input from %v", req.Source), "", bp.HistoryTTL)
+}
+
+// receiveEnttec puts the widget in 'receive DMX on change' mode and applies
+// changes to the input universe until the context is canceled
+func (bp *BackgroundProcess) receiveEnttec(ctx context.Context, req InputRequest) error {
+	//	First, see if the request has a device set on it.  If it doesn't, use the default
+	if strings.TrimSpace(req.USBDevicePath) == "" {
+		defaultDevice, err := bp.DB.GetDefaultUSBDev()
+		if err != nil {
+			return fmt.Errorf("an error occurred trying to get the default USB device: %v", err)
+		}
+		req.USBDevicePath = defaultDevice
+	}
+
+	port, err := serial.OpenPort(&serial.Config{Name: req.USBDevicePath, Baud: enttecBaud})
+	if err != nil {
+		return fmt.Errorf("unable to connect to DMX512 interface %v: %v", req.USBDevicePath, err)
+	}
+
+	defer port.Close()
+
+	//	Closing the port unblocks the read loop below
+	stopped := make(chan struct{})
+	defer close(stopped)
+	go func() {
+		select {
+		case <-ctx.Done():
+			port.Close()
+		case <-stopped:
+		}
+	}()
+
+	//	Only send us the channels that change
+	if err := writeEnttecMessage(port, enttecLabelReceiveOnChange, []byte{1}); err != nil {
+		return fmt.Errorf("unable to set receive on change mode: %v", err)
+	}
+
+	reader := bufio.NewReader(port)
+	for {
+		label, payload, err := readEnttecMessage(reader)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			//	Skip past malformed messages -- anything else means the port is gone
+			if errors.Is(err, errEnttecFraming) {
+				continue
+			}
+			return err
+		}
+
+		switch label {
+		case enttecLabelReceivedDMX:
+			if values, ok := parseEnttecReceivedDMX(payload); ok {
+				bp.Input.Update(InputSourceEnttec, channelMap(values))
+			}
+		case enttecLabelChangeOfState:
+			if values, ok := parseEnttecChangeOfState(payload); ok {
+				bp.Input.Update(InputSourceEnttec, values)
+			}
+		}
+	}
+}
+
+// channelMap converts a slice of channel values (starting with channel 1) to a map of channel -> value
+func channelMap(values []byte) map[int]byte {
+	retval := make(map[int]byte, len(values))
+	for i, value := range values {
+		if i >= 512 {
+			break
+		}
+		retval[i+1] = value
+	}
+	return retval
+}
@@ -0,0 +1,141 @@
+package dmx
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+)
+
+func TestInput_ParseArtDmx_ValidPacket_Successful(t *testing.T) {
+
+	//	Arrange
+	packet := append([]byte("Art-Net\x00"),
+		0x00, 0x50, // OpDmx (little endian)
+		0x00, 14, // Protocol version
+		0x01, 0x00, // Sequence, physical
+		0x03, 0x01, // SubUni, Net
+		0x00, 0x04, // Length (big endian)
+		10, 20, 30, 40)
+
+	//	Act
+	universe, values, ok := parseArtDmx(packet)
+
+	//	Assert
+	if !ok {
+		t.Fatalf("parseArtDmx - Should parse a valid packet")
+	}
+
+	if universe != 0x103 {
+		t.Errorf("parseArtDmx failed: Should get port-address 0x103 but got: %x", universe)
+	}
+
+	if !bytes.Equal(values, []byte{10, 20, 30, 40}) {
+		t.Errorf("parseArtDmx failed: Should get channel values but got: %v", values)
+	}
+}
+
+func TestInput_ParseArtDmx_OtherOpCode_ReturnsNotOk(t *testing.T) {
+
+	//	Arrange
+	packet := append([]byte("Art-Net\x00"), 0x00, 0x20, 0x00, 14, 0, 0, 0, 0, 0, 0)
+
+	//	Act
+	_, _, ok := parseArtDmx(packet)
+
+	//	Assert
+	if ok {
+		t.Errorf("parseArtDmx - Should ignore non-ArtDmx packets")
+	}
+}
+
+func TestInput_ParseSACN_ValidPacket_Successful(t *testing.T) {
+
+	//	Arrange
+	packet := make([]byte, sacnOffsetStartCode+4)
+	copy(packet[4:], sacnPacketID)
+	packet[sacnOffsetRootVector+3] = 0x04
+	packet[sacnOffsetFrameVector+3] = 0x02
+	packet[sacnOffsetUniverse] = 0x00
+	packet[sacnOffsetUniverse+1] = 0x07
+	packet[sacnOffsetDMPVector] = 0x02
+	packet[sacnOffsetValueCount+1] = 4 // Start code + 3 channels
+	copy(packet[sacnOffsetStartCode:], []byte{0, 255, 128, 1})
+
+	//	Act
+	universe, values, ok := parseSACN(packet)
+
+	//	Assert
+	if !ok {
+		t.Fatalf("parseSACN - Should parse a valid packet")
+	}
+
+	if universe != 7 {
+		t.Errorf("parseSACN failed: Should get universe 7 but got: %v", universe)
+	}
+
+	if !bytes.Equal(values, []byte{255, 128, 1}) {
+		t.Errorf("parseSACN failed: Should get channel values but got: %v", values)
+	}
+}
+
+func TestInput_ParseEnttecChangeOfState_ValidPayload_Successful(t *testing.T) {
+
+	//	Arrange
+	//	Block 1 (slots 8-47), with bits 0 and 2 set -> channels 8 and 10
+	payload := []byte{1, 0x05, 0, 0, 0, 0, 100, 200}
+
+	//	Act
+	values, ok := parseEnttecChangeOfState(payload)
+
+	//	Assert
+	if !ok {
+		t.Fatalf("parseEnttecChangeOfState - Should parse a valid payload")
+	}
+
+	if len(values) != 2 || values[8] != 100 || values[10] != 200 {
+		t.Errorf("parseEnttecChangeOfState failed: Should get channels 8 and 10 but got: %v", values)
+	}
+}
+
+func TestInput_ReadEnttecMessage_SkipsLeadingBytes_Successful(t *testing.T) {
+
+	//	Arrange
+	var buf bytes.Buffer
+	buf.Write([]byte{0x01, 0x02})
+	writeEnttecMessage(&buf, enttecLabelReceivedDMX, []byte{0, 0, 42})
+
+	//	Act
+	label, payload, err := readEnttecMessage(bufio.NewReader(&buf))
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("readEnttecMessage - Should read message without error, but got: %s", err)
+	}
+
+	if label != enttecLabelReceivedDMX || !bytes.Equal(payload, []byte{0, 0, 42}) {
+		t.Errorf("readEnttecMessage failed: Should get the written message but got: %v %v", label, payload)
+	}
+}
+
+func TestInput_Update_NotifiesChangedChannelsOnly(t *testing.T) {
+
+	//	Arrange
+	universe := NewInputUniverse()
+	universe.Update(InputSourceArtNet, map[int]byte{1: 10, 2: 20})
+
+	changes, unsubscribe := universe.Subscribe()
+	defer unsubscribe()
+
+	//	Act
+	universe.Update(InputSourceArtNet, map[int]byte{1: 10, 2: 25})
+
+	//	Assert
+	change := <-changes
+	if len(change.Channels) != 1 || change.Channels[0].Channel != 2 || change.Channels[0].Value != 25 {
+		t.Errorf("Update failed: Should only notify changed channels but got: %+v", change.Channels)
+	}
+
+	if universe.Values()[1] != 25 {
+		t.Errorf("Update failed: Should track the new value but got: %v", universe.Values()[1])
+	}
+}
@@ -0,0 +1,121 @@
+package dmx
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/danesparza/fxdmx/internal/color"
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+// keyframeChannel is a channel's keyframes in a keyframe frame
+type keyframeChannel struct {
+	value  data2.ChannelValue // The channel (and fine channel) the keyframes set
+	from   uint16             // The channel's level before its first keyframe
+	points []keyPoint         // In time order
+}
+
+// keyPoint is a channel's level at a keyframe
+type keyPoint struct {
+	at    time.Duration
+	level uint16
+	snap  bool
+}
+
+// levelAt is the level of the channel at the elapsed time.  The channel holds
+// its level until its first keyframe, then fades from each keyframe to the next.
+func (k keyframeChannel) levelAt(elapsed time.Duration) uint16 {
+	level := k.from
+	for i, point := range k.points {
+		if elapsed < point.at {
+			if i == 0 || point.snap {
+				return level
+			}
+
+			before := k.points[i-1]
+			progress := float64(elapsed-before.at) / float64(point.at-before.at)
+			return uint16(math.Round(float64(before.level) + (float64(point.level)-float64(before.level))*progress))
+		}
+		level = point.level
+	}
+
+	return level
+}
+
+func (k keyframeChannel) renderAt(state channelState, out dmxOutput, elapsed time.Duration) {
+	state.set(out, k.value, k.levelAt(elapsed))
+}
+
+func (k keyframeChannel) length() time.Duration {
+	return k.points[len(k.points)-1].at
+}
+
+// resolveKeyframes checks a keyframe frame and resolves each keyframe's look
+// to the keyframes of every channel it sets (in the order channels are first set)
+func (p Patch) resolveKeyframes(frame data2.TimelineFrame, colors map[string]color.RGB) ([]keyframeChannel, error) {
+	if len(frame.Keyframes) < 1 {
+		return nil, fmt.Errorf("keyframes must contain at least one item")
+	}
+
+	if len(frame.Channels) > 0 || len(frame.Fixtures) > 0 || len(frame.Presets) > 0 {
+		return nil, fmt.Errorf("keyframe frames set channels, fixtures and presets in their keyframes")
+	}
+
+	retval := []keyframeChannel{}
+	index := map[int]int{}
+
+	for i, keyframe := range frame.Keyframes {
+		if keyframe.Time < 0 {
+			return nil, fmt.Errorf("keyframe %v time can't be negative", i+1)
+		}
+		if i > 0 && keyframe.Time <= frame.Keyframes[i-1].Time {
+			return nil, fmt.Errorf("keyframe %v time must be after keyframe %v", i+1, i)
+		}
+		if len(keyframe.Channels) == 0 && len(keyframe.Fixtures) == 0 && len(keyframe.Presets) == 0 {
+			return nil, fmt.Errorf("keyframe %v needs channels, fixtures or presets", i+1)
+		}
+
+		resolved, err := p.resolveFrame(data2.TimelineFrame{Type: "scene", Channels: keyframe.Channels, Fixtures: keyframe.Fixtures, Presets: keyframe.Presets}, colors)
+		if err != nil {
+			return nil, fmt.Errorf("keyframe %v: %v", i+1, err)
+		}
+
+		point := keyPoint{at: time.Duration(keyframe.Time) * time.Millisecond, snap: keyframe.Snap}
+		for _, value := range resolved.Channels {
+			n, exists := index[value.Channel]
+			if !exists {
+				n = len(retval)
+				index[value.Channel] = n
+				retval = append(retval, keyframeChannel{value: value})
+			}
+
+			if retval[n].value.Fine != value.Fine {
+				return nil, fmt.Errorf("keyframe %v: channel %v must use the same fine channel in every keyframe", i+1, value.Channel)
+			}
+
+			point.level = target(value)
+			retval[n].points = append(retval[n].points, point)
+		}
+	}
+
+	return retval, nil
+}
+
+// playKeyframes plays a keyframe frame.  Every channel's level is worked out
+// from the time since the frame started, so keyframes land on time however
+// long the frame is.  It returns false if it was stopped before it finished.
+func (p *player) playKeyframes(ctx context.Context, frame playFrame) bool {
+	faders := make([]fader, len(frame.keyframes))
+	longest := time.Duration(0)
+	for i, channel := range frame.keyframes {
+		channel.from = p.state.level(channel.value)
+		faders[i] = channel
+		longest = max(longest, channel.length())
+	}
+
+	started := p.cursor
+	p.cursor = started.Add(longest)
+	return p.renderUntil(ctx, faders, started, p.cursor)
+}
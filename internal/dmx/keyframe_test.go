@@ -0,0 +1,144 @@
+package dmx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+func TestKeyframe_LevelAt_Interpolates(t *testing.T) {
+
+	//	Arrange
+	channel := keyframeChannel{
+		value: data2.ChannelValue{Channel: 1},
+		from:  50,
+		points: []keyPoint{
+			{at: 100 * time.Millisecond, level: 0},
+			{at: 300 * time.Millisecond, level: 200},
+			{at: 400 * time.Millisecond, level: 20, snap: true},
+		},
+	}
+	tests := []struct {
+		elapsed time.Duration
+		want    uint16
+	}{
+		{0, 50},
+		{99 * time.Millisecond, 50},
+		{100 * time.Millisecond, 0},
+		{200 * time.Millisecond, 100},
+		{350 * time.Millisecond, 200},
+		{400 * time.Millisecond, 20},
+		{time.Hour, 20},
+	}
+
+	for _, test := range tests {
+		//	Act
+		got := channel.levelAt(test.elapsed)
+
+		//	Assert
+		if got != test.want {
+			t.Errorf("levelAt failed: At %v should be %v but got %v", test.elapsed, test.want, got)
+		}
+	}
+}
+
+func TestKeyframe_ResolveFrames_Fixtures_Successful(t *testing.T) {
+
+	//	Arrange
+	frame := data2.TimelineFrame{Type: "keyframes", Keyframes: []data2.Keyframe{
+		{Time: 0, Fixtures: []data2.FixtureValue{{Fixture: "Stage Left Par", Attributes: map[string]interface{}{"dimmer": 0.0}}}},
+		{Time: 12350, Channels: []data2.ChannelValue{{Channel: 1, Value: 255}}},
+		{Time: 15000, Fixtures: []data2.FixtureValue{{Fixture: "Stage Left Par", Attributes: map[string]interface{}{"dimmer": 1.0}}}},
+	}}
+
+	//	Act
+	resolved, err := getTestPatch().resolveFrames([]data2.TimelineFrame{frame})
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("resolveFrames - Should resolve without error, but got: %s", err)
+	}
+
+	channels := resolved[0].keyframes
+	if len(channels) != 2 || channels[0].value.Channel != 17 || channels[1].value.Channel != 1 {
+		t.Fatalf("resolveFrames failed: Should have keyframes for channels 17 and 1 but got %+v", channels)
+	}
+
+	if len(channels[0].points) != 2 || channels[0].points[1].at != 15*time.Second || channels[0].points[1].level != 255 {
+		t.Errorf("resolveFrames failed: Should have channel 17 at 255 after 15 seconds but got %+v", channels[0].points)
+	}
+
+	if channels[1].points[0].at != 12350*time.Millisecond {
+		t.Errorf("resolveFrames failed: Should have channel 1 keyframe at 12.35 seconds but got %v", channels[1].points[0].at)
+	}
+}
+
+func TestKeyframe_ResolveFrames_Errors_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	channels := []data2.ChannelValue{{Channel: 1, Value: 255}}
+	tests := [][]data2.Keyframe{
+		{},
+		{{Time: -1, Channels: channels}},
+		{{Time: 100, Channels: channels}, {Time: 100, Channels: channels}},
+		{{Time: 100}},
+		{{Time: 100, Fixtures: []data2.FixtureValue{{Fixture: "Nobody"}}}},
+		{{Time: 0, Channels: channels}, {Time: 100, Channels: []data2.ChannelValue{{Channel: 1, Fine: 2, Value16: 300}}}},
+	}
+
+	for _, test := range tests {
+		//	Act
+		_, err := getTestPatch().resolveFrames([]data2.TimelineFrame{{Type: "keyframes", Keyframes: test}})
+
+		//	Assert
+		if err == nil {
+			t.Errorf("resolveFrames - Should return error for %+v, but got none", test)
+		}
+	}
+}
+
+func TestKeyframe_PlayFrames_LandsOnKeyframes(t *testing.T) {
+
+	//	Arrange
+	out := &testOutput{}
+	frames := getTestPlayFrames(t, []data2.TimelineFrame{
+		{Type: "keyframes", Keyframes: []data2.Keyframe{
+			{Time: 0, Channels: []data2.ChannelValue{{Channel: 1, Value: 0}}},
+			{Time: 40, Channels: []data2.ChannelValue{{Channel: 2, Value: 100}}, Snap: true},
+			{Time: 80, Channels: []data2.ChannelValue{{Channel: 1, Value: 255}}},
+		}},
+	})
+
+	//	Act
+	started := time.Now()
+	finished := playFrames(context.Background(), out, frames)
+
+	//	Assert
+	if !finished {
+		t.Fatalf("playFrames - Should finish playing, but it was stopped")
+	}
+
+	if time.Since(started) < 80*time.Millisecond {
+		t.Errorf("playFrames failed: Should play until the last keyframe")
+	}
+
+	faded := false
+	for _, render := range out.renders {
+		if render[1] > 0 && render[1] < 255 {
+			faded = true
+		}
+		if render[2] != 0 && render[2] != 100 {
+			t.Fatalf("playFrames failed: Should snap channel 2 to its keyframe but got %v", render[2])
+		}
+	}
+	if !faded {
+		t.Errorf("playFrames failed: Should fade channel 1 between its keyframes")
+	}
+
+	last := out.renders[len(out.renders)-1]
+	if last[1] != 255 || last[2] != 100 {
+		t.Errorf("playFrames failed: Should end on the last keyframe but got %v and %v", last[1], last[2])
+	}
+}
@@ -0,0 +1,116 @@
+package dmx
+
+import (
+	"math"
+	"time"
+)
+
+// Natural effect shapes.  These use seeded random numbers, so an effect with
+// the same seed plays the same way every time.
+const (
+	ShapeCandle      = "candle"      // A gentle, mostly bright flicker
+	ShapeFire        = "fire"        // A deeper, faster flicker
+	ShapeLightning   = "lightning"   // Bursts of bright flashes at random intervals
+	ShapeTV          = "tv"          // Brightness jumps at each (random) scene cut, with a little flicker
+	ShapeFluorescent = "fluorescent" // Sputters a few times, then stays on
+)
+
+// naturalRates are the default rates (in Hz) of the natural shapes.  Rate
+// means how fast a flame flickers, how often lightning strikes (on average),
+// how often a TV changes scenes and how long a fluorescent tube takes to start.
+var naturalRates = map[string]float64{
+	ShapeCandle:      2,
+	ShapeFire:        4,
+	ShapeLightning:   0.2,
+	ShapeTV:          0.5,
+	ShapeFluorescent: 0.5,
+}
+
+// smoothNoise is random noise that changes smoothly from one whole position to the next
+func smoothNoise(seed int64, position float64) float64 {
+	step := math.Floor(position)
+	from, to := noise(seed, int64(step)), noise(seed, int64(step)+1)
+
+	blend := (1 - math.Cos(math.Pi*(position-step))) / 2
+	return from + (to-from)*blend
+}
+
+// candle flickers gently, with the odd dip when the flame gutters
+func candle(seed int64, position float64) float64 {
+	level := 0.7 + 0.2*smoothNoise(seed, position) + 0.1*smoothNoise(layer(seed, 1), position*3.1)
+
+	if dip := noise(layer(seed, 2), int64(position)); dip > 0.9 {
+		level -= 0.25 * math.Sin(math.Pi*(position-math.Floor(position)))
+	}
+
+	return clampLevel(level)
+}
+
+// fire flickers more deeply (and faster) than a candle
+func fire(seed int64, position float64) float64 {
+	level := 0.35 + 0.35*smoothNoise(seed, position) + 0.2*smoothNoise(layer(seed, 1), position*2.7) + 0.1*noise(layer(seed, 2), int64(position*8))
+	return clampLevel(level)
+}
+
+// lightning strikes once in each cycle, at a random time, with a burst of
+// one to three flashes that get dimmer
+func lightning(seed int64, position float64, rate float64) float64 {
+	cycle := math.Floor(position)
+
+	//	When in the cycle the strike starts (in seconds since the start of the cycle)
+	strike := noise(seed, int64(cycle)) * 0.7 / rate
+	since := time.Duration(((position-cycle)/rate - strike) * float64(time.Second))
+	if since < 0 {
+		return 0
+	}
+
+	//	Each flash is on for 50ms, then off for 80ms
+	const flash, gap = 50 * time.Millisecond, 80 * time.Millisecond
+	flashes := 1 + int(noise(layer(seed, 1), int64(cycle))*3)
+
+	n := int(since / (flash + gap))
+	if n >= flashes || since%(flash+gap) >= flash {
+		return 0
+	}
+
+	return 1 - 0.3*float64(n)*noise(layer(seed, 2), int64(cycle))
+}
+
+// tv holds a random brightness for each scene, with a little flicker.  Scene
+// lengths vary between half and one and a half cycles.
+func tv(seed int64, position float64) float64 {
+	scene := math.Floor(position)
+	cut := scene + (noise(layer(seed, 1), int64(scene))-0.5)/2
+	if position < cut {
+		scene--
+	}
+
+	level := 0.3 + 0.6*noise(seed, int64(scene)) + 0.1*smoothNoise(layer(seed, 2), position*20)
+	return clampLevel(level)
+}
+
+// fluorescent sputters on and off (with a dim glow) more and more often
+// during the first cycle, then stays on
+func fluorescent(seed int64, position float64) float64 {
+	if position >= 1 {
+		return 1
+	}
+
+	//	Sputter in 60ms steps (at the default rate)
+	step := int64(position * 33)
+	if noise(seed, step) > 0.85-0.6*position {
+		return 1
+	}
+
+	return 0.1
+}
+
+// layer gets a seed for another layer of noise (so layers don't line up with
+// the next target's seed)
+func layer(seed int64, n int64) int64 {
+	return seed + n<<48
+}
+
+func clampLevel(level float64) float64 {
+	return math.Max(0, math.Min(1, level))
+}
@@ -0,0 +1,143 @@
+package dmx
+
+import (
+	"testing"
+	"time"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+// levelsOf samples an effect's level every 10ms for a length of time
+func levelsOf(fx effect, length time.Duration) []float64 {
+	retval := []float64{}
+	for elapsed := time.Duration(0); elapsed < length; elapsed += 10 * time.Millisecond {
+		retval = append(retval, fx.levelAt(elapsed))
+	}
+	return retval
+}
+
+func TestNatural_LevelAt_Candle_SeededAndBright(t *testing.T) {
+
+	//	Arrange
+	fx := effect{shape: ShapeCandle, rate: naturalRates[ShapeCandle], max: 1, seed: 42}
+	other := fx
+	other.seed = 43
+
+	//	Act
+	levels := levelsOf(fx, 10*time.Second)
+	again := levelsOf(fx, 10*time.Second)
+	otherLevels := levelsOf(other, 10*time.Second)
+
+	//	Assert
+	total, different := 0.0, 0
+	for i, level := range levels {
+		if level < 0 || level > 1 {
+			t.Fatalf("levelAt failed: Should stay between min and max but got %v", level)
+		}
+		if level != again[i] {
+			t.Fatalf("levelAt failed: Should flicker the same way every time for the same seed")
+		}
+		if level != otherLevels[i] {
+			different++
+		}
+		total += level
+	}
+
+	if average := total / float64(len(levels)); average < 0.6 {
+		t.Errorf("levelAt failed: Should be mostly bright but got an average of %v", average)
+	}
+
+	if different < len(levels)/2 {
+		t.Errorf("levelAt failed: Should flicker differently for a different seed but only %v of %v levels were different", different, len(levels))
+	}
+}
+
+func TestNatural_LevelAt_Lightning_StrikesEachCycle(t *testing.T) {
+
+	//	Arrange
+	fx := effect{shape: ShapeLightning, rate: naturalRates[ShapeLightning], max: 1, seed: 7}
+
+	//	Act
+	levels := levelsOf(fx, 60*time.Second)
+
+	//	Assert
+	strikes, dark := 0, 0
+	lastFlash := -1000
+	for i, level := range levels {
+		if level == 0 {
+			dark++
+			continue
+		}
+
+		//	A flash more than 500ms after the last one is a new strike
+		if i-lastFlash > 50 {
+			strikes++
+		}
+		lastFlash = i
+	}
+
+	if strikes != 12 {
+		t.Errorf("levelAt failed: Should strike once every 5 seconds (on average) but got %v strikes in a minute", strikes)
+	}
+
+	if dark < len(levels)*9/10 {
+		t.Errorf("levelAt failed: Should be dark most of the time but got %v of %v dark levels", dark, len(levels))
+	}
+}
+
+func TestNatural_LevelAt_Fluorescent_StartsThenStaysOn(t *testing.T) {
+
+	//	Arrange
+	fx := effect{shape: ShapeFluorescent, rate: naturalRates[ShapeFluorescent], max: 1, seed: 1}
+
+	//	Act
+	starting := levelsOf(fx, 2*time.Second)
+	started := levelsOf(effect{shape: ShapeFluorescent, rate: naturalRates[ShapeFluorescent], max: 1, seed: 1, phase: 1}, 5*time.Second)
+
+	//	Assert
+	on, glowing := 0, 0
+	for _, level := range starting {
+		switch level {
+		case 1:
+			on++
+		case 0.1:
+			glowing++
+		}
+	}
+
+	if on == 0 || glowing == 0 || on+glowing != len(starting) {
+		t.Errorf("levelAt failed: Should sputter between on and a dim glow while starting but got %v on and %v glowing", on, glowing)
+	}
+
+	for _, level := range started {
+		if level != 1 {
+			t.Fatalf("levelAt failed: Should stay on once started but got %v", level)
+		}
+	}
+}
+
+func TestNatural_ResolveFrames_DefaultRateAndSeeds_Successful(t *testing.T) {
+
+	//	Arrange
+	patch := getTestPatch()
+	fixtures := []data2.FixtureValue{{Group: "Pars"}}
+
+	//	Act
+	candles, err := patch.resolveFrames([]data2.TimelineFrame{{Type: "effect", Fixtures: fixtures, Effect: &data2.Effect{Shape: "Candle", Seed: 5}}})
+	if err != nil {
+		t.Fatalf("resolveFrames - Should resolve without error, but got: %s", err)
+	}
+	strikes, err := patch.resolveFrames([]data2.TimelineFrame{{Type: "effect", Fixtures: fixtures, Effect: &data2.Effect{Shape: "lightning", Seed: 5}}})
+	if err != nil {
+		t.Fatalf("resolveFrames - Should resolve without error, but got: %s", err)
+	}
+
+	//	Assert
+	if fx := candles[0].effects; fx[0].rate != 2 || fx[0].seed == fx[1].seed {
+		t.Errorf("resolveFrames failed: Should use the default rate and flicker each fixture on its own but got: %+v", fx)
+	}
+
+	if fx := strikes[0].effects; fx[0].seed != fx[2].seed {
+		t.Errorf("resolveFrames failed: Should strike every fixture at once but got: %+v", fx)
+	}
+}
@@ -0,0 +1,100 @@
+package dmx
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+// resolveTimeline resolves a timeline's tracks (and those of any timelines it
+// plays).  The timeline's own frames are the first track.
+func (p Patch) resolveTimeline(timeline data2.Timeline) ([][]playFrame, error) {
+	p.calling = append(slices.Clip(p.calling), timeline.ID)
+
+	frames, err := p.resolveFrames(timeline.Frames)
+	if err != nil {
+		return nil, err
+	}
+
+	retval := [][]playFrame{frames}
+	for i, track := range timeline.Tracks {
+		frames, err := p.resolveFrames(track.Frames)
+		if err != nil {
+			return nil, fmt.Errorf("track %v %v", i+1, err)
+		}
+		retval = append(retval, frames)
+	}
+
+	return retval, nil
+}
+
+// resolveTimelineFrame checks a timeline frame and resolves the tracks of the
+// timeline it plays
+func (p Patch) resolveTimelineFrame(frame data2.TimelineFrame) ([][]playFrame, error) {
+	if strings.TrimSpace(frame.Timeline) == "" {
+		return nil, fmt.Errorf("timeline frames need a timeline")
+	}
+
+	if len(frame.Channels) > 0 || len(frame.Fixtures) > 0 || len(frame.Presets) > 0 {
+		return nil, fmt.Errorf("timeline frames can't set channels, fixtures or presets")
+	}
+
+	timeline, found := p.timelines[frame.Timeline]
+	if !found {
+		return nil, fmt.Errorf("timeline '%s' doesn't exist", frame.Timeline)
+	}
+
+	if slices.Contains(p.calling, timeline.ID) {
+		return nil, fmt.Errorf("timeline '%s' plays itself", timeline.Name)
+	}
+
+	retval, err := p.resolveTimeline(timeline)
+	if err != nil {
+		return nil, fmt.Errorf("timeline '%s' %v", timeline.Name, err)
+	}
+
+	return retval, nil
+}
+
+// sharedOutput is an output that players running in parallel render to
+type sharedOutput struct {
+	out   dmxOutput
+	mutex sync.Mutex
+}
+
+func (s *sharedOutput) SetChannel(channel int, value byte) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.out.SetChannel(channel, value)
+}
+
+func (s *sharedOutput) Render() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.out.Render()
+}
+
+// spawn plays tracks alongside the player's own frames, on the same output
+// and clock.  The tracks start when the player's next frame is due (from its
+// current channel state) and stop when the player is stopped.  They're
+// added to the group, so the player can wait for them.
+func (p *player) spawn(ctx context.Context, tracks [][]playFrame, group *playerGroup) *player {
+	if _, shared := p.out.(*sharedOutput); !shared {
+		p.out = &sharedOutput{out: p.out}
+	}
+
+	child := &player{out: p.out, state: maps.Clone(p.state), clock: p.clock, cursor: p.cursor}
+
+	group.add(p.clock)
+	go func() {
+		defer group.finished(p.clock)
+		child.playAll(ctx, tracks)
+	}()
+
+	return child
+}
@@ -0,0 +1,220 @@
+package dmx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+// getTestNestedPatch gets a patch with timelines that timeline frames can play
+func getTestNestedPatch() Patch {
+	patch := getTestPatch()
+	patch.timelines = map[string]data2.Timeline{
+		"flash": {ID: "flash", Name: "Flash", Frames: []data2.TimelineFrame{
+			{Type: "scene", Channels: []data2.ChannelValue{{Channel: 1, Value: 255}}},
+			{Type: "sleep", SleepTime: 40},
+			{Type: "scene", Channels: []data2.ChannelValue{{Channel: 1, Value: 0}}},
+		}},
+		"outer": {ID: "outer", Name: "Outer", Frames: []data2.TimelineFrame{{Type: "timeline", Timeline: "inner"}}},
+		"inner": {ID: "inner", Name: "Inner", Frames: []data2.TimelineFrame{{Type: "timeline", Timeline: "outer"}}},
+		"broken": {ID: "broken", Name: "Broken", Frames: []data2.TimelineFrame{
+			{Type: "scene", Fixtures: []data2.FixtureValue{{Fixture: "Nobody"}}},
+		}},
+	}
+	return patch
+}
+
+func TestNested_ResolveFrames_Errors_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	patch := getTestNestedPatch()
+	tests := []data2.TimelineFrame{
+		{Type: "timeline"},
+		{Type: "timeline", Timeline: "missing"},
+		{Type: "timeline", Timeline: "outer"},
+		{Type: "timeline", Timeline: "broken"},
+		{Type: "timeline", Timeline: "flash", Channels: []data2.ChannelValue{{Channel: 2}}},
+	}
+
+	for _, test := range tests {
+		//	Act
+		_, err := patch.resolveFrames([]data2.TimelineFrame{test})
+
+		//	Assert
+		if err == nil {
+			t.Errorf("resolveFrames - Should return error for timeline '%s', but got none", test.Timeline)
+		}
+	}
+}
+
+func TestNested_ResolveTimeline_PlaysItself_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	patch := getTestNestedPatch()
+	timeline := data2.Timeline{ID: "flash", Name: "Flash", Frames: []data2.TimelineFrame{{Type: "timeline", Timeline: "flash"}}}
+
+	//	Act
+	_, err := patch.resolveTimeline(timeline)
+
+	//	Assert
+	if err == nil {
+		t.Errorf("resolveTimeline - Should return error for a timeline that plays itself, but got none")
+	}
+}
+
+func TestNested_PlayFrames_Inline_WaitsForTimeline(t *testing.T) {
+
+	//	Arrange
+	out := &testOutput{}
+	frames, err := getTestNestedPatch().resolveFrames([]data2.TimelineFrame{
+		{Type: "timeline", Timeline: "flash"},
+		{Type: "scene", Channels: []data2.ChannelValue{{Channel: 2, Value: 255}}},
+	})
+	if err != nil {
+		t.Fatalf("resolveFrames - Should resolve without error, but got: %s", err)
+	}
+
+	//	Act
+	finished := playFrames(context.Background(), out, frames)
+
+	//	Assert
+	if !finished {
+		t.Fatalf("playFrames - Should finish playing, but it was stopped")
+	}
+
+	for _, render := range out.renders {
+		if render[1] == 255 && render[2] == 255 {
+			t.Fatalf("playFrames failed: Should finish the flash before setting channel 2")
+		}
+	}
+
+	last := out.renders[len(out.renders)-1]
+	if last[1] != 0 || last[2] != 255 {
+		t.Errorf("playFrames failed: Should end with channel 1 at 0 and channel 2 at 255 but got %v and %v", last[1], last[2])
+	}
+}
+
+func TestNested_PlayFrames_Parallel_PlaysAlongside(t *testing.T) {
+
+	//	Arrange
+	out := &testOutput{}
+	frames, err := getTestNestedPatch().resolveFrames([]data2.TimelineFrame{
+		{Type: "timeline", Timeline: "flash", Parallel: true},
+		{Type: "scene", Channels: []data2.ChannelValue{{Channel: 2, Value: 255}}},
+	})
+	if err != nil {
+		t.Fatalf("resolveFrames - Should resolve without error, but got: %s", err)
+	}
+
+	//	Act
+	started := time.Now()
+	finished := playFrames(context.Background(), out, frames)
+
+	//	Assert
+	if !finished {
+		t.Fatalf("playFrames - Should finish playing, but it was stopped")
+	}
+
+	if time.Since(started) < 40*time.Millisecond {
+		t.Errorf("playFrames failed: Should wait for the parallel timeline to finish")
+	}
+
+	overlapped := false
+	for _, render := range out.renders {
+		if render[1] == 255 && render[2] == 255 {
+			overlapped = true
+		}
+	}
+	if !overlapped {
+		t.Errorf("playFrames failed: Should set channel 2 while the flash is still playing")
+	}
+}
+
+func TestNested_PlayFrames_ParallelStopped_ReturnsFalse(t *testing.T) {
+
+	//	Arrange
+	out := &testOutput{}
+	patch := getTestNestedPatch()
+	patch.timelines["long"] = data2.Timeline{ID: "long", Name: "Long", Frames: []data2.TimelineFrame{{Type: "sleep", SleepTime: 5000}}}
+	frames, err := patch.resolveFrames([]data2.TimelineFrame{{Type: "timeline", Timeline: "long", Parallel: true}})
+	if err != nil {
+		t.Fatalf("resolveFrames - Should resolve without error, but got: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	//	Act
+	started := time.Now()
+	finished := playFrames(ctx, out, frames)
+
+	//	Assert
+	if finished || time.Since(started) > time.Second {
+		t.Errorf("playFrames - Should stop the parallel timeline with its parent, but it finished")
+	}
+}
+
+func TestNested_PlayTimeline_Tracks_StartTogether(t *testing.T) {
+
+	//	Arrange
+	out := &testOutput{}
+	timeline := data2.Timeline{
+		ID: "show",
+		Frames: []data2.TimelineFrame{
+			{Type: "fade", FadeTime: 60, Channels: []data2.ChannelValue{{Channel: 1, Value: 255}}},
+		},
+		Tracks: []data2.Track{
+			{Name: "Strobe", Frames: []data2.TimelineFrame{
+				{Type: "effect", Channels: []data2.ChannelValue{{Channel: 2}}, Effect: &data2.Effect{Shape: "square", Rate: 20, Duration: 100}},
+			}},
+		},
+	}
+
+	tracks, err := getTestNestedPatch().resolveTimeline(timeline)
+	if err != nil {
+		t.Fatalf("resolveTimeline - Should resolve without error, but got: %s", err)
+	}
+
+	//	Act
+	started := time.Now()
+	finished := playTimeline(context.Background(), SystemClock{}, out, tracks)
+
+	//	Assert
+	if !finished {
+		t.Fatalf("playTimeline - Should finish playing, but it was stopped")
+	}
+
+	if elapsed := time.Since(started); elapsed < 100*time.Millisecond || elapsed > 500*time.Millisecond {
+		t.Errorf("playTimeline failed: Should play the tracks together (about 100ms) but took %v", elapsed)
+	}
+
+	overlapped := false
+	for _, render := range out.renders {
+		if render[1] > 0 && render[1] < 255 && render[2] == 255 {
+			overlapped = true
+		}
+	}
+	if !overlapped {
+		t.Errorf("playTimeline failed: Should pulse channel 2 while channel 1 is fading")
+	}
+}
+
+func TestNested_ResolveTimeline_TrackError_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	timeline := data2.Timeline{
+		ID:     "show",
+		Frames: []data2.TimelineFrame{{Type: "sleep", SleepTime: 10}},
+		Tracks: []data2.Track{{Frames: []data2.TimelineFrame{{Type: "scene", Fixtures: []data2.FixtureValue{{Fixture: "Nobody"}}}}}},
+	}
+
+	//	Act
+	_, err := getTestNestedPatch().resolveTimeline(timeline)
+
+	//	Assert
+	if err == nil {
+		t.Errorf("resolveTimeline - Should return error for a track that doesn't resolve, but got none")
+	}
+}
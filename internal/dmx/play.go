@@ -0,0 +1,376 @@
+package dmx
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/danesparza/fxdmx/internal/color"
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+// renderInterval is how often fades are rendered.  DMX can't refresh much
+// faster than 40 times a second anyway.
+const renderInterval = 25 * time.Millisecond
+
+// dmxOutput is somewhere DMX frames are rendered (like a USB DMX widget)
+type dmxOutput interface {
+	SetChannel(channel int, value byte) error
+	Render() error
+}
+
+// channelState tracks the current value of each channel as a timeline plays
+type channelState map[int]byte
+
+// level gets the current value of a channel value's channels (16 bit if it has a fine channel)
+func (state channelState) level(value data2.ChannelValue) uint16 {
+	if value.Fine != 0 {
+		return uint16(state[value.Channel])<<8 | uint16(state[value.Fine])
+	}
+	return uint16(state[value.Channel])
+}
+
+// target is the level a channel value sets (16 bit if it has a fine channel)
+func target(value data2.ChannelValue) uint16 {
+	if value.Fine != 0 {
+		return value.Value16
+	}
+	return uint16(value.Value)
+}
+
+// set sets a channel value's channels to a level -- both bytes of a 16 bit
+// value are set together, so they're always rendered in the same frame
+func (state channelState) set(out dmxOutput, value data2.ChannelValue, level uint16) {
+	if value.Fine != 0 {
+		out.SetChannel(value.Channel, byte(level>>8))
+		out.SetChannel(value.Fine, byte(level))
+		state[value.Channel], state[value.Fine] = byte(level>>8), byte(level)
+		return
+	}
+
+	out.SetChannel(value.Channel, byte(level))
+	state[value.Channel] = byte(level)
+}
+
+// fader is something that fades over time (like a channel or a fixture's color)
+type fader interface {
+	// renderAt sets the fade's channels to where they are after the elapsed time
+	renderAt(state channelState, out dmxOutput, elapsed time.Duration)
+
+	// length is how long the fade takes
+	length() time.Duration
+}
+
+// channelFade is a single channel value fading from its current level to its target
+type channelFade struct {
+	value    data2.ChannelValue
+	from, to uint16
+	delay    time.Duration // How long the channel holds its current level before it starts fading
+	duration time.Duration
+}
+
+// levelAt is the level of the fade after it has been running for the elapsed time
+func (f channelFade) levelAt(elapsed time.Duration) uint16 {
+	elapsed -= f.delay
+	if elapsed < 0 {
+		return f.from
+	}
+
+	if elapsed >= f.duration || f.duration <= 0 {
+		return f.to
+	}
+
+	progress := float64(elapsed) / float64(f.duration)
+	return uint16(math.Round(float64(f.from) + (float64(f.to)-float64(f.from))*progress))
+}
+
+func (f channelFade) renderAt(state channelState, out dmxOutput, elapsed time.Duration) {
+	state.set(out, f.value, f.levelAt(elapsed))
+}
+
+func (f channelFade) length() time.Duration {
+	return f.delay + f.duration
+}
+
+// newChannelFade creates a fade from the current state of a channel value to
+// its target (after a delay).  If there isn't a fade time, the fade moves one
+// (coarse) step every millisecond.
+func newChannelFade(state channelState, value data2.ChannelValue, fadeTime int, delay time.Duration) channelFade {
+	retval := channelFade{value: value, from: state.level(value), to: target(value), delay: delay}
+
+	if fadeTime > 0 {
+		retval.duration = time.Duration(fadeTime) * time.Millisecond
+		return retval
+	}
+
+	steps := math.Abs(float64(retval.to) - float64(retval.from))
+	if value.Fine != 0 {
+		steps = steps / 256
+	}
+	retval.duration = time.Duration(steps * float64(time.Millisecond))
+
+	return retval
+}
+
+// fixtureColorFade is a fixture's color fading in a color space
+type fixtureColorFade struct {
+	colorFade
+	duration time.Duration
+}
+
+func (f fixtureColorFade) renderAt(state channelState, out dmxOutput, elapsed time.Duration) {
+	elapsed -= f.delay
+
+	progress := 1.0
+	switch {
+	case elapsed < 0:
+		progress = 0
+	case f.duration > 0 && elapsed < f.duration:
+		progress = float64(elapsed) / float64(f.duration)
+	}
+
+	//	The emitters were checked when the frame was resolved
+	values, _ := f.fixture.colorLevels(color.Blend(f.from, f.to, progress, f.space))
+	for _, value := range values {
+		state.set(out, value, target(value))
+	}
+}
+
+func (f fixtureColorFade) length() time.Duration {
+	return f.delay + f.duration
+}
+
+// newFixtureColorFade creates a fixture color fade.  If there isn't a fade
+// time, the fade moves one step (of the color channel that changes most)
+// every millisecond.
+func newFixtureColorFade(fade colorFade, fadeTime int) fixtureColorFade {
+	retval := fixtureColorFade{colorFade: fade}
+
+	if fadeTime > 0 {
+		retval.duration = time.Duration(fadeTime) * time.Millisecond
+		return retval
+	}
+
+	change := math.Max(math.Abs(fade.to.R-fade.from.R), math.Max(math.Abs(fade.to.G-fade.from.G), math.Abs(fade.to.B-fade.from.B)))
+	retval.duration = time.Duration(change * 255 * float64(time.Millisecond))
+
+	return retval
+}
+
+// renderUntil renders faders every render interval until a time (or until
+// it's stopped).  Faders render at the time elapsed since they started.  It
+// returns false if it was stopped.
+func (p *player) renderUntil(ctx context.Context, faders []fader, started, until time.Time) bool {
+	for {
+		//	Stop as soon as we're asked to (even if the next render is due too)
+		if ctx.Err() != nil {
+			return false
+		}
+
+		elapsed := p.clock.Now().Sub(started)
+		for _, fade := range faders {
+			fade.renderAt(p.state, p.out, elapsed)
+		}
+		p.out.Render()
+
+		remaining := until.Sub(p.clock.Now())
+		if remaining <= 0 {
+			return true
+		}
+
+		select {
+		case <-p.clock.After(min(remaining, renderInterval)):
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// later is the later of two times
+func later(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+// player plays resolved frames to an output, keeping track of the channel state
+type player struct {
+	out   dmxOutput
+	state channelState
+
+	//	Frames are scheduled against the clock: the cursor is when the next
+	//	frame is due, so time spent rendering (or waiting to be scheduled)
+	//	never pushes the frames after it back
+	clock  Clock
+	cursor time.Time
+
+	//	Effects without a duration keep running through sleep frames until the next frame
+	running      []fader
+	runningSince time.Time
+
+	//	Timelines started in parallel (which the player waits for when it finishes)
+	children playerGroup
+}
+
+// playFrames plays a list of (resolved) timeline frames to an output.  It returns
+// false if it was stopped before it finished.
+func playFrames(ctx context.Context, out dmxOutput, frames []playFrame) bool {
+	return playTimeline(ctx, SystemClock{}, out, [][]playFrame{frames})
+}
+
+// playTimeline plays a (resolved) timeline's tracks to an output on a clock.
+// It returns false if it was stopped before it finished.
+func playTimeline(ctx context.Context, c Clock, out dmxOutput, tracks [][]playFrame) bool {
+	return newPlayer(out, c).playAll(ctx, tracks)
+}
+
+// newPlayer creates a player whose first frame is due now
+func newPlayer(out dmxOutput, c Clock) *player {
+	return &player{out: out, state: channelState{}, clock: c, cursor: c.Now()}
+}
+
+// playAll plays a timeline's tracks, then waits for any timelines they
+// started in parallel.  It returns false if it was stopped before it finished.
+func (p *player) playAll(ctx context.Context, tracks [][]playFrame) bool {
+	finished := p.playTracks(ctx, tracks)
+	p.children.wait(p.clock)
+
+	return finished && ctx.Err() == nil
+}
+
+// playTracks plays a timeline's tracks together (on the same clock): the
+// first track on this player and the rest alongside it.  It returns when
+// every track is done (or false if it was stopped before they finished).
+func (p *player) playTracks(ctx context.Context, tracks [][]playFrame) bool {
+	tracksDone := &playerGroup{}
+	others := []*player{}
+	for _, track := range tracks[1:] {
+		others = append(others, p.spawn(ctx, [][]playFrame{track}, tracksDone))
+	}
+
+	finished := p.play(ctx, tracks[0])
+	tracksDone.wait(p.clock)
+
+	//	The next frame is due when the longest track is done
+	for _, other := range others {
+		p.cursor = later(p.cursor, other.cursor)
+	}
+
+	return finished && ctx.Err() == nil
+}
+
+// play plays a list of frames.  It returns false if it was stopped before it finished.
+func (p *player) play(ctx context.Context, frames []playFrame) bool {
+
+	//	Iterate through each frame
+	for _, frame := range frames {
+
+		select {
+		case <-ctx.Done():
+			// stop
+			return false
+		default:
+		}
+
+		if !p.playFrame(ctx, frame) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// playFrame plays a single frame.  It returns false if it was stopped before it finished.
+func (p *player) playFrame(ctx context.Context, frame playFrame) bool {
+	out, state := p.out, p.state
+
+	//	Find out what type of frame this is, and act accordingly:
+	switch strings.ToLower(frame.Type) {
+	case "scene":
+		p.running = nil
+
+		//	Iterate through each of the channels and set them, then render
+		for _, channel := range frame.Channels {
+			state.set(out, channel, target(channel))
+		}
+		out.Render()
+
+	case "fade":
+		p.running = nil
+
+		fades := make([]fader, 0, len(frame.Channels)+len(frame.colorFades))
+		for _, channel := range frame.Channels {
+			fades = append(fades, newChannelFade(state, channel, frame.FadeTime, frame.delays[channel.Channel]))
+		}
+		for _, fade := range frame.colorFades {
+			fades = append(fades, newFixtureColorFade(fade, frame.FadeTime))
+		}
+
+		//	Render every channel's current level in each frame until all the fades are done
+		longest := time.Duration(0)
+		for _, fade := range fades {
+			longest = max(longest, fade.length())
+		}
+
+		started := p.cursor
+		p.cursor = started.Add(longest)
+		return p.renderUntil(ctx, fades, started, p.cursor)
+
+	case "effect":
+		p.running = nil
+
+		//	Set any other fixture attributes in the frame, then run the effect
+		for _, channel := range frame.Channels {
+			state.set(out, channel, target(channel))
+		}
+
+		effects := make([]fader, len(frame.effects))
+		for i, effect := range frame.effects {
+			effects[i] = effect
+		}
+
+		started := p.cursor
+		if frame.Effect.Duration <= 0 {
+			p.running, p.runningSince = effects, started
+			return p.renderUntil(ctx, effects, started, started)
+		}
+
+		p.cursor = started.Add(time.Duration(frame.Effect.Duration) * time.Millisecond)
+		return p.renderUntil(ctx, effects, started, p.cursor)
+
+	case "chase":
+		p.running = nil
+		return p.playChase(ctx, frame)
+
+	case "keyframes":
+		p.running = nil
+		return p.playKeyframes(ctx, frame)
+
+	case "timeline":
+		//	Play the other timeline as if its frames were in this one, or alongside it
+		if frame.Parallel {
+			p.spawn(ctx, frame.tracks, &p.children)
+			return true
+		}
+		return p.playTracks(ctx, frame.tracks)
+
+	case "sleep":
+		p.cursor = p.cursor.Add(time.Duration(frame.SleepTime) * time.Millisecond)
+
+		//	Keep any running effects going while we sleep
+		if len(p.running) > 0 {
+			return p.renderUntil(ctx, p.running, p.runningSince, p.cursor)
+		}
+
+		//	Just sleep until the next frame is due
+		select {
+		case <-p.clock.After(p.cursor.Sub(p.clock.Now())):
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,250 @@
+package dmx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+// testOutput records every rendered frame
+type testOutput struct {
+	frame   [513]byte
+	renders [][513]byte
+}
+
+func (o *testOutput) SetChannel(channel int, value byte) error {
+	o.frame[channel] = value
+	return nil
+}
+
+func (o *testOutput) Render() error {
+	o.renders = append(o.renders, o.frame)
+	return nil
+}
+
+// getTestPlayFrames resolves frames (without fixtures) for playing
+func getTestPlayFrames(t *testing.T, frames []data2.TimelineFrame) []playFrame {
+	resolved, err := Patch{}.resolveFrames(frames)
+	if err != nil {
+		t.Fatalf("Problem resolving the test frames: %s", err)
+	}
+	return resolved
+}
+
+func TestPlay_PlayFrames_16BitFade_Successful(t *testing.T) {
+
+	//	Arrange
+	out := &testOutput{}
+	frames := []data2.TimelineFrame{
+		{Type: "scene", Channels: []data2.ChannelValue{{Channel: 1, Fine: 2, Value16: 0x00ff}}},
+		{Type: "fade", FadeTime: 100, Channels: []data2.ChannelValue{{Channel: 1, Fine: 2, Value16: 0x0300}}},
+	}
+
+	//	Act
+	finished := playFrames(context.Background(), out, getTestPlayFrames(t, frames))
+
+	//	Assert
+	if !finished {
+		t.Fatalf("playFrames - Should finish playing, but it was stopped")
+	}
+
+	if len(out.renders) < 3 {
+		t.Fatalf("playFrames failed: Should render the fade in steps but got %v renders", len(out.renders))
+	}
+
+	//	The 16 bit level should only ever move toward the target (so the coarse
+	//	and fine bytes were always rendered together)
+	previous := 0
+	for i, render := range out.renders {
+		level := int(render[1])<<8 | int(render[2])
+		if level < previous {
+			t.Errorf("playFrames failed: Render %v went backwards from %#04x to %#04x", i, previous, level)
+		}
+		previous = level
+	}
+
+	if previous != 0x0300 {
+		t.Errorf("playFrames failed: Should end at the target value but got: %#04x", previous)
+	}
+}
+
+func TestPlay_PlayFrames_8BitScene_Successful(t *testing.T) {
+
+	//	Arrange
+	out := &testOutput{}
+	frames := []data2.TimelineFrame{
+		{Type: "scene", Channels: []data2.ChannelValue{{Channel: 1, Value: 255}, {Channel: 3, Value: 10}}},
+		{Type: "fade", FadeTime: 10, Channels: []data2.ChannelValue{{Channel: 1, Value: 0}}},
+	}
+
+	//	Act
+	playFrames(context.Background(), out, getTestPlayFrames(t, frames))
+
+	//	Assert
+	last := out.renders[len(out.renders)-1]
+	if last[1] != 0 || last[3] != 10 {
+		t.Errorf("playFrames failed: Should fade channel 1 down and leave channel 3 but got: %v", last[:4])
+	}
+}
+
+func TestPlay_PlayFrames_Stopped_ReturnsFalse(t *testing.T) {
+
+	//	Arrange
+	out := &testOutput{}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	//	Act
+	finished := playFrames(ctx, out, getTestPlayFrames(t, []data2.TimelineFrame{{Type: "sleep", SleepTime: 1000}}))
+
+	//	Assert
+	if finished {
+		t.Errorf("playFrames - Should be stopped, but it finished")
+	}
+}
+
+func TestPlay_PlayFrames_HSVColorFade_Successful(t *testing.T) {
+
+	//	Arrange
+	out := &testOutput{}
+	frames, err := getTestPatch().resolveFrames([]data2.TimelineFrame{
+		{Type: "scene", Fixtures: []data2.FixtureValue{{Fixture: "Stage Left Par", Attributes: map[string]interface{}{"dimmer": 1.0, "color": "#ff0000"}}}},
+		{Type: "fade", FadeTime: 100, ColorSpace: "hsv", Fixtures: []data2.FixtureValue{{Fixture: "Stage Left Par", Attributes: map[string]interface{}{"color": "hsv(240, 100%, 100%)"}}}},
+	})
+	if err != nil {
+		t.Fatalf("resolveFrames - Should resolve without error, but got: %s", err)
+	}
+
+	//	Act
+	playFrames(context.Background(), out, frames)
+
+	//	Assert
+	if len(frames[1].colorFades) != 1 {
+		t.Fatalf("resolveFrames failed: Should fade the color in HSV but got: %+v", frames[1])
+	}
+
+	//	Going around the hue wheel from red to blue passes through magenta -- never green
+	sawMagenta := false
+	for _, render := range out.renders {
+		if render[19] != 0 {
+			t.Fatalf("playFrames failed: Should never turn on green fading red to blue in HSV but got: %v", render[17:21])
+		}
+		if render[18] > 0 && render[20] > 0 {
+			sawMagenta = true
+		}
+	}
+
+	last := out.renders[len(out.renders)-1]
+	if !sawMagenta || last[17] != 255 || last[18] != 0 || last[20] != 255 {
+		t.Errorf("playFrames failed: Should fade through magenta to blue but got: %v", last[17:21])
+	}
+}
+
+func TestPlay_PlayFrames_GroupOffset_Successful(t *testing.T) {
+
+	//	Arrange
+	out := &testOutput{}
+	frames, err := getTestPatch().resolveFrames([]data2.TimelineFrame{
+		{Type: "fade", FadeTime: 50, Fixtures: []data2.FixtureValue{{Group: "Pars", Attributes: map[string]interface{}{"dimmer": 1.0}, Offset: 100}}},
+	})
+	if err != nil {
+		t.Fatalf("resolveFrames - Should resolve without error, but got: %s", err)
+	}
+
+	//	Act
+	playFrames(context.Background(), out, frames)
+
+	//	Assert
+	first := out.renders[0]
+	if first[25] != 0 || first[21] != 0 {
+		t.Errorf("playFrames failed: Should hold later fixtures in the group until their offset but got: %v, %v", first[25], first[21])
+	}
+
+	//	The first fixture finishes fading before the last one starts
+	sawStaggered := false
+	for _, render := range out.renders {
+		if render[17] == 255 && render[21] == 0 {
+			sawStaggered = true
+		}
+	}
+
+	last := out.renders[len(out.renders)-1]
+	if !sawStaggered || last[17] != 255 || last[25] != 255 || last[21] != 255 {
+		t.Errorf("playFrames failed: Should fade each fixture in group order but got: %v, %v, %v", last[17], last[25], last[21])
+	}
+}
+
+// lateClock is a clock that only moves when something waits on it, and wakes
+// up a little late every time (like a busy system)
+type lateClock struct {
+	now  time.Time
+	late time.Duration
+}
+
+func (c *lateClock) Now() time.Time {
+	return c.now
+}
+
+func (c *lateClock) After(d time.Duration) <-chan time.Time {
+	c.now = c.now.Add(max(d, 0) + c.late)
+	retval := make(chan time.Time, 1)
+	retval <- c.now
+	return retval
+}
+
+// slowOutput records when each frame is rendered, and takes a while to render
+type slowOutput struct {
+	clock   *lateClock
+	renders []time.Time
+}
+
+func (o *slowOutput) SetChannel(channel int, value byte) error {
+	return nil
+}
+
+func (o *slowOutput) Render() error {
+	o.renders = append(o.renders, o.clock.now)
+	o.clock.now = o.clock.now.Add(3 * time.Millisecond)
+	return nil
+}
+
+func TestPlay_PlayFrames_ThousandsOfFrames_NoDrift(t *testing.T) {
+
+	//	Arrange
+	clock := &lateClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), late: 2 * time.Millisecond}
+	out := &slowOutput{clock: clock}
+	start := clock.now
+
+	frames := []data2.TimelineFrame{}
+	for i := 0; i < 5000; i++ {
+		frames = append(frames,
+			data2.TimelineFrame{Type: "scene", Channels: []data2.ChannelValue{{Channel: 1, Value: byte(i)}}},
+			data2.TimelineFrame{Type: "sleep", SleepTime: 40},
+		)
+	}
+
+	//	Act
+	finished := newPlayer(out, clock).playAll(context.Background(), [][]playFrame{getTestPlayFrames(t, frames)})
+
+	//	Assert
+	if !finished {
+		t.Fatalf("playAll - Should finish playing, but it was stopped")
+	}
+
+	if len(out.renders) != 5000 {
+		t.Fatalf("playAll failed: Should render 5000 scenes but got %v", len(out.renders))
+	}
+
+	for i, rendered := range out.renders {
+		want := time.Duration(i) * 40 * time.Millisecond
+		if i > 0 {
+			want += clock.late
+		}
+
+		if got := rendered.Sub(start); got != want {
+			t.Fatalf("playAll failed: Scene %v should render at %v but rendered at %v", i, want, got)
+		}
+	}
+}
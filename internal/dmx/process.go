@@ -39,6 +39,32 @@ type BackgroundProcess struct {
 
 	// PlayingTimelines tracks currently playing timelines
 	PlayingTimelines timelineProcessMap
+
+	// StartInput signals DMX input should be received from a source
+	StartInput chan InputRequest
+
+	// StopInput signals DMX input should stop being received
+	StopInput chan bool
+
+	// Input tracks the most recently received DMX input
+	Input *InputUniverse
+
+	// RunCue signals a cue list command (GO, BACK, GOTO or release)
+	RunCue chan CueCommand
+
+	// CueLists tracks where each running cue list is up to
+	CueLists *CueListPlayback
+
+	// Clock is the clock timelines and cue lists play on (optional) If not set, they play on the system clock
+	Clock Clock
+}
+
+// clock is the clock timelines and cue lists play on
+func (bp *BackgroundProcess) clock() Clock {
+	if bp.Clock == nil {
+		return SystemClock{}
+	}
+	return bp.Clock
 }
 
 // HandleAndProcess handles system context calls and channel events to play/stop audio
@@ -47,6 +73,13 @@ func (bp *BackgroundProcess) HandleAndProcess(systemctx context.Context) {
 	//	Create a map of running timelines and their cancel functions
 	bp.PlayingTimelines.m = make(map[string]func())
 
+	//	The cancel function for the current input receiver (if there is one)
+	stopInput := func() {}
+	defer func() { stopInput() }()
+
+	//	The running cue lists
+	cueLists := map[string]runningCueList{}
+
 	//	Loop and respond to channels:
 	for {
 		select {
@@ -92,6 +125,21 @@ func (bp *BackgroundProcess) HandleAndProcess(systemctx context.Context) {
 
 			bp.PlayingTimelines.rwMutex.Unlock()
 
+		case inputReq := <-bp.StartInput:
+			//	Only one input source is received at a time -- stop any current one
+			stopInput()
+
+			inputctx, cancelInput := context.WithCancel(systemctx)
+			stopInput = cancelInput
+			go bp.ReceiveInput(inputctx, inputReq)
+
+		case <-bp.StopInput:
+			stopInput()
+			stopInput = func() {}
+
+		case cueCommand := <-bp.RunCue:
+			bp.runCueCommand(systemctx, cueLists, cueCommand)
+
 		case <-systemctx.Done():
 			bp.DB.AddEvent(event.AllTimelinesStopped, "Stopping timeline processor", "", bp.HistoryTTL)
 			return
@@ -128,6 +176,20 @@ func (bp *BackgroundProcess) StartTimelinePlay(cx context.Context, req PlayTimel
 		req.RequestedTimeline.USBDevicePath = defaultDevice
 	}
 
+	//	Resolve any fixture attributes to channels using the current patch
+	//	(so re-patching a fixture doesn't mean editing every timeline)
+	patch, err := LoadPatch(bp.DB)
+	if err != nil {
+		bp.DB.AddEvent(event.TimelineError, fmt.Sprintf("An error occurred trying to load the fixture patch: %v", err), "", bp.HistoryTTL)
+		return
+	}
+
+	tracks, err := patch.resolveTimeline(req.RequestedTimeline)
+	if err != nil {
+		bp.DB.AddEvent(event.TimelineError, fmt.Sprintf("Unable to resolve fixtures in timeline %v: %v", req.RequestedTimeline.ID, err), "", bp.HistoryTTL)
+		return
+	}
+
 	// Connect to the DMX controller.
 	dmx, e := dmx.NewDMXConnection(req.RequestedTimeline.USBDevicePath)
 	if e != nil {
@@ -136,101 +198,9 @@ func (bp *BackgroundProcess) StartTimelinePlay(cx context.Context, req PlayTimel
 	}
 	defer dmx.Close()
 
-	//	Keep a channel state map:
-	channelState := map[int]byte{}
-
-	//	Our waitgroup (for sync'ing fade finishes)
-	var wg sync.WaitGroup
-
-	//	Iterate through each frame
-	for _, frame := range req.RequestedTimeline.Frames {
-
-		select {
-		default:
-
-			//	Find out what type of frame this is, and act accordingly:
-			switch strings.ToLower(frame.Type) {
-			case "scene":
-				//	Iterate through each of the channels and set them, then render
-				for _, channel := range frame.Channels {
-					//	Set dmx value for each channel:
-					dmx.SetChannel(channel.Channel, channel.Value)
-
-					//	Track chennel state:
-					channelState[channel.Channel] = channel.Value
-				}
-				dmx.Render()
-
-			case "fade":
-
-				//	Iterate through each of the channels.
-				for _, channel := range frame.Channels {
-
-					wg.Add(1)
-
-					//	Find the initial value, and pass that to the fade operation:
-					//	(if we can't find it, assume it's 0 and pass that)
-					ivalue, prs := channelState[channel.Channel]
-					if !prs {
-						ivalue = 0
-					}
-
-					go func(channelInfo data2.ChannelValue, initialValue byte) {
-						// Decrement the counter when the goroutine completes.
-						defer wg.Done()
-
-						//	Compare with the initial state, then render repeatedly
-						//	toward the target value in a for loop (pay attention to the direction).
-						if initialValue < channelInfo.Value {
-							//	We need to fade up
-							for i := initialValue; i < channelInfo.Value; i++ {
-								select {
-								case <-time.After(1 * time.Millisecond):
-									dmx.SetChannel(channelInfo.Channel, byte(i))
-									dmx.Render()
-								case <-ctx.Done():
-									return
-								}
-							}
-						} else {
-							//	We need to fade down
-							for i := initialValue; i > 0; i-- {
-								select {
-								case <-time.After(1 * time.Millisecond):
-									dmx.SetChannel(channelInfo.Channel, byte(i))
-									dmx.Render()
-								case <-ctx.Done():
-									return
-								}
-							}
-						}
-
-					}(channel, ivalue)
-
-					//	This means we'll need to put a mutex around them map. (for thread safe map interactions)
-
-					//	Also:  Track the new value of the channel
-					channelState[channel.Channel] = channel.Value
-
-				}
-
-				// Wait for all fades to complete.
-				wg.Wait()
-
-			case "sleep":
-				//	Just sleep for the specified number of milliseconds
-				select {
-				case <-time.After(time.Duration(frame.SleepTime) * time.Millisecond):
-					continue
-				case <-ctx.Done():
-					return
-				}
-			}
-
-		case <-ctx.Done():
-			// stop
-			return
-		}
+	//	Play the frames
+	if !playTimeline(ctx, bp.clock(), dmx, tracks) {
+		return
 	}
 
 	//	Remove ourselves from the map and exit (critical section)
@@ -0,0 +1,429 @@
+package dmx
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RDM (ANSI E1.20) packet constants
+const (
+	rdmStartCode    = 0xCC
+	rdmSubStartCode = 0x01
+	rdmHeaderSize   = 24
+
+	rdmCommandDiscovery = 0x10
+	rdmCommandGet       = 0x20
+	rdmCommandSet       = 0x30
+
+	rdmResponseAck        = 0x00
+	rdmResponseNackReason = 0x02
+
+	rdmNackUnknownPID     = 0x00
+	rdmNackFormatError    = 0x01
+	rdmNackDataOutOfRange = 0x06
+
+	rdmPIDDiscUniqueBranch          = 0x0001
+	rdmPIDDiscMute                  = 0x0002
+	rdmPIDDiscUnMute                = 0x0003
+	rdmPIDDeviceInfo                = 0x0060
+	rdmPIDDeviceModelDescription    = 0x0080
+	rdmPIDManufacturerLabel         = 0x0081
+	rdmPIDDeviceLabel               = 0x0082
+	rdmPIDDMXPersonalityDescription = 0x00E1
+	rdmPIDDMXStartAddress           = 0x00F0
+
+	rdmDiscoveryPreamble             = 0xFE
+	rdmDiscoverySeparator            = 0xAA
+	rdmMaxDiscoveryAttemptsPerBranch = 32
+)
+
+const (
+	// RDMBroadcastUID addresses every device on the line
+	RDMBroadcastUID UID = 0xFFFFFFFFFFFF
+
+	// RDMControllerUID is the UID fxdmx uses as the source of RDM requests
+	// (in the manufacturer ID range reserved for prototyping)
+	RDMControllerUID UID = 0x7FF000000001
+
+	rdmMaxUID UID = 0xFFFFFFFFFFFE
+)
+
+// UID is a 48 bit RDM unique ID (16 bit manufacturer ID + 32 bit device ID)
+type UID uint64
+
+// String formats the UID the usual way: MMMM:DDDDDDDD
+func (u UID) String() string {
+	return fmt.Sprintf("%04X:%08X", uint16(u>>32), uint32(u))
+}
+
+// bytes returns the UID in wire format
+func (u UID) bytes() []byte {
+	return []byte{byte(u >> 40), byte(u >> 32), byte(u >> 24), byte(u >> 16), byte(u >> 8), byte(u)}
+}
+
+// uidFromBytes reads a UID in wire format
+func uidFromBytes(b []byte) UID {
+	var retval UID
+	for _, v := range b[:6] {
+		retval = retval<<8 | UID(v)
+	}
+	return retval
+}
+
+// ParseUID parses a UID formatted as MMMM:DDDDDDDD
+func ParseUID(s string) (UID, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) != 2 || len(parts[0]) != 4 || len(parts[1]) != 8 {
+		return 0, fmt.Errorf("uid '%v' should look like MMMM:DDDDDDDD", s)
+	}
+
+	manufacturer, err := strconv.ParseUint(parts[0], 16, 16)
+	if err != nil {
+		return 0, fmt.Errorf("uid '%v' has an invalid manufacturer id: %v", s, err)
+	}
+
+	device, err := strconv.ParseUint(parts[1], 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("uid '%v' has an invalid device id: %v", s, err)
+	}
+
+	return UID(manufacturer<<32 | device), nil
+}
+
+// RDMDevice is the information read from an RDM responder
+type RDMDevice struct {
+	UID              string `json:"uid"`                       // The device UID (MMMM:DDDDDDDD)
+	Manufacturer     string `json:"manufacturer,omitempty"`    // Manufacturer label (if supported)
+	Model            string `json:"model,omitempty"`           // Device model description (if supported)
+	Label            string `json:"label,omitempty"`           // Device label (if supported)
+	ModelID          int    `json:"modelid"`                   // Device model ID
+	ProductCategory  int    `json:"productcategory"`           // Product category
+	SoftwareVersion  uint32 `json:"softwareversion"`           // Software version ID
+	Footprint        int    `json:"footprint"`                 // Number of DMX channels used in the current personality
+	Personality      int    `json:"personality"`               // The current DMX personality (starting at 1)
+	PersonalityCount int    `json:"personalitycount"`          // The number of DMX personalities the device supports
+	PersonalityName  string `json:"personalityname,omitempty"` // The current DMX personality description (if supported)
+	StartAddress     int    `json:"startaddress"`              // DMX start address (0xFFFF if the device doesn't use DMX slots)
+	SubDevices       int    `json:"subdevices"`                // Number of sub-devices
+	Sensors          int    `json:"sensors"`                   // Number of sensors
+}
+
+// RDMTransport sends RDM requests to the line and returns the raw responses
+type RDMTransport interface {
+	// SendRDM sends an RDM packet and returns the response packet (or nil if there was no response)
+	SendRDM(packet []byte) ([]byte, error)
+
+	// SendRDMDiscovery sends a discovery unique branch packet and returns the
+	// raw (encoded) response (or nil if there was no response)
+	SendRDMDiscovery(packet []byte) ([]byte, error)
+
+	// Close closes the transport
+	Close() error
+}
+
+// rdmPacket is a decoded RDM packet
+type rdmPacket struct {
+	Destination  UID
+	Source       UID
+	Transaction  byte
+	PortID       byte // The port ID for requests, the response type for responses
+	MessageCount byte
+	SubDevice    uint16
+	CommandClass byte
+	PID          uint16
+	Data         []byte
+}
+
+// encode encodes the packet in wire format (including the start code and checksum)
+func (p rdmPacket) encode() []byte {
+	length := rdmHeaderSize + len(p.Data)
+
+	retval := make([]byte, 0, length+2)
+	retval = append(retval, rdmStartCode, rdmSubStartCode, byte(length))
+	retval = append(retval, p.Destination.bytes()...)
+	retval = append(retval, p.Source.bytes()...)
+	retval = append(retval, p.Transaction, p.PortID, p.MessageCount, byte(p.SubDevice>>8), byte(p.SubDevice))
+	retval = append(retval, p.CommandClass, byte(p.PID>>8), byte(p.PID), byte(len(p.Data)))
+	retval = append(retval, p.Data...)
+
+	sum := rdmChecksum(retval)
+	return append(retval, byte(sum>>8), byte(sum))
+}
+
+// parseRDMPacket decodes an RDM packet in wire format
+func parseRDMPacket(b []byte) (rdmPacket, error) {
+	if len(b) < rdmHeaderSize+2 || b[0] != rdmStartCode || b[1] != rdmSubStartCode {
+		return rdmPacket{}, fmt.Errorf("not an RDM packet")
+	}
+
+	length := int(b[2])
+	if length < rdmHeaderSize || len(b) < length+2 || length != rdmHeaderSize+int(b[23]) {
+		return rdmPacket{}, fmt.Errorf("RDM packet has an invalid length")
+	}
+
+	if sum := rdmChecksum(b[:length]); byte(sum>>8) != b[length] || byte(sum) != b[length+1] {
+		return rdmPacket{}, fmt.Errorf("RDM packet has an invalid checksum")
+	}
+
+	return rdmPacket{
+		Destination:  uidFromBytes(b[3:9]),
+		Source:       uidFromBytes(b[9:15]),
+		Transaction:  b[15],
+		PortID:       b[16],
+		MessageCount: b[17],
+		SubDevice:    uint16(b[18])<<8 | uint16(b[19]),
+		CommandClass: b[20],
+		PID:          uint16(b[21])<<8 | uint16(b[22]),
+		Data:         append([]byte{}, b[rdmHeaderSize:length]...),
+	}, nil
+}
+
+// rdmChecksum is the 16 bit additive checksum used by RDM
+func rdmChecksum(b []byte) uint16 {
+	var sum uint16
+	for _, v := range b {
+		sum += uint16(v)
+	}
+	return sum
+}
+
+// encodeDiscoveryResponse encodes a UID as a discovery unique branch response
+func encodeDiscoveryResponse(uid UID) []byte {
+	retval := bytes.Repeat([]byte{rdmDiscoveryPreamble}, 7)
+	retval = append(retval, rdmDiscoverySeparator)
+
+	var sum uint16
+	for _, v := range uid.bytes() {
+		retval = append(retval, v|0xAA, v|0x55)
+		sum += uint16(v|0xAA) + uint16(v|0x55)
+	}
+
+	return append(retval, byte(sum>>8)|0xAA, byte(sum>>8)|0x55, byte(sum)|0xAA, byte(sum)|0x55)
+}
+
+// decodeDiscoveryResponse decodes a discovery unique branch response.  If more
+// than one device responded (a collision), the checksum won't match.
+func decodeDiscoveryResponse(b []byte) (UID, bool) {
+	//	Skip the preamble (up to 7 bytes) and find the separator
+	i := 0
+	for i < len(b) && i < 7 && b[i] == rdmDiscoveryPreamble {
+		i++
+	}
+	if i >= len(b) || b[i] != rdmDiscoverySeparator {
+		return 0, false
+	}
+
+	encoded := b[i+1:]
+	if len(encoded) < 16 {
+		return 0, false
+	}
+
+	var sum uint16
+	decoded := make([]byte, 6)
+	for j := 0; j < 6; j++ {
+		decoded[j] = encoded[j*2] & encoded[j*2+1]
+		sum += uint16(encoded[j*2]) + uint16(encoded[j*2+1])
+	}
+
+	checksum := uint16(encoded[12]&encoded[13])<<8 | uint16(encoded[14]&encoded[15])
+	if checksum != sum {
+		return 0, false
+	}
+
+	return uidFromBytes(decoded), true
+}
+
+// RDMController performs RDM operations over a transport
+type RDMController struct {
+	transport   RDMTransport
+	transaction byte
+}
+
+// NewRDMController creates a controller that uses the given transport
+func NewRDMController(transport RDMTransport) *RDMController {
+	return &RDMController{transport: transport}
+}
+
+// Close closes the controller's transport
+func (c *RDMController) Close() error {
+	return c.transport.Close()
+}
+
+// Discover finds every RDM responder on the line
+func (c *RDMController) Discover() ([]UID, error) {
+	retval := []UID{}
+
+	//	Un-mute everybody, so everybody takes part in discovery
+	if _, err := c.transport.SendRDM(c.request(RDMBroadcastUID, rdmCommandDiscovery, rdmPIDDiscUnMute, nil).encode()); err != nil {
+		return retval, fmt.Errorf("problem un-muting devices: %v", err)
+	}
+
+	err := c.discoverBranch(0, rdmMaxUID, &retval)
+	return retval, err
+}
+
+// discoverBranch runs the binary search part of discovery for the given UID range
+func (c *RDMController) discoverBranch(lower, upper UID, found *[]UID) error {
+	for attempt := 0; attempt < rdmMaxDiscoveryAttemptsPerBranch; attempt++ {
+		branch := append(lower.bytes(), upper.bytes()...)
+		response, err := c.transport.SendRDMDiscovery(c.request(RDMBroadcastUID, rdmCommandDiscovery, rdmPIDDiscUniqueBranch, branch).encode())
+		if err != nil {
+			return fmt.Errorf("problem sending discovery request: %v", err)
+		}
+
+		//	Nobody in this branch
+		if len(response) == 0 {
+			return nil
+		}
+
+		//	More than one device answered -- split the branch and search each half
+		uid, ok := decodeDiscoveryResponse(response)
+		if !ok {
+			if lower == upper {
+				return nil
+			}
+
+			mid := lower + (upper-lower)/2
+			if err := c.discoverBranch(lower, mid, found); err != nil {
+				return err
+			}
+			return c.discoverBranch(mid+1, upper, found)
+		}
+
+		//	Exactly one device answered.  Mute it so it doesn't answer again, then
+		//	ask this branch again in case anybody else is in it
+		if _, err := c.command(uid, rdmCommandDiscovery, rdmPIDDiscMute, nil); err == nil {
+			*found = append(*found, uid)
+		}
+	}
+
+	return nil
+}
+
+// DiscoverDevices finds every RDM responder on the line and reads its device information
+func (c *RDMController) DiscoverDevices() ([]RDMDevice, error) {
+	retval := []RDMDevice{}
+
+	uids, err := c.Discover()
+	if err != nil {
+		return retval, err
+	}
+
+	for _, uid := range uids {
+		device, err := c.DeviceInfo(uid)
+		if err != nil {
+			return retval, err
+		}
+		retval = append(retval, device)
+	}
+
+	return retval, nil
+}
+
+// DeviceInfo reads the device information from a responder
+func (c *RDMController) DeviceInfo(uid UID) (RDMDevice, error) {
+	retval := RDMDevice{UID: uid.String()}
+
+	info, err := c.command(uid, rdmCommandGet, rdmPIDDeviceInfo, nil)
+	if err != nil {
+		return retval, err
+	}
+	if len(info) < 19 {
+		return retval, fmt.Errorf("device info response from %v is too short", uid)
+	}
+
+	retval.ModelID = int(info[2])<<8 | int(info[3])
+	retval.ProductCategory = int(info[4])<<8 | int(info[5])
+	retval.SoftwareVersion = be32(info[6:10])
+	retval.Footprint = int(info[10])<<8 | int(info[11])
+	retval.Personality = int(info[12])
+	retval.PersonalityCount = int(info[13])
+	retval.StartAddress = int(info[14])<<8 | int(info[15])
+	retval.SubDevices = int(info[16])<<8 | int(info[17])
+	retval.Sensors = int(info[18])
+
+	//	These are optional -- not every device supports them
+	retval.Manufacturer = c.label(uid, rdmPIDManufacturerLabel, nil)
+	retval.Model = c.label(uid, rdmPIDDeviceModelDescription, nil)
+	retval.Label = c.label(uid, rdmPIDDeviceLabel, nil)
+	if retval.Personality > 0 {
+		//	The description response starts with the personality and footprint
+		if description := c.label(uid, rdmPIDDMXPersonalityDescription, []byte{byte(retval.Personality)}); len(description) > 3 {
+			retval.PersonalityName = description[3:]
+		}
+	}
+
+	return retval, nil
+}
+
+// SetStartAddress sets the DMX start address of a responder
+func (c *RDMController) SetStartAddress(uid UID, address int) error {
+	if address < 1 || address > 512 {
+		return fmt.Errorf("start address %v is out of range 1-512", address)
+	}
+
+	_, err := c.command(uid, rdmCommandSet, rdmPIDDMXStartAddress, []byte{byte(address >> 8), byte(address)})
+	return err
+}
+
+// label gets a text parameter, or returns an empty string if it can't be read
+func (c *RDMController) label(uid UID, pid uint16, data []byte) string {
+	response, err := c.command(uid, rdmCommandGet, pid, data)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(string(response), "\x00")
+}
+
+// request builds a request packet with the next transaction number
+func (c *RDMController) request(uid UID, commandClass byte, pid uint16, data []byte) rdmPacket {
+	c.transaction++
+
+	return rdmPacket{
+		Destination:  uid,
+		Source:       RDMControllerUID,
+		Transaction:  c.transaction,
+		PortID:       1,
+		CommandClass: commandClass,
+		PID:          pid,
+		Data:         data,
+	}
+}
+
+// command sends a request to a single device and returns the response data
+func (c *RDMController) command(uid UID, commandClass byte, pid uint16, data []byte) ([]byte, error) {
+	request := c.request(uid, commandClass, pid, data)
+
+	raw, err := c.transport.SendRDM(request.encode())
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("no response from %v", uid)
+	}
+
+	response, err := parseRDMPacket(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Source != uid || response.Transaction != request.Transaction ||
+		response.CommandClass != commandClass+1 || response.PID != pid {
+		return nil, fmt.Errorf("unexpected response from %v", response.Source)
+	}
+
+	switch response.PortID {
+	case rdmResponseAck:
+		return response.Data, nil
+	case rdmResponseNackReason:
+		reason := 0
+		if len(response.Data) >= 2 {
+			reason = int(response.Data[0])<<8 | int(response.Data[1])
+		}
+		return nil, fmt.Errorf("%v refused the request (nack reason %#04x)", uid, reason)
+	default:
+		return nil, fmt.Errorf("%v sent an unsupported response type %#02x", uid, response.PortID)
+	}
+}
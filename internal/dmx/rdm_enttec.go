@@ -0,0 +1,84 @@
+package dmx
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	serial "github.com/tarm/goserial"
+)
+
+const (
+	enttecLabelSendRDM          = 7  // Host -> widget: send an RDM packet
+	enttecLabelSendRDMDiscovery = 11 // Host -> widget: send an RDM discovery request
+	enttecLabelRDMTimeout       = 12 // Widget -> host: no response to an RDM request
+
+	enttecRDMReadTimeout = 100 * time.Millisecond
+)
+
+// enttecRDMTransport sends RDM through an Enttec DMX USB Pro compatible widget
+type enttecRDMTransport struct {
+	port   io.ReadWriteCloser
+	reader *bufio.Reader
+}
+
+// OpenEnttecRDM opens an RDM transport on an Enttec DMX USB Pro compatible widget
+func OpenEnttecRDM(devicePath string) (RDMTransport, error) {
+	port, err := serial.OpenPort(&serial.Config{Name: devicePath, Baud: enttecBaud, ReadTimeout: enttecRDMReadTimeout})
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to DMX512 interface %v: %v", devicePath, err)
+	}
+
+	return &enttecRDMTransport{port: port, reader: bufio.NewReader(port)}, nil
+}
+
+// SendRDM sends an RDM packet and returns the response packet
+func (t *enttecRDMTransport) SendRDM(packet []byte) ([]byte, error) {
+	return t.send(enttecLabelSendRDM, packet)
+}
+
+// SendRDMDiscovery sends a discovery unique branch packet and returns the raw response
+func (t *enttecRDMTransport) SendRDMDiscovery(packet []byte) ([]byte, error) {
+	return t.send(enttecLabelSendRDMDiscovery, packet)
+}
+
+// Close closes the serial port
+func (t *enttecRDMTransport) Close() error {
+	return t.port.Close()
+}
+
+// send writes a request to the widget and waits for the response.  The
+// response comes back as a 'received DMX packet' with a leading status byte
+func (t *enttecRDMTransport) send(label byte, packet []byte) ([]byte, error) {
+	if err := writeEnttecMessage(t.port, label, packet); err != nil {
+		return nil, err
+	}
+
+	for {
+		responseLabel, payload, err := readEnttecMessage(t.reader)
+		if err != nil {
+			//	A read timeout means nobody answered
+			if errors.Is(err, io.EOF) {
+				t.reader.Reset(t.port)
+				return nil, nil
+			}
+			if errors.Is(err, errEnttecFraming) {
+				continue
+			}
+			return nil, err
+		}
+
+		switch responseLabel {
+		case enttecLabelRDMTimeout:
+			return nil, nil
+		case enttecLabelReceivedDMX:
+			//	Skip the receive status byte
+			if len(payload) < 2 {
+				return nil, nil
+			}
+			return payload[1:], nil
+		}
+	}
+}
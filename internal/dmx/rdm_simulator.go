@@ -0,0 +1,180 @@
+package dmx
+
+import (
+	"fmt"
+	"sync"
+)
+
+// SimulatedPersonality is a DMX personality of a simulated RDM responder
+type SimulatedPersonality struct {
+	Name      string // Personality description
+	Footprint int    // Number of DMX channels used
+}
+
+// SimulatedRDMDevice is a simulated RDM responder
+type SimulatedRDMDevice struct {
+	UID           UID
+	Manufacturer  string
+	Model         string
+	Label         string
+	ModelID       int
+	Personalities []SimulatedPersonality
+	Personality   int // The current personality (starting at 1)
+	StartAddress  int
+
+	muted bool
+}
+
+// RDMSimulator is a simulated line of RDM responders.  It implements
+// RDMTransport, so it can be used in place of a real widget (for tests)
+type RDMSimulator struct {
+	Devices []*SimulatedRDMDevice
+
+	mutex sync.Mutex
+}
+
+// SendRDM handles an RDM request and returns the response (if any)
+func (s *RDMSimulator) SendRDM(packet []byte) ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	request, err := parseRDMPacket(packet)
+	if err != nil {
+		return nil, err
+	}
+
+	var response []byte
+	for _, device := range s.Devices {
+		if request.Destination != RDMBroadcastUID && request.Destination != device.UID {
+			continue
+		}
+
+		reply, data := device.handle(request)
+
+		//	Devices don't respond to broadcast requests
+		if request.Destination == RDMBroadcastUID {
+			continue
+		}
+
+		response = rdmPacket{
+			Destination:  request.Source,
+			Source:       device.UID,
+			Transaction:  request.Transaction,
+			PortID:       reply,
+			CommandClass: request.CommandClass + 1,
+			PID:          request.PID,
+			Data:         data,
+		}.encode()
+	}
+
+	return response, nil
+}
+
+// SendRDMDiscovery handles a discovery unique branch request.  If more than
+// one un-muted device is in the branch their responses collide
+func (s *RDMSimulator) SendRDMDiscovery(packet []byte) ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	request, err := parseRDMPacket(packet)
+	if err != nil {
+		return nil, err
+	}
+	if request.PID != rdmPIDDiscUniqueBranch || len(request.Data) != 12 {
+		return nil, fmt.Errorf("not a discovery unique branch request")
+	}
+
+	lower := uidFromBytes(request.Data[:6])
+	upper := uidFromBytes(request.Data[6:])
+
+	var response []byte
+	for _, device := range s.Devices {
+		if device.muted || device.UID < lower || device.UID > upper {
+			continue
+		}
+
+		//	Overlapping responses are OR'ed together on the line
+		encoded := encodeDiscoveryResponse(device.UID)
+		if response == nil {
+			response = encoded
+			continue
+		}
+		for i := range response {
+			response[i] |= encoded[i]
+		}
+	}
+
+	return response, nil
+}
+
+// Close does nothing for the simulator
+func (s *RDMSimulator) Close() error {
+	return nil
+}
+
+// handle handles a request addressed to this device and returns the response type and data
+func (d *SimulatedRDMDevice) handle(request rdmPacket) (byte, []byte) {
+	switch {
+	case request.CommandClass == rdmCommandDiscovery && request.PID == rdmPIDDiscMute:
+		d.muted = true
+		return rdmResponseAck, []byte{0, 0}
+
+	case request.CommandClass == rdmCommandDiscovery && request.PID == rdmPIDDiscUnMute:
+		d.muted = false
+		return rdmResponseAck, []byte{0, 0}
+
+	case request.CommandClass == rdmCommandGet && request.PID == rdmPIDDeviceInfo:
+		footprint := d.footprint()
+		return rdmResponseAck, []byte{
+			0x01, 0x00, // RDM protocol version 1.0
+			byte(d.ModelID >> 8), byte(d.ModelID),
+			0x01, 0x01, // Product category: fixture
+			0, 0, 0, 1, // Software version
+			byte(footprint >> 8), byte(footprint),
+			byte(d.Personality), byte(len(d.Personalities)),
+			byte(d.StartAddress >> 8), byte(d.StartAddress),
+			0, 0, // Sub-devices
+			0, // Sensors
+		}
+
+	case request.CommandClass == rdmCommandGet && request.PID == rdmPIDManufacturerLabel:
+		return rdmResponseAck, []byte(d.Manufacturer)
+
+	case request.CommandClass == rdmCommandGet && request.PID == rdmPIDDeviceModelDescription:
+		return rdmResponseAck, []byte(d.Model)
+
+	case request.CommandClass == rdmCommandGet && request.PID == rdmPIDDeviceLabel:
+		return rdmResponseAck, []byte(d.Label)
+
+	case request.CommandClass == rdmCommandGet && request.PID == rdmPIDDMXPersonalityDescription:
+		if len(request.Data) != 1 || int(request.Data[0]) < 1 || int(request.Data[0]) > len(d.Personalities) {
+			return rdmResponseNackReason, []byte{0, rdmNackDataOutOfRange}
+		}
+		personality := d.Personalities[request.Data[0]-1]
+		return rdmResponseAck, append([]byte{request.Data[0], byte(personality.Footprint >> 8), byte(personality.Footprint)}, personality.Name...)
+
+	case request.CommandClass == rdmCommandGet && request.PID == rdmPIDDMXStartAddress:
+		return rdmResponseAck, []byte{byte(d.StartAddress >> 8), byte(d.StartAddress)}
+
+	case request.CommandClass == rdmCommandSet && request.PID == rdmPIDDMXStartAddress:
+		if len(request.Data) != 2 {
+			return rdmResponseNackReason, []byte{0, rdmNackFormatError}
+		}
+		address := int(request.Data[0])<<8 | int(request.Data[1])
+		if address < 1 || address > 512 {
+			return rdmResponseNackReason, []byte{0, rdmNackDataOutOfRange}
+		}
+		d.StartAddress = address
+		return rdmResponseAck, nil
+	}
+
+	return rdmResponseNackReason, []byte{0, rdmNackUnknownPID}
+}
+
+// footprint returns the footprint of the current personality
+func (d *SimulatedRDMDevice) footprint() int {
+	if d.Personality < 1 || d.Personality > len(d.Personalities) {
+		return 0
+	}
+	return d.Personalities[d.Personality-1].Footprint
+}
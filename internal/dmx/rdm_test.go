@@ -0,0 +1,156 @@
+package dmx
+
+import (
+	"testing"
+)
+
+func getTestRDMLine() *RDMSimulator {
+	return &RDMSimulator{
+		Devices: []*SimulatedRDMDevice{
+			{
+				UID:          0x4750_00000001,
+				Manufacturer: "Unit test lighting",
+				Model:        "Par 64",
+				ModelID:      0x0101,
+				Personalities: []SimulatedPersonality{
+					{Name: "3 channel RGB", Footprint: 3},
+					{Name: "7 channel", Footprint: 7},
+				},
+				Personality:  2,
+				StartAddress: 1,
+			},
+			{
+				UID:           0x4750_00000002,
+				Manufacturer:  "Unit test lighting",
+				Model:         "Par 64",
+				ModelID:       0x0101,
+				Personalities: []SimulatedPersonality{{Name: "3 channel RGB", Footprint: 3}},
+				Personality:   1,
+				StartAddress:  8,
+			},
+			{
+				UID:           0x0A1B_12345678,
+				Manufacturer:  "Unit test fog",
+				Model:         "Fogger",
+				ModelID:       0x0002,
+				Personalities: []SimulatedPersonality{{Name: "Fog", Footprint: 1}},
+				Personality:   1,
+				StartAddress:  100,
+			},
+		},
+	}
+}
+
+func TestRDM_ParseUID_RoundTrip_Successful(t *testing.T) {
+
+	//	Arrange
+	uid := UID(0x0A1B_12345678)
+
+	//	Act
+	parsed, err := ParseUID(uid.String())
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("ParseUID - Should parse without error, but got: %s", err)
+	}
+
+	if uid.String() != "0A1B:12345678" || parsed != uid {
+		t.Errorf("ParseUID failed: Should round trip %v but got: %v", uid, parsed)
+	}
+}
+
+func TestRDM_DiscoveryResponse_RoundTrip_Successful(t *testing.T) {
+
+	//	Arrange
+	uid := UID(0x7FF0_00C0FFEE)
+
+	//	Act
+	decoded, ok := decodeDiscoveryResponse(encodeDiscoveryResponse(uid))
+
+	//	Assert
+	if !ok || decoded != uid {
+		t.Errorf("decodeDiscoveryResponse failed: Should decode %v but got: %v", uid, decoded)
+	}
+}
+
+func TestRDM_Discover_MultipleDevices_Successful(t *testing.T) {
+
+	//	Arrange
+	controller := NewRDMController(getTestRDMLine())
+
+	//	Act
+	uids, err := controller.Discover()
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("Discover - Should discover without error, but got: %s", err)
+	}
+
+	if len(uids) != 3 {
+		t.Fatalf("Discover failed: Should find 3 devices but got: %v", uids)
+	}
+
+	found := map[UID]bool{}
+	for _, uid := range uids {
+		found[uid] = true
+	}
+	if !found[0x4750_00000001] || !found[0x4750_00000002] || !found[0x0A1B_12345678] {
+		t.Errorf("Discover failed: Should find every device but got: %v", uids)
+	}
+}
+
+func TestRDM_DeviceInfo_ValidDevice_Successful(t *testing.T) {
+
+	//	Arrange
+	controller := NewRDMController(getTestRDMLine())
+
+	//	Act
+	device, err := controller.DeviceInfo(0x4750_00000001)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("DeviceInfo - Should get device info without error, but got: %s", err)
+	}
+
+	if device.Footprint != 7 || device.Personality != 2 || device.PersonalityCount != 2 || device.StartAddress != 1 {
+		t.Errorf("DeviceInfo failed: Should get the footprint, personality and address but got: %+v", device)
+	}
+
+	if device.Manufacturer != "Unit test lighting" || device.Model != "Par 64" || device.PersonalityName != "7 channel" {
+		t.Errorf("DeviceInfo failed: Should get the device labels but got: %+v", device)
+	}
+}
+
+func TestRDM_SetStartAddress_ValidAddress_Successful(t *testing.T) {
+
+	//	Arrange
+	line := getTestRDMLine()
+	controller := NewRDMController(line)
+
+	//	Act
+	err := controller.SetStartAddress(0x0A1B_12345678, 42)
+	device, _ := controller.DeviceInfo(0x0A1B_12345678)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("SetStartAddress - Should set the address without error, but got: %s", err)
+	}
+
+	if device.StartAddress != 42 || line.Devices[2].StartAddress != 42 {
+		t.Errorf("SetStartAddress failed: Should update the start address but got: %+v", device)
+	}
+}
+
+func TestRDM_DeviceInfo_UnknownDevice_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	controller := NewRDMController(getTestRDMLine())
+
+	//	Act
+	_, err := controller.DeviceInfo(0x1234_00000001)
+
+	//	Assert
+	if err == nil {
+		t.Errorf("DeviceInfo - Should return error for a device that isn't on the line, but got none")
+	}
+}
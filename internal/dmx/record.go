@@ -0,0 +1,234 @@
+package dmx
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+const (
+	// minRampSteps is the minimum number of consecutive changes that can be collapsed into a fade
+	minRampSteps = 3
+
+	// maxRampGap is the longest pause between changes that still counts as a smooth ramp
+	maxRampGap = 250 * time.Millisecond
+)
+
+// RecordRequest is a request to record DMX input into a new timeline
+type RecordRequest struct {
+	Name          string `json:"name"`          // The name of the timeline to create
+	CollapseFades bool   `json:"collapsefades"` // Collapse smooth ramps into fade frames
+}
+
+// RecordStatus describes the current recording (if there is one)
+type RecordStatus struct {
+	Recording bool          `json:"recording"` // True if input is currently being recorded
+	Request   RecordRequest `json:"request"`   // The current recording request
+	Started   time.Time     `json:"started"`   // When the current recording was started
+	Changes   int           `json:"changes"`   // The number of input changes recorded so far
+}
+
+// InputRecorder records changes on an input universe so they can be saved as a timeline
+type InputRecorder struct {
+	input   *InputUniverse
+	current *recording
+	mutex   sync.Mutex
+}
+
+// recording is a single recording 'take'
+type recording struct {
+	request  RecordRequest
+	started  time.Time
+	initial  [512]byte
+	changes  []InputChange
+	unlisten func()
+	mutex    sync.Mutex
+}
+
+// NewInputRecorder creates a recorder for the given input universe
+func NewInputRecorder(input *InputUniverse) *InputRecorder {
+	return &InputRecorder{input: input}
+}
+
+// Start starts recording input changes.  Only one recording can be in progress at a time
+func (r *InputRecorder) Start(req RecordRequest) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.current != nil {
+		return fmt.Errorf("a recording is already in progress")
+	}
+
+	take := &recording{
+		request: req,
+		started: time.Now(),
+	}
+
+	//	Collect every change until the recording is stopped
+	take.initial, take.unlisten = r.input.Listen(func(change InputChange) {
+		take.mutex.Lock()
+		take.changes = append(take.changes, change)
+		take.mutex.Unlock()
+	})
+
+	r.current = take
+	return nil
+}
+
+// Stop stops the current recording and returns the request it was started
+// with, along with the recorded timeline frames
+func (r *InputRecorder) Stop() (RecordRequest, []data2.TimelineFrame, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	take := r.current
+	if take == nil {
+		return RecordRequest{}, nil, fmt.Errorf("there is no recording in progress")
+	}
+	r.current = nil
+
+	//	Stop listening (once this returns, every change is in the take)
+	take.unlisten()
+
+	take.mutex.Lock()
+	defer take.mutex.Unlock()
+
+	frames := RecordedFrames(take.initial, take.started, take.changes, take.request.CollapseFades)
+	if len(frames) < 1 {
+		return take.request, nil, fmt.Errorf("no DMX input was recorded")
+	}
+
+	return take.request, frames, nil
+}
+
+// Status returns the status of the current recording
+func (r *InputRecorder) Status() RecordStatus {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.current == nil {
+		return RecordStatus{}
+	}
+
+	r.current.mutex.Lock()
+	defer r.current.mutex.Unlock()
+
+	return RecordStatus{
+		Recording: true,
+		Request:   r.current.request,
+		Started:   r.current.started,
+		Changes:   len(r.current.changes),
+	}
+}
+
+// RecordedFrames converts the initial universe state and a list of changes
+// into scene and sleep frames.  If collapseFades is set, smooth ramps are
+// collapsed into fade frames.
+func RecordedFrames(initial [512]byte, started time.Time, changes []InputChange, collapseFades bool) []data2.TimelineFrame {
+	retval := []data2.TimelineFrame{}
+
+	//	Start with a scene that sets every channel we care about: anything
+	//	that was already on, and anything that changes during the recording
+	used := map[int]bool{}
+	for _, change := range changes {
+		for _, channel := range change.Channels {
+			used[channel.Channel] = true
+		}
+	}
+
+	opening := data2.TimelineFrame{Type: "scene"}
+	for i, value := range initial {
+		if value != 0 || used[i+1] {
+			opening.Channels = append(opening.Channels, data2.ChannelValue{Channel: i + 1, Value: value})
+		}
+	}
+	if len(opening.Channels) > 0 {
+		retval = append(retval, opening)
+	}
+
+	//	Then add each change, with a sleep for the time in between
+	current := initial
+	last := started
+	for i := 0; i < len(changes); {
+		end := i
+		if collapseFades {
+			end = rampEnd(current, changes, i)
+		}
+
+		if sleep := changes[i].Received.Sub(last).Milliseconds(); sleep > 0 {
+			retval = append(retval, data2.TimelineFrame{Type: "sleep", SleepTime: int(sleep)})
+		}
+
+		frame := data2.TimelineFrame{Type: "scene"}
+		if fadeTime := changes[end].Received.Sub(changes[i].Received).Milliseconds(); fadeTime > 0 {
+			frame.Type = "fade"
+			frame.FadeTime = int(fadeTime)
+		}
+
+		//	Apply the changes, keeping track of the final value of each channel
+		targets := map[int]byte{}
+		order := []int{}
+		for _, change := range changes[i : end+1] {
+			for _, channel := range change.Channels {
+				if _, seen := targets[channel.Channel]; !seen {
+					order = append(order, channel.Channel)
+				}
+				targets[channel.Channel] = channel.Value
+				current[channel.Channel-1] = channel.Value
+			}
+		}
+		for _, channel := range order {
+			frame.Channels = append(frame.Channels, data2.ChannelValue{Channel: channel, Value: targets[channel]})
+		}
+
+		retval = append(retval, frame)
+		last = changes[end].Received
+		i = end + 1
+	}
+
+	return retval
+}
+
+// rampEnd finds the last change of a smooth ramp starting at changes[start].
+// A ramp is a run of closely spaced changes where every channel keeps moving
+// in the same direction.  If there isn't a ramp, start is returned.
+func rampEnd(current [512]byte, changes []InputChange, start int) int {
+	direction := map[int]int{}
+	end := start
+
+	for i := start; i < len(changes); i++ {
+		if i > start && changes[i].Received.Sub(changes[i-1].Received) > maxRampGap {
+			break
+		}
+
+		consistent := true
+		for _, channel := range changes[i].Channels {
+			delta := 1
+			if channel.Value < current[channel.Channel-1] {
+				delta = -1
+			}
+
+			if d, exists := direction[channel.Channel]; exists && d != delta {
+				consistent = false
+				break
+			}
+			direction[channel.Channel] = delta
+		}
+		if !consistent {
+			break
+		}
+
+		for _, channel := range changes[i].Channels {
+			current[channel.Channel-1] = channel.Value
+		}
+		end = i
+	}
+
+	if end-start+1 < minRampSteps {
+		return start
+	}
+
+	return end
+}
@@ -0,0 +1,108 @@
+package dmx
+
+import (
+	"testing"
+	"time"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+func TestRecord_RecordedFrames_ScenesAndSleeps_Successful(t *testing.T) {
+
+	//	Arrange
+	started := time.Now()
+	initial := [512]byte{}
+	initial[0] = 255 // Channel 1 is already on
+
+	changes := []InputChange{
+		{Received: started.Add(500 * time.Millisecond), Channels: []data2.ChannelValue{{Channel: 2, Value: 100}}},
+		{Received: started.Add(1500 * time.Millisecond), Channels: []data2.ChannelValue{{Channel: 1, Value: 0}}},
+	}
+
+	//	Act
+	frames := RecordedFrames(initial, started, changes, false)
+
+	//	Assert
+	if len(frames) != 5 {
+		t.Fatalf("RecordedFrames failed: Should get 5 frames but got: %+v", frames)
+	}
+
+	if frames[0].Type != "scene" || len(frames[0].Channels) != 2 {
+		t.Errorf("RecordedFrames failed: Should start with a scene for channels 1 and 2 but got: %+v", frames[0])
+	}
+
+	if frames[1].Type != "sleep" || frames[1].SleepTime != 500 {
+		t.Errorf("RecordedFrames failed: Should sleep 500ms before the first change but got: %+v", frames[1])
+	}
+
+	if frames[3].Type != "sleep" || frames[3].SleepTime != 1000 {
+		t.Errorf("RecordedFrames failed: Should sleep 1000ms before the second change but got: %+v", frames[3])
+	}
+
+	if frames[4].Type != "scene" || frames[4].Channels[0] != (data2.ChannelValue{Channel: 1, Value: 0}) {
+		t.Errorf("RecordedFrames failed: Should end with the second change but got: %+v", frames[4])
+	}
+}
+
+func TestRecord_RecordedFrames_CollapseFades_Successful(t *testing.T) {
+
+	//	Arrange
+	started := time.Now()
+	changes := []InputChange{}
+	for i := 1; i <= 10; i++ {
+		changes = append(changes, InputChange{
+			Received: started.Add(time.Duration(1000+i*20) * time.Millisecond),
+			Channels: []data2.ChannelValue{{Channel: 5, Value: byte(i * 25)}},
+		})
+	}
+
+	//	Then fade back down the other way
+	changes = append(changes, InputChange{Received: started.Add(1220 * time.Millisecond), Channels: []data2.ChannelValue{{Channel: 5, Value: 0}}})
+
+	//	Act
+	frames := RecordedFrames([512]byte{}, started, changes, true)
+
+	//	Assert
+	if len(frames) != 5 {
+		t.Fatalf("RecordedFrames failed: Should get 5 frames but got: %+v", frames)
+	}
+
+	if frames[2].Type != "fade" || frames[2].FadeTime != 180 || frames[2].Channels[0].Value != 250 {
+		t.Errorf("RecordedFrames failed: Should collapse the ramp into a 180ms fade but got: %+v", frames[2])
+	}
+
+	if frames[4].Type != "scene" || frames[4].Channels[0].Value != 0 {
+		t.Errorf("RecordedFrames failed: Should end with a scene for the change in direction but got: %+v", frames[4])
+	}
+}
+
+func TestRecord_StartStop_LotsOfChanges_RecordsEveryChange(t *testing.T) {
+
+	//	Arrange
+	universe := NewInputUniverse()
+	recorder := NewInputRecorder(universe)
+	if err := recorder.Start(RecordRequest{Name: "Take 1"}); err != nil {
+		t.Fatalf("Start - Should start recording without error, but got: %s", err)
+	}
+
+	//	Act
+	for i := 1; i <= 1000; i++ {
+		universe.Update(InputSourceArtNet, map[int]byte{1: byte(i % 256), 2: byte(i % 7)})
+	}
+	changes := recorder.Status().Changes
+	_, frames, err := recorder.Stop()
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("Stop - Should stop recording without error, but got: %s", err)
+	}
+
+	if changes != 1000 {
+		t.Errorf("Start failed: Should record all 1000 changes but got %v", changes)
+	}
+
+	last := frames[len(frames)-1]
+	if channels := channelMapOf(last.Channels); channels[1] != 1000%256 || channels[2] != 1000%7 {
+		t.Errorf("Stop failed: Should end with the last change but got: %+v", last)
+	}
+}
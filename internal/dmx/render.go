@@ -0,0 +1,209 @@
+package dmx
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+// DefaultRenderRate is how many samples a second a timeline is rendered at
+// if a rate isn't given (the rate timelines render at when they play)
+const DefaultRenderRate = float64(time.Second / renderInterval)
+
+// MaxRenderRate is the most samples a second a timeline can be rendered at
+const MaxRenderRate = 1000
+
+// These stop a long timeline (or one rendered at a high rate) from using all
+// the memory or running for ages.  Rendering stops as soon as it goes past
+// any of them.
+const (
+	maxRenderSamples  = 1000000
+	maxRenderValues   = 10000000 // Samples times channels
+	maxRenderDuration = 24 * time.Hour
+)
+
+// TimelineRender is a timeline's channel levels over time, from playing it
+// against a virtual clock (without any hardware)
+type TimelineRender struct {
+	ID       string         `json:"id"`       // The timeline ID
+	Name     string         `json:"name"`     // The timeline name
+	Rate     float64        `json:"rate"`     // Samples per second
+	Duration int            `json:"duration"` // How long the timeline plays, in milliseconds
+	Channels []int          `json:"channels"` // Every channel the timeline sets, lowest first
+	Samples  []RenderSample `json:"samples"`  // The channel levels at each sample time
+}
+
+// RenderSample is every channel's level at a time
+type RenderSample struct {
+	Time   int   `json:"time"`   // Milliseconds since the timeline started
+	Values []int `json:"values"` // Each channel's level (0 - 255), in the same order as the render's channels
+}
+
+// renderOutput is an output that samples the channels that are set at a
+// rate as the timeline plays.  Samples are kept flat (one after another in
+// values) with the levels of the channels set so far, in the order each
+// channel was first set (channels set later were 0 before).
+type renderOutput struct {
+	clock    Clock
+	start    time.Time
+	interval time.Duration // Time between samples
+	channels []int         // Every channel set, in the order they were first set
+	index    map[int]int   // Where each channel is in channels
+	levels   []byte        // Each channel's current level
+	samples  []int         // Where each sample starts in values
+	values   []byte
+	err      error  // Why rendering was stopped (if it was)
+	stop     func() // Stops playing the timeline
+}
+
+// sampleTime is when a sample is taken
+func (o *renderOutput) sampleTime(i int) time.Duration {
+	return time.Duration(math.Round(float64(i) * float64(o.interval)))
+}
+
+// check stops rendering if it's gone on too long
+func (o *renderOutput) check(at time.Duration) bool {
+	switch {
+	case o.err != nil:
+		return false
+	case at > maxRenderDuration:
+		o.err = fmt.Errorf("timeline plays for more than %v, which is too long to render", maxRenderDuration)
+	case float64(at)/float64(o.interval) > maxRenderSamples:
+		o.err = fmt.Errorf("rendering %v of timeline at %v samples per second is too many samples (use a lower rate)", at, float64(time.Second)/float64(o.interval))
+	case len(o.values) > maxRenderValues:
+		o.err = fmt.Errorf("rendering %v channels at %v samples per second is too many values (use a lower rate)", len(o.channels), float64(time.Second)/float64(o.interval))
+	default:
+		return true
+	}
+
+	o.stop()
+	return false
+}
+
+// sampleBefore takes every sample due before a time, with the current levels
+func (o *renderOutput) sampleBefore(at time.Duration) {
+	for next := o.sampleTime(len(o.samples)); next < at && o.check(next); next = o.sampleTime(len(o.samples)) {
+		o.sample()
+	}
+}
+
+// sample takes a sample with the current levels
+func (o *renderOutput) sample() {
+	o.samples = append(o.samples, len(o.values))
+	o.values = append(o.values, o.levels...)
+}
+
+func (o *renderOutput) SetChannel(channel int, value byte) error {
+	at := o.clock.Now().Sub(o.start)
+	if !o.check(at) {
+		return o.err
+	}
+	o.sampleBefore(at)
+
+	i, ok := o.index[channel]
+	if !ok {
+		i = len(o.channels)
+		o.index[channel] = i
+		o.channels = append(o.channels, channel)
+		o.levels = append(o.levels, 0)
+	}
+	o.levels[i] = value
+
+	return nil
+}
+
+func (o *renderOutput) Render() error {
+	o.check(o.clock.Now().Sub(o.start))
+	return o.err
+}
+
+// RenderTimeline plays a timeline against a virtual clock and samples every
+// channel it sets at a rate (samples per second).  The last sample is the
+// timeline's final state.
+func (p Patch) RenderTimeline(timeline data2.Timeline, rate float64) (TimelineRender, error) {
+	retval := TimelineRender{ID: timeline.ID, Name: timeline.Name, Rate: rate, Channels: []int{}, Samples: []RenderSample{}}
+
+	if !(rate > 0 && rate <= MaxRenderRate) {
+		return retval, fmt.Errorf("rate must be greater than 0 and at most %v samples per second", MaxRenderRate)
+	}
+
+	tracks, err := p.resolveTimeline(timeline)
+	if err != nil {
+		return retval, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clock := NewVirtualClock(time.Unix(0, 0).UTC())
+	out := &renderOutput{clock: clock, start: clock.Now(), interval: time.Duration(float64(time.Second) / rate), index: map[int]int{}, stop: cancel}
+	playTimeline(ctx, clock, out, tracks)
+
+	//	Sample through to the end (and then the final state)
+	duration := clock.Now().Sub(out.start)
+	if out.check(duration) {
+		out.sampleBefore(duration)
+	}
+	if out.err != nil {
+		return retval, out.err
+	}
+	out.sample()
+
+	retval.Duration = int(duration.Milliseconds())
+
+	//	Put the channels in order (lowest first)
+	retval.Channels = append(retval.Channels, out.channels...)
+	sort.Ints(retval.Channels)
+
+	for i, start := range out.samples {
+		end := len(out.values)
+		if i+1 < len(out.samples) {
+			end = out.samples[i+1]
+		}
+
+		values := make([]int, len(retval.Channels))
+		for j, channel := range retval.Channels {
+			if at := start + out.index[channel]; at < end {
+				values[j] = int(out.values[at])
+			}
+		}
+
+		at := out.sampleTime(i)
+		if i == len(out.samples)-1 {
+			at = duration
+		}
+		retval.Samples = append(retval.Samples, RenderSample{Time: int(at.Milliseconds()), Values: values})
+	}
+
+	return retval, nil
+}
+
+// WriteCSV writes the render as CSV: a row for each sample, with its time
+// (in milliseconds) and then each channel's level
+func (r TimelineRender) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"time"}
+	for _, channel := range r.Channels {
+		header = append(header, strconv.Itoa(channel))
+	}
+	writer.Write(header)
+
+	for _, sample := range r.Samples {
+		row := []string{strconv.Itoa(sample.Time)}
+		for _, value := range sample.Values {
+			row = append(row, strconv.Itoa(value))
+		}
+		writer.Write(row)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,123 @@
+package dmx
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+func getTestRenderTimeline() data2.Timeline {
+	return data2.Timeline{
+		ID:   "show",
+		Name: "Show",
+		Frames: []data2.TimelineFrame{
+			{Type: "scene", Channels: []data2.ChannelValue{{Channel: 5, Value: 10}}},
+			{Type: "fade", FadeTime: 100, Channels: []data2.ChannelValue{{Channel: 1, Value: 200}}},
+			{Type: "sleep", SleepTime: 30},
+		},
+	}
+}
+
+func TestRender_RenderTimeline_SamplesEveryChannel(t *testing.T) {
+
+	//	Act
+	render, err := getTestPatch().RenderTimeline(getTestRenderTimeline(), 20)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("RenderTimeline - Should render without error, but got: %s", err)
+	}
+
+	if render.Duration != 130 || !reflect.DeepEqual(render.Channels, []int{1, 5}) {
+		t.Fatalf("RenderTimeline failed: Should render channels 1 and 5 for 130ms but got %v for %vms", render.Channels, render.Duration)
+	}
+
+	want := []RenderSample{
+		{Time: 0, Values: []int{0, 10}},
+		{Time: 50, Values: []int{100, 10}},
+		{Time: 100, Values: []int{200, 10}},
+		{Time: 130, Values: []int{200, 10}},
+	}
+	if !reflect.DeepEqual(render.Samples, want) {
+		t.Errorf("RenderTimeline failed: Should sample every 50ms (and at the end) but got %+v", render.Samples)
+	}
+}
+
+func TestRender_RenderTimeline_SameEveryTime(t *testing.T) {
+
+	//	Arrange
+	timeline := data2.Timeline{ID: "show", Frames: []data2.TimelineFrame{
+		{Type: "effect", Fixtures: []data2.FixtureValue{{Group: "Pars"}}, Effect: &data2.Effect{Shape: "candle", Seed: 3, Duration: 2000}},
+	}}
+
+	//	Act
+	first, err := getTestPatch().RenderTimeline(timeline, 100)
+	second, _ := getTestPatch().RenderTimeline(timeline, 100)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("RenderTimeline - Should render without error, but got: %s", err)
+	}
+
+	if len(first.Samples) != 201 || !reflect.DeepEqual(first, second) {
+		t.Errorf("RenderTimeline failed: Should render the same 201 samples every time")
+	}
+}
+
+func TestRender_RenderTimeline_Errors_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	broken := data2.Timeline{ID: "broken", Frames: []data2.TimelineFrame{{Type: "scene", Fixtures: []data2.FixtureValue{{Fixture: "Nobody"}}}}}
+	long := data2.Timeline{ID: "long", Frames: []data2.TimelineFrame{{Type: "sleep", SleepTime: 3600000}}}
+	longFade := data2.Timeline{ID: "longfade", Frames: []data2.TimelineFrame{{Type: "fade", FadeTime: 36000000, Channels: []data2.ChannelValue{{Channel: 1, Value: 255}, {Channel: 2, Value: 255}}}}}
+	days := data2.Timeline{ID: "days", Frames: []data2.TimelineFrame{{Type: "sleep", SleepTime: 2 * 86400000}, {Type: "scene", Channels: []data2.ChannelValue{{Channel: 1, Value: 255}}}}}
+	tests := []struct {
+		timeline data2.Timeline
+		rate     float64
+	}{
+		{getTestRenderTimeline(), 0},
+		{getTestRenderTimeline(), 5000},
+		{getTestRenderTimeline(), -10},
+		{getTestRenderTimeline(), math.NaN()},
+		{broken, DefaultRenderRate},
+		{long, 1000},
+		{longFade, 1000},
+		{days, 1},
+	}
+
+	for _, test := range tests {
+		//	Act
+		_, err := getTestPatch().RenderTimeline(test.timeline, test.rate)
+
+		//	Assert
+		if err == nil {
+			t.Errorf("RenderTimeline - Should return error for timeline '%s' at %v, but got none", test.timeline.ID, test.rate)
+		}
+	}
+}
+
+func TestRender_WriteCSV_Successful(t *testing.T) {
+
+	//	Arrange
+	render, err := getTestPatch().RenderTimeline(getTestRenderTimeline(), 20)
+	if err != nil {
+		t.Fatalf("RenderTimeline - Should render without error, but got: %s", err)
+	}
+	out := &bytes.Buffer{}
+
+	//	Act
+	err = render.WriteCSV(out)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("WriteCSV - Should write without error, but got: %s", err)
+	}
+
+	want := "time,1,5\n0,0,10\n50,100,10\n100,200,10\n130,200,10\n"
+	if out.String() != want {
+		t.Errorf("WriteCSV failed: Should write %q but got %q", want, out.String())
+	}
+}
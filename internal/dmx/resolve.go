@@ -0,0 +1,585 @@
+package dmx
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/danesparza/fxdmx/internal/color"
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+// AttributeColor is the fixture pseudo attribute that sets a fixture's color
+// emitters from a color (like '#ff8800', 'hsv(30, 100%, 100%)' or '3200K')
+const AttributeColor = "color"
+
+// Patch resolves fixture (and group) names, attributes and presets to DMX channels
+type Patch struct {
+	fixtures map[string]patchedFixture
+	groups   map[string]patchedGroup
+	presets  map[string]data2.Preset
+
+	//	Timelines that timeline frames can play (by ID), and the timelines
+	//	being resolved (so a timeline can't play itself)
+	timelines map[string]data2.Timeline
+	calling   []string
+}
+
+type patchedFixture struct {
+	fixture data2.Fixture
+	mode    data2.FixtureMode
+	err     error // Set if the fixture's profile or mode can't be found
+}
+
+// patchedGroup is a fixture group with its fixtures (by name, in group order)
+type patchedGroup struct {
+	fixtures []string
+	err      error // Set if a fixture in the group isn't patched
+}
+
+// playFrame is a timeline frame resolved for playing
+type playFrame struct {
+	data2.TimelineFrame
+
+	// colorFades are fixture colors that fade in a color space (instead of channel by channel)
+	colorFades []colorFade
+
+	// delays are how long each channel waits before it starts fading (for group offsets)
+	delays map[int]time.Duration
+
+	// effects are the waveforms an effect frame runs
+	effects []effect
+
+	// steps are a chase frame's (resolved) steps
+	steps []playFrame
+
+	// keyframes are a keyframe frame's channels, with their (resolved) keyframes
+	keyframes []keyframeChannel
+
+	// tracks are a timeline frame's timeline's (resolved) tracks
+	tracks [][]playFrame
+}
+
+// colorFade is a fixture's color fading in a color space
+type colorFade struct {
+	fixture  patchedFixture
+	from, to color.RGB
+	space    string
+	delay    time.Duration
+}
+
+// fixtureTarget is a single fixture's part of a frame's fixture value
+type fixtureTarget struct {
+	item       patchedFixture
+	attributes map[string]interface{}
+	delay      time.Duration
+}
+
+// NewPatch creates a patch from a list of fixtures, the profiles they use,
+// the groups they're in and the presets frames can use.  A fixture with a
+// missing profile or mode (or a group with a fixture that isn't patched) only
+// causes an error if a frame refers to it.
+func NewPatch(fixtures []data2.Fixture, profiles []data2.FixtureProfile, groups []data2.FixtureGroup, presets []data2.Preset) Patch {
+	retval := Patch{fixtures: map[string]patchedFixture{}, groups: map[string]patchedGroup{}, presets: map[string]data2.Preset{}}
+
+	byID := map[string]data2.FixtureProfile{}
+	for _, profile := range profiles {
+		byID[profile.ID] = profile
+	}
+
+	for _, fixture := range fixtures {
+		item := patchedFixture{fixture: fixture}
+
+		profile, found := byID[fixture.ProfileID]
+		if !found {
+			item.err = fmt.Errorf("fixture '%s' uses fixture profile '%s' which was not found", fixture.Name, fixture.ProfileID)
+		} else if item.mode, found = profile.Mode(fixture.Mode); !found {
+			item.err = fmt.Errorf("fixture '%s' uses mode '%s' which isn't in fixture profile '%s %s'", fixture.Name, fixture.Mode, profile.Manufacturer, profile.Model)
+		}
+
+		retval.fixtures[strings.ToLower(fixture.Name)] = item
+	}
+
+	names := map[string]string{}
+	for _, fixture := range fixtures {
+		names[fixture.ID] = fixture.Name
+	}
+
+	for _, group := range groups {
+		item := patchedGroup{}
+		for _, id := range group.Fixtures {
+			name, found := names[id]
+			if !found {
+				item.err = fmt.Errorf("group '%s' has fixture '%s' which isn't patched", group.Name, id)
+				break
+			}
+			item.fixtures = append(item.fixtures, name)
+		}
+
+		retval.groups[strings.ToLower(group.Name)] = item
+	}
+
+	for _, preset := range presets {
+		retval.presets[strings.ToLower(preset.Name)] = preset
+	}
+
+	return retval
+}
+
+// LoadPatch loads the current patch from the database
+func LoadPatch(db *data2.Manager) (Patch, error) {
+	fixtures, err := db.GetAllFixtures()
+	if err != nil {
+		return Patch{}, err
+	}
+
+	profiles, err := db.GetAllFixtureProfiles()
+	if err != nil {
+		return Patch{}, err
+	}
+
+	groups, err := db.GetAllFixtureGroups()
+	if err != nil {
+		return Patch{}, err
+	}
+
+	presets, err := db.GetAllPresets()
+	if err != nil {
+		return Patch{}, err
+	}
+
+	timelines, err := db.GetAllTimelines()
+	if err != nil {
+		return Patch{}, err
+	}
+
+	retval := NewPatch(fixtures, profiles, groups, presets)
+	retval.timelines = map[string]data2.Timeline{}
+	for _, timeline := range timelines {
+		retval.timelines[timeline.ID] = timeline
+	}
+
+	return retval, nil
+}
+
+// ResolveFrame returns the channel values for a frame.  Raw channel values
+// win over fixture attributes that set the same channel, and fixture
+// attributes win over preset channels.
+func (p Patch) ResolveFrame(frame data2.TimelineFrame) ([]data2.ChannelValue, error) {
+	frame.ColorSpace = ""
+	resolved, err := p.resolveFrame(frame, map[string]color.RGB{})
+	return resolved.Channels, err
+}
+
+// resolveFrames resolves every frame in a timeline for playing
+func (p Patch) resolveFrames(frames []data2.TimelineFrame) ([]playFrame, error) {
+	retval := make([]playFrame, len(frames))
+
+	//	The last color each fixture was set to (so colors can fade from it)
+	colors := map[string]color.RGB{}
+
+	for i, frame := range frames {
+		resolved, err := p.resolveFrame(frame, colors)
+		if err != nil {
+			return nil, fmt.Errorf("frame %v: %v", i+1, err)
+		}
+		retval[i] = resolved
+	}
+
+	return retval, nil
+}
+
+// resolveFrame resolves a frame's presets and fixture attributes to channel
+// values (and color fades)
+func (p Patch) resolveFrame(frame data2.TimelineFrame, colors map[string]color.RGB) (playFrame, error) {
+	retval := playFrame{TimelineFrame: frame}
+	retval.Fixtures = nil
+	retval.Presets = nil
+	isEffect := strings.EqualFold(frame.Type, "effect")
+
+	if strings.EqualFold(frame.Type, "chase") {
+		steps, err := p.resolveChase(frame, colors)
+		retval.steps = steps
+		return retval, err
+	}
+
+	if strings.EqualFold(frame.Type, "keyframes") {
+		keyframes, err := p.resolveKeyframes(frame, colors)
+		retval.keyframes = keyframes
+		return retval, err
+	}
+
+	if strings.EqualFold(frame.Type, "timeline") {
+		tracks, err := p.resolveTimelineFrame(frame)
+		retval.tracks = tracks
+		return retval, err
+	}
+
+	if len(frame.Fixtures) == 0 && len(frame.Presets) == 0 && !isEffect {
+		return retval, nil
+	}
+
+	space := strings.ToLower(frame.ColorSpace)
+	if !color.ValidSpace(space) {
+		return retval, fmt.Errorf("colorspace '%s' must be one of rgb, hsv or perceptual", frame.ColorSpace)
+	}
+	fadeColors := strings.EqualFold(frame.Type, "fade") && space != "" && space != color.SpaceRGB
+
+	channels := []data2.ChannelValue{}
+	index := map[int]int{}
+	set := func(value data2.ChannelValue) {
+		if i, exists := index[value.Channel]; exists {
+			channels[i] = value
+			return
+		}
+		index[value.Channel] = len(channels)
+		channels = append(channels, value)
+	}
+
+	for _, name := range frame.Presets {
+		preset, found := p.presets[strings.ToLower(name)]
+		if !found {
+			return retval, fmt.Errorf("preset '%s' doesn't exist", name)
+		}
+		if len(preset.Channels) == 0 {
+			return retval, fmt.Errorf("preset '%s' doesn't have any channels (use it on a fixture or group instead)", preset.Name)
+		}
+
+		for _, value := range preset.Channels {
+			set(value)
+		}
+	}
+
+	for _, fixtureValue := range frame.Fixtures {
+		targets, err := p.targets(fixtureValue, space)
+		if err != nil {
+			return retval, err
+		}
+
+		for _, target := range targets {
+			item := target.item
+
+			values, fixtureColor, err := item.resolve(target.attributes)
+			if err != nil {
+				return retval, err
+			}
+
+			if fixtureColor != nil {
+				colorValues, err := item.colorLevels(*fixtureColor)
+				if err != nil {
+					return retval, err
+				}
+
+				//	Fade the color in its color space, or set its channels like any other attribute
+				key := strings.ToLower(item.fixture.Name)
+				if fadeColors {
+					retval.colorFades = append(retval.colorFades, colorFade{fixture: item, from: colors[key], to: *fixtureColor, space: space, delay: target.delay})
+				} else {
+					values = append(values, colorValues...)
+				}
+				colors[key] = *fixtureColor
+			}
+
+			for _, value := range values {
+				set(value)
+				if target.delay > 0 {
+					if retval.delays == nil {
+						retval.delays = map[int]time.Duration{}
+					}
+					retval.delays[value.Channel] = target.delay
+				}
+			}
+		}
+	}
+
+	//	An effect frame's channels are what the effect runs on (not values to set)
+	if isEffect {
+		effects, err := p.resolveEffect(frame)
+		if err != nil {
+			return retval, err
+		}
+		retval.effects = effects
+		retval.Channels = channels
+		return retval, nil
+	}
+
+	for _, value := range frame.Channels {
+		set(value)
+		delete(retval.delays, value.Channel)
+	}
+
+	retval.Channels = channels
+	return retval, nil
+}
+
+// targets finds the fixtures a fixture value sets, with each fixture's
+// attributes (spread across a group) and fade delay
+func (p Patch) targets(fixtureValue data2.FixtureValue, space string) ([]fixtureTarget, error) {
+	hasFixture := strings.TrimSpace(fixtureValue.Fixture) != ""
+	hasGroup := strings.TrimSpace(fixtureValue.Group) != ""
+
+	switch {
+	case hasFixture && hasGroup:
+		return nil, fmt.Errorf("set either fixture or group, not both (fixture '%s', group '%s')", fixtureValue.Fixture, fixtureValue.Group)
+	case !hasFixture && !hasGroup:
+		return nil, fmt.Errorf("either fixture or group is required")
+	}
+
+	attributes, err := p.presetAttributes(fixtureValue)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasFixture {
+		item, found := p.fixtures[strings.ToLower(fixtureValue.Fixture)]
+		if !found {
+			return nil, fmt.Errorf("fixture '%s' isn't patched", fixtureValue.Fixture)
+		}
+		if item.err != nil {
+			return nil, item.err
+		}
+		return []fixtureTarget{{item: item, attributes: attributes}}, nil
+	}
+
+	group, found := p.groups[strings.ToLower(fixtureValue.Group)]
+	if !found {
+		return nil, fmt.Errorf("group '%s' doesn't exist", fixtureValue.Group)
+	}
+	if group.err != nil {
+		return nil, group.err
+	}
+
+	retval := []fixtureTarget{}
+	for i, name := range group.fixtures {
+		item := p.fixtures[strings.ToLower(name)]
+		if item.err != nil {
+			return nil, item.err
+		}
+
+		//	Spread the attributes evenly from the first fixture in the group to the last
+		progress := 0.0
+		if len(group.fixtures) > 1 {
+			progress = float64(i) / float64(len(group.fixtures)-1)
+		}
+
+		spread, err := spreadAttributes(attributes, fixtureValue.Spread, progress, space)
+		if err != nil {
+			return nil, fmt.Errorf("group '%s' %v", fixtureValue.Group, err)
+		}
+
+		retval = append(retval, fixtureTarget{
+			item:       item,
+			attributes: spread,
+			delay:      time.Duration(fixtureValue.Offset*i) * time.Millisecond,
+		})
+	}
+
+	return retval, nil
+}
+
+// presetAttributes gets a fixture value's attributes on top of its preset's
+// attributes (if it uses a preset)
+func (p Patch) presetAttributes(fixtureValue data2.FixtureValue) (map[string]interface{}, error) {
+	if strings.TrimSpace(fixtureValue.Preset) == "" {
+		return fixtureValue.Attributes, nil
+	}
+
+	preset, found := p.presets[strings.ToLower(fixtureValue.Preset)]
+	if !found {
+		return nil, fmt.Errorf("preset '%s' doesn't exist", fixtureValue.Preset)
+	}
+	if len(preset.Attributes) == 0 {
+		return nil, fmt.Errorf("preset '%s' doesn't have any attributes (use it in the frame's presets instead)", preset.Name)
+	}
+
+	retval := map[string]interface{}{}
+	for name, value := range preset.Attributes {
+		retval[name] = value
+	}
+	for name, value := range fixtureValue.Attributes {
+		retval[name] = value
+	}
+
+	return retval, nil
+}
+
+// spreadAttributes finds the attribute values part way (progress 0.0 - 1.0)
+// from the starting attributes to the spread attributes.  Colors are spread
+// in the color space.
+func spreadAttributes(attributes, spread map[string]interface{}, progress float64, space string) (map[string]interface{}, error) {
+	retval := map[string]interface{}{}
+	for name, value := range attributes {
+		retval[name] = value
+	}
+
+	for name, end := range spread {
+		start, found := attributes[name]
+		if !found {
+			return nil, fmt.Errorf("spread %s needs a starting value in attributes", name)
+		}
+
+		if strings.EqualFold(name, AttributeColor) {
+			startText, startOK := start.(string)
+			endText, endOK := end.(string)
+			if !startOK || !endOK {
+				return nil, fmt.Errorf("spread color must be a color string (like '#ff8800')")
+			}
+			from, err := color.Parse(startText)
+			if err != nil {
+				return nil, err
+			}
+			to, err := color.Parse(endText)
+			if err != nil {
+				return nil, err
+			}
+			retval[name] = color.Blend(from, to, progress, space)
+			continue
+		}
+
+		from, err := normalizedLevel(start)
+		if err != nil {
+			return nil, fmt.Errorf("%s %v", name, err)
+		}
+		to, err := normalizedLevel(end)
+		if err != nil {
+			return nil, fmt.Errorf("spread %s %v", name, err)
+		}
+		retval[name] = from + (to-from)*progress
+	}
+
+	return retval, nil
+}
+
+// resolve resolves a fixture's attributes to channel values.  The color (if
+// there is one) is returned separately.
+func (item patchedFixture) resolve(attributes map[string]interface{}) ([]data2.ChannelValue, *color.RGB, error) {
+	var fixtureColor *color.RGB
+
+	//	Resolve attributes in a stable order
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	retval := []data2.ChannelValue{}
+	for _, name := range names {
+		attribute := strings.ToLower(name)
+		value := attributes[name]
+
+		if attribute == AttributeColor {
+			switch v := value.(type) {
+			case color.RGB:
+				fixtureColor = &v
+			case string:
+				parsed, err := color.Parse(v)
+				if err != nil {
+					return nil, nil, fmt.Errorf("fixture '%s' %v", item.fixture.Name, err)
+				}
+				fixtureColor = &parsed
+			default:
+				return nil, nil, fmt.Errorf("fixture '%s' color must be a color string (like '#ff8800', 'hsv(30, 100%%, 100%%)' or '3200K')", item.fixture.Name)
+			}
+			continue
+		}
+
+		level, err := normalizedLevel(value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("fixture '%s' %s %v", item.fixture.Name, name, err)
+		}
+
+		levels := item.levels(attribute, level)
+		if len(levels) == 0 {
+			return nil, nil, fmt.Errorf("fixture '%s' doesn't have a '%s' channel", item.fixture.Name, name)
+		}
+		retval = append(retval, levels...)
+	}
+
+	return retval, fixtureColor, nil
+}
+
+// colorLevels returns the channel values that set the fixture's emitters to a color
+func (item patchedFixture) colorLevels(c color.RGB) ([]data2.ChannelValue, error) {
+	available := map[string]bool{}
+	for _, channel := range item.mode.Channels {
+		available[channel.Attribute] = true
+	}
+
+	emitters, err := c.Emitters(available)
+	if err != nil {
+		return nil, fmt.Errorf("fixture '%s' %v", item.fixture.Name, err)
+	}
+
+	//	Set the emitters in a stable order
+	attributes := make([]string, 0, len(emitters))
+	for attribute := range emitters {
+		attributes = append(attributes, attribute)
+	}
+	sort.Strings(attributes)
+
+	retval := []data2.ChannelValue{}
+	for _, attribute := range attributes {
+		retval = append(retval, item.levels(attribute, emitters[attribute])...)
+	}
+
+	return retval, nil
+}
+
+// levels returns the channel values that set an attribute to a level (0.0 - 1.0).
+// A coarse channel followed by a fine channel for the same attribute is set
+// as a single 16 bit value (and any extra fine channels are set to 0).
+func (item patchedFixture) levels(attribute string, level float64) []data2.ChannelValue {
+	retval := []data2.ChannelValue{}
+	paired := map[int]bool{}
+
+	for i, channel := range item.mode.Channels {
+		if channel.Attribute != attribute || paired[i] {
+			continue
+		}
+
+		value := data2.ChannelValue{Channel: item.fixture.Channel(i + 1), Value: byte(math.Round(level * 255))}
+
+		if !channel.Fine {
+			//	Look for the fine channel to go with this one
+			for j := i + 1; j < len(item.mode.Channels); j++ {
+				next := item.mode.Channels[j]
+				if next.Attribute == attribute && !next.Fine {
+					break
+				}
+				if next.Attribute == attribute && next.Fine {
+					value.Fine = item.fixture.Channel(j + 1)
+					value.Value16 = uint16(math.Round(level * 65535))
+					paired[j] = true
+					break
+				}
+			}
+		} else {
+			value.Value = 0
+		}
+
+		retval = append(retval, value)
+	}
+
+	return retval
+}
+
+// normalizedLevel converts an attribute value to a level between 0.0 and 1.0
+func normalizedLevel(value interface{}) (float64, error) {
+	var level float64
+	switch v := value.(type) {
+	case float64:
+		level = v
+	case int:
+		level = float64(v)
+	default:
+		return 0, fmt.Errorf("must be a number between 0.0 and 1.0")
+	}
+
+	if level < 0 || level > 1 || math.IsNaN(level) {
+		return 0, fmt.Errorf("must be between 0.0 and 1.0 but got %v", level)
+	}
+
+	return level, nil
+}
@@ -0,0 +1,223 @@
+package dmx
+
+import (
+	"testing"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+func getTestPatch() Patch {
+	profiles := []data2.FixtureProfile{
+		{
+			ID:    "par",
+			Model: "Par 64",
+			Modes: []data2.FixtureMode{
+				{Name: "4 channel", Channels: []data2.FixtureChannel{
+					{Name: "Dimmer", Attribute: data2.AttributeDimmer},
+					{Name: "Red", Attribute: data2.AttributeRed},
+					{Name: "Green", Attribute: data2.AttributeGreen},
+					{Name: "Blue", Attribute: data2.AttributeBlue},
+				}},
+			},
+		},
+		{
+			ID:    "mover",
+			Model: "Mover",
+			Modes: []data2.FixtureMode{
+				{Name: "3 channel", Channels: []data2.FixtureChannel{
+					{Name: "Pan", Attribute: data2.AttributePan},
+					{Name: "Pan fine", Attribute: data2.AttributePan, Fine: true},
+					{Name: "Dimmer", Attribute: data2.AttributeDimmer},
+				}},
+			},
+		},
+	}
+
+	fixtures := []data2.Fixture{
+		{ID: "left", Name: "Stage Left Par", ProfileID: "par", Mode: "4 channel", Universe: 1, Address: 17, Footprint: 4},
+		{ID: "right", Name: "Stage Right Par", ProfileID: "par", Mode: "4 channel", Universe: 1, Address: 21, Footprint: 4},
+		{ID: "center", Name: "Center Par", ProfileID: "par", Mode: "4 channel", Universe: 1, Address: 25, Footprint: 4},
+		{Name: "Mover", ProfileID: "mover", Mode: "3 channel", Universe: 1, Address: 100, Footprint: 3},
+		{Name: "Lost", ProfileID: "gone", Mode: "1 channel", Universe: 1, Address: 200, Footprint: 1},
+	}
+
+	groups := []data2.FixtureGroup{
+		{Name: "Pars", Fixtures: []string{"left", "center", "right"}},
+		{Name: "Broken", Fixtures: []string{"left", "unpatched"}},
+	}
+
+	presets := []data2.Preset{
+		{Name: "Warm white", Attributes: map[string]interface{}{"dimmer": 1.0, "color": "#ffcc88"}},
+		{Name: "Blackout", Channels: []data2.ChannelValue{{Channel: 17, Value: 0}, {Channel: 21, Value: 0}, {Channel: 25, Value: 0}, {Channel: 102, Value: 0}}},
+	}
+
+	return NewPatch(fixtures, profiles, groups, presets)
+}
+
+func channelMapOf(values []data2.ChannelValue) map[int]byte {
+	retval := map[int]byte{}
+	for _, value := range values {
+		retval[value.Channel] = value.Value
+	}
+	return retval
+}
+
+func TestResolve_ResolveFrame_DimmerAndColor_Successful(t *testing.T) {
+
+	//	Arrange
+	patch := getTestPatch()
+	frame := data2.TimelineFrame{
+		Type: "scene",
+		Fixtures: []data2.FixtureValue{
+			{Fixture: "stage left par", Attributes: map[string]interface{}{"dimmer": 1.0, "color": "#ff8800"}},
+		},
+	}
+
+	//	Act
+	channels, err := patch.ResolveFrame(frame)
+	got := channelMapOf(channels)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("ResolveFrame - Should resolve without error, but got: %s", err)
+	}
+
+	if len(channels) != 4 || got[17] != 255 || got[18] != 255 || got[19] != 0x88 || got[20] != 0 {
+		t.Errorf("ResolveFrame failed: Should resolve dimmer and color to channels 17-20 but got: %v", channels)
+	}
+}
+
+func TestResolve_ResolveFrame_16BitChannel_Successful(t *testing.T) {
+
+	//	Arrange
+	patch := getTestPatch()
+	frame := data2.TimelineFrame{
+		Type: "scene",
+		Fixtures: []data2.FixtureValue{
+			{Fixture: "Mover", Attributes: map[string]interface{}{"pan": 0.5}},
+		},
+		Channels: []data2.ChannelValue{{Channel: 102, Value: 10}},
+	}
+
+	//	Act
+	channels, err := patch.ResolveFrame(frame)
+	got := channelMapOf(channels)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("ResolveFrame - Should resolve without error, but got: %s", err)
+	}
+
+	if len(channels) != 2 || channels[0].Channel != 100 || channels[0].Fine != 101 || channels[0].Value16 != 0x8000 || got[102] != 10 {
+		t.Errorf("ResolveFrame failed: Should set pan as a 16 bit value across the coarse and fine channels but got: %v", channels)
+	}
+}
+
+func TestResolve_ResolveFrame_GroupSpread_Successful(t *testing.T) {
+
+	//	Arrange
+	patch := getTestPatch()
+	frame := data2.TimelineFrame{
+		Type: "scene",
+		Fixtures: []data2.FixtureValue{
+			{
+				Group:      "pars",
+				Attributes: map[string]interface{}{"dimmer": 0.0, "color": "#ff0000"},
+				Spread:     map[string]interface{}{"dimmer": 1.0, "color": "#0000ff"},
+			},
+		},
+	}
+
+	//	Act
+	channels, err := patch.ResolveFrame(frame)
+	got := channelMapOf(channels)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("ResolveFrame - Should resolve without error, but got: %s", err)
+	}
+
+	//	Group order is left (17), center (25), right (21)
+	if got[17] != 0 || got[18] != 255 || got[20] != 0 {
+		t.Errorf("ResolveFrame failed: Should set the first fixture in the group to the starting attributes but got: %v", channels)
+	}
+
+	if got[25] != 128 || got[26] != 128 || got[28] != 128 {
+		t.Errorf("ResolveFrame failed: Should spread the middle fixture in the group halfway but got: %v", channels)
+	}
+
+	if got[21] != 255 || got[22] != 0 || got[24] != 255 {
+		t.Errorf("ResolveFrame failed: Should set the last fixture in the group to the spread attributes but got: %v", channels)
+	}
+}
+
+func TestResolve_ResolveFrame_Presets_Successful(t *testing.T) {
+
+	//	Arrange
+	patch := getTestPatch()
+	frame := data2.TimelineFrame{
+		Type:    "scene",
+		Presets: []string{"blackout"},
+		Fixtures: []data2.FixtureValue{
+			{Fixture: "Stage Left Par", Preset: "Warm White", Attributes: map[string]interface{}{"dimmer": 0.5}},
+		},
+	}
+
+	//	Act
+	channels, err := patch.ResolveFrame(frame)
+	got := channelMapOf(channels)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("ResolveFrame - Should resolve without error, but got: %s", err)
+	}
+
+	if got[17] != 128 || got[18] != 0xff || got[19] != 0xcc || got[20] != 0x88 {
+		t.Errorf("ResolveFrame failed: Should set the preset's attributes (with the frame's attributes winning) but got: %v", channels)
+	}
+
+	if got[21] != 0 || got[25] != 0 || got[102] != 0 || len(channels) != 7 {
+		t.Errorf("ResolveFrame failed: Should set the preset's channels but got: %v", channels)
+	}
+}
+
+func TestResolve_ResolveFrames_Errors_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	patch := getTestPatch()
+	tests := []data2.FixtureValue{
+		{Fixture: "Nobody", Attributes: map[string]interface{}{"dimmer": 1.0}},
+		{Fixture: "Lost", Attributes: map[string]interface{}{"dimmer": 1.0}},
+		{Fixture: "Mover", Attributes: map[string]interface{}{"color": "#ffffff"}},
+		{Fixture: "Mover", Attributes: map[string]interface{}{"gobo": 0.5}},
+		{Fixture: "Mover", Attributes: map[string]interface{}{"dimmer": 2.0}},
+		{Fixture: "Stage Left Par", Attributes: map[string]interface{}{"color": "orange"}},
+		{Attributes: map[string]interface{}{"dimmer": 1.0}},
+		{Fixture: "Mover", Group: "Pars", Attributes: map[string]interface{}{"dimmer": 1.0}},
+		{Group: "Nobody", Attributes: map[string]interface{}{"dimmer": 1.0}},
+		{Group: "Broken", Attributes: map[string]interface{}{"dimmer": 1.0}},
+		{Group: "Pars", Attributes: map[string]interface{}{"dimmer": 1.0}, Spread: map[string]interface{}{"red": 1.0}},
+		{Fixture: "Mover", Preset: "Nothing"},
+		{Fixture: "Mover", Preset: "Blackout"},
+	}
+
+	for _, test := range tests {
+		//	Act
+		_, err := patch.resolveFrames([]data2.TimelineFrame{{Type: "scene", Fixtures: []data2.FixtureValue{test}}})
+
+		//	Assert
+		if err == nil {
+			t.Errorf("resolveFrames - Should return error for %+v, but got none", test)
+		}
+	}
+
+	for _, name := range []string{"Nothing", "Warm white"} {
+		//	Act
+		_, err := patch.resolveFrames([]data2.TimelineFrame{{Type: "scene", Presets: []string{name}}})
+
+		//	Assert
+		if err == nil {
+			t.Errorf("resolveFrames - Should return error for preset '%s', but got none", name)
+		}
+	}
+}
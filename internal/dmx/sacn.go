@@ -0,0 +1,92 @@
+package dmx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+)
+
+// sACN (ANSI E1.31) packet layout.  Offsets are from the start of the UDP payload.
+const (
+	sacnPort               = 5568
+	sacnRootVectorData     = 0x00000004
+	sacnFramingVectorData  = 0x00000002
+	sacnDMPVectorSetProp   = 0x02
+	sacnOffsetRootVector   = 18
+	sacnOffsetFrameVector  = 40
+	sacnOffsetOptions      = 112
+	sacnOffsetUniverse     = 113
+	sacnOffsetDMPVector    = 117
+	sacnOffsetValueCount   = 123
+	sacnOffsetStartCode    = 125
+	sacnOptionsStreamTerm  = 0x40
+	sacnOptionsPreviewData = 0x80
+)
+
+var sacnPacketID = []byte("ASC-E1.17\x00\x00\x00")
+
+// parseSACN parses an E1.31 data packet and returns the universe and the
+// channel values (starting with channel 1)
+func parseSACN(packet []byte) (int, []byte, bool) {
+	if len(packet) <= sacnOffsetStartCode || !bytes.Equal(packet[4:16], sacnPacketID) {
+		return 0, nil, false
+	}
+
+	if be32(packet[sacnOffsetRootVector:]) != sacnRootVectorData ||
+		be32(packet[sacnOffsetFrameVector:]) != sacnFramingVectorData ||
+		packet[sacnOffsetDMPVector] != sacnDMPVectorSetProp {
+		return 0, nil, false
+	}
+
+	//	Ignore preview data and stream termination packets
+	if packet[sacnOffsetOptions]&(sacnOptionsStreamTerm|sacnOptionsPreviewData) != 0 {
+		return 0, nil, false
+	}
+
+	//	Only handle null start code (dimmer) data
+	if packet[sacnOffsetStartCode] != 0 {
+		return 0, nil, false
+	}
+
+	universe := int(packet[sacnOffsetUniverse])<<8 | int(packet[sacnOffsetUniverse+1])
+
+	//	The property value count includes the start code
+	count := int(packet[sacnOffsetValueCount])<<8 | int(packet[sacnOffsetValueCount+1])
+	if count < 1 || count > 513 || len(packet) < sacnOffsetStartCode+count {
+		return 0, nil, false
+	}
+
+	return universe, packet[sacnOffsetStartCode+1 : sacnOffsetStartCode+count], true
+}
+
+// receiveSACN joins the multicast group for the requested universe and
+// applies received data to the input universe until the context is canceled
+func (bp *BackgroundProcess) receiveSACN(ctx context.Context, req InputRequest) error {
+	universe := req.Universe
+	if universe < 1 {
+		universe = 1
+	}
+
+	group := &net.UDPAddr{
+		IP:   net.IPv4(239, 255, byte(universe>>8), byte(universe&0xFF)),
+		Port: sacnPort,
+	}
+
+	conn, err := net.ListenMulticastUDP("udp4", nil, group)
+	if err != nil {
+		return fmt.Errorf("unable to join sACN multicast group %v: %v", group, err)
+	}
+
+	return readPackets(ctx, conn, func(packet []byte) {
+		u, values, ok := parseSACN(packet)
+		if ok && u == universe {
+			bp.Input.Update(InputSourceSACN, channelMap(values))
+		}
+	})
+}
+
+// be32 reads a big endian 32 bit value
+func be32(b []byte) uint32 {
+	return uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+}
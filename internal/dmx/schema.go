@@ -0,0 +1,17 @@
+package dmx
+
+import (
+	_ "embed"
+)
+
+// timelineSchema is the JSON Schema for a timeline document
+//
+//go:embed schema/timeline.schema.json
+var timelineSchema []byte
+
+// TimelineSchema is the JSON Schema (draft-07) for a timeline document, so
+// editors and CI can check timeline files before they're uploaded.  It
+// checks the shape of a timeline; ValidateTimeline checks the rest.
+func TimelineSchema() []byte {
+	return append([]byte{}, timelineSchema...)
+}
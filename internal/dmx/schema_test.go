@@ -0,0 +1,141 @@
+package dmx
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/danesparza/fxdmx/internal/color"
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+// jsonFields is the json name of every field in a struct
+func jsonFields(t reflect.Type) []string {
+	retval := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			retval = append(retval, name)
+		}
+	}
+	sort.Strings(retval)
+	return retval
+}
+
+func TestSchema_TimelineSchema_MatchesTimelineFields(t *testing.T) {
+
+	//	Arrange
+	schema := struct {
+		Properties  map[string]json.RawMessage `json:"properties"`
+		Definitions map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"definitions"`
+	}{}
+
+	types := map[string]reflect.Type{
+		"track":    reflect.TypeOf(data2.Track{}),
+		"frame":    reflect.TypeOf(data2.TimelineFrame{}),
+		"channel":  reflect.TypeOf(data2.ChannelValue{}),
+		"fixture":  reflect.TypeOf(data2.FixtureValue{}),
+		"effect":   reflect.TypeOf(data2.Effect{}),
+		"chase":    reflect.TypeOf(data2.Chase{}),
+		"look":     reflect.TypeOf(data2.ChaseStep{}),
+		"keyframe": reflect.TypeOf(data2.Keyframe{}),
+	}
+
+	//	Act
+	err := json.Unmarshal(TimelineSchema(), &schema)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("TimelineSchema - Should be valid JSON, but got: %s", err)
+	}
+
+	properties := func(fields map[string]json.RawMessage) []string {
+		retval := []string{}
+		for name := range fields {
+			retval = append(retval, name)
+		}
+		sort.Strings(retval)
+		return retval
+	}
+
+	if got, want := properties(schema.Properties), jsonFields(reflect.TypeOf(data2.Timeline{})); !reflect.DeepEqual(got, want) {
+		t.Errorf("TimelineSchema failed: Timeline properties should be %v but got %v", want, got)
+	}
+
+	for name, structType := range types {
+		definition, ok := schema.Definitions[name]
+		if !ok {
+			t.Errorf("TimelineSchema failed: Should define '%s'", name)
+			continue
+		}
+
+		if got, want := properties(definition.Properties), jsonFields(structType); !reflect.DeepEqual(got, want) {
+			t.Errorf("TimelineSchema failed: '%s' properties should be %v but got %v", name, want, got)
+		}
+	}
+}
+
+func TestSchema_TimelineSchema_ChoicesIgnoreCase(t *testing.T) {
+
+	//	Arrange
+	type choice struct {
+		AnyOf []struct {
+			Enum    []string `json:"enum"`
+			Pattern string   `json:"pattern"`
+		} `json:"anyOf"`
+	}
+
+	schema := struct {
+		Definitions map[string]struct {
+			Properties map[string]choice `json:"properties"`
+		} `json:"definitions"`
+	}{}
+
+	//	The validator lowercases these before checking them, so the schema
+	//	should accept any case too
+	fields := map[string]func(string) bool{
+		"frame.type":       func(string) bool { return true },
+		"frame.colorspace": color.ValidSpace,
+		"effect.shape":     validShape,
+		"chase.direction":  validDirection,
+	}
+
+	//	Act
+	err := json.Unmarshal(TimelineSchema(), &schema)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("TimelineSchema - Should be valid JSON, but got: %s", err)
+	}
+
+	for field, valid := range fields {
+		names := strings.Split(field, ".")
+		property := schema.Definitions[names[0]].Properties[names[1]]
+		if len(property.AnyOf) != 2 || len(property.AnyOf[0].Enum) == 0 || property.AnyOf[1].Pattern == "" {
+			t.Errorf("TimelineSchema failed: '%s' should be an enum or a case-insensitive pattern", field)
+			continue
+		}
+
+		pattern := regexp.MustCompile(property.AnyOf[1].Pattern)
+		for _, value := range property.AnyOf[0].Enum {
+			if !valid(value) {
+				t.Errorf("TimelineSchema failed: '%s' value '%s' isn't valid to the server", field, value)
+			}
+
+			if value == "" {
+				continue
+			}
+
+			for _, other := range []string{value, strings.ToUpper(value), strings.ToUpper(value[:1]) + value[1:]} {
+				if !pattern.MatchString(other) {
+					t.Errorf("TimelineSchema failed: '%s' should accept '%s'", field, other)
+				}
+			}
+		}
+	}
+}
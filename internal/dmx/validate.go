@@ -0,0 +1,328 @@
+package dmx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/danesparza/fxdmx/internal/color"
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+// ValidationError is a problem with a field in a timeline
+type ValidationError struct {
+	Field   string `json:"field"`   // The path to the field (like 'frames[3].channels[1].channel')
+	Message string `json:"message"` // What's wrong with it
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors is every problem found in a timeline
+type ValidationErrors []ValidationError
+
+func (e ValidationErrors) Error() string {
+	problems := make([]string, len(e))
+	for i, problem := range e {
+		problems[i] = problem.Error()
+	}
+	return strings.Join(problems, "; ")
+}
+
+// validator collects the problems found in a timeline
+type validator struct {
+	problems ValidationErrors
+}
+
+func (v *validator) add(field, format string, args ...interface{}) {
+	v.problems = append(v.problems, ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// ValidateTimeline checks everything in a timeline that can be checked
+// without the patch (fixture, group, preset and timeline names are checked
+// when the timeline plays).  It returns every problem it finds, or nil if the
+// timeline is valid.
+func ValidateTimeline(timeline data2.Timeline) ValidationErrors {
+	v := &validator{}
+
+	if len(timeline.Frames) < 1 && len(timeline.Tracks) < 1 {
+		v.add("frames", "must contain at least one item")
+	}
+
+	v.frames("frames", timeline.Frames)
+	for i, track := range timeline.Tracks {
+		path := fmt.Sprintf("tracks[%v].frames", i)
+		if len(track.Frames) < 1 {
+			v.add(path, "must contain at least one item")
+		}
+		v.frames(path, track.Frames)
+	}
+
+	return v.problems
+}
+
+// ValidateCueList checks the channels, fixture attributes and presets in
+// every cue the same way ValidateTimeline checks frames.  It returns every
+// problem it finds, or nil if the cues are valid.
+func ValidateCueList(cues []data2.Cue) ValidationErrors {
+	v := &validator{}
+
+	for i, cue := range cues {
+		path := fmt.Sprintf("cues[%v]", i)
+		v.channels(path+".channels", cue.Channels)
+		v.fixtures(path+".fixtures", cue.Fixtures)
+		v.presets(path+".presets", cue.Presets)
+	}
+
+	return v.problems
+}
+
+func (v *validator) frames(path string, frames []data2.TimelineFrame) {
+	for i, frame := range frames {
+		v.frame(fmt.Sprintf("%s[%v]", path, i), frame)
+	}
+}
+
+func (v *validator) frame(path string, frame data2.TimelineFrame) {
+	v.channels(path+".channels", frame.Channels)
+	v.fixtures(path+".fixtures", frame.Fixtures)
+	v.presets(path+".presets", frame.Presets)
+
+	if frame.SleepTime < 0 {
+		v.add(path+".sleeptime", "can't be negative")
+	}
+
+	if frame.FadeTime < 0 {
+		v.add(path+".fadetime", "can't be negative")
+	}
+
+	if !color.ValidSpace(strings.ToLower(frame.ColorSpace)) {
+		v.add(path+".colorspace", "'%s' must be one of rgb, hsv or perceptual", frame.ColorSpace)
+	}
+
+	hasLook := len(frame.Channels) > 0 || len(frame.Fixtures) > 0 || len(frame.Presets) > 0
+
+	switch frameType := strings.ToLower(frame.Type); frameType {
+	case "scene", "fade":
+		if !hasLook {
+			v.add(path+".channels", "%s frames need channels, fixtures or presets", frameType)
+		}
+
+	case "sleep":
+		if frame.SleepTime == 0 {
+			v.add(path+".sleeptime", "must be greater than 0 for sleep frames")
+		}
+
+	case "effect":
+		if len(frame.Channels) == 0 && len(frame.Fixtures) == 0 {
+			v.add(path+".channels", "effect frames need channels or fixtures to run on")
+		}
+		v.effect(path+".effect", frame.Effect)
+
+	case "chase":
+		if hasLook {
+			v.add(path+".channels", "chase frames set channels, fixtures and presets in their steps")
+		}
+		v.chase(path+".chase", frame.Chase)
+
+	case "keyframes":
+		if hasLook {
+			v.add(path+".channels", "keyframe frames set channels, fixtures and presets in their keyframes")
+		}
+		v.keyframes(path+".keyframes", frame.Keyframes)
+
+	case "timeline":
+		if hasLook {
+			v.add(path+".channels", "timeline frames can't set channels, fixtures or presets")
+		}
+		if strings.TrimSpace(frame.Timeline) == "" {
+			v.add(path+".timeline", "is required for timeline frames")
+		}
+
+	case "":
+		v.add(path+".type", "is required")
+
+	default:
+		v.add(path+".type", "'%s' must be one of scene, sleep, fade, effect, chase, keyframes or timeline", frame.Type)
+	}
+}
+
+func (v *validator) channels(path string, channels []data2.ChannelValue) {
+	for i, value := range channels {
+		field := fmt.Sprintf("%s[%v]", path, i)
+
+		if value.Channel < 1 || value.Channel > 512 {
+			v.add(field+".channel", "out of range 1-512")
+		}
+
+		if value.Fine != 0 && (value.Fine < 1 || value.Fine > 512) {
+			v.add(field+".fine", "out of range 1-512")
+		} else if value.Fine != 0 && value.Fine == value.Channel {
+			v.add(field+".fine", "must be a different channel")
+		}
+
+		if value.Fine != 0 && value.Value != 0 {
+			v.add(field+".value", "is ignored when fine is set (use value16)")
+		} else if value.Fine == 0 && value.Value16 != 0 {
+			v.add(field+".value16", "needs a fine channel (or use value)")
+		}
+	}
+}
+
+func (v *validator) fixtures(path string, fixtures []data2.FixtureValue) {
+	for i, fixtureValue := range fixtures {
+		field := fmt.Sprintf("%s[%v]", path, i)
+
+		hasFixture := strings.TrimSpace(fixtureValue.Fixture) != ""
+		hasGroup := strings.TrimSpace(fixtureValue.Group) != ""
+		switch {
+		case hasFixture && hasGroup:
+			v.add(field, "set either fixture or group, not both")
+		case !hasFixture && !hasGroup:
+			v.add(field+".fixture", "either fixture or group is required")
+		}
+
+		if len(fixtureValue.Spread) > 0 && !hasGroup {
+			v.add(field+".spread", "only applies to groups")
+		}
+
+		v.attributes(field+".attributes", fixtureValue.Attributes)
+		v.attributes(field+".spread", fixtureValue.Spread)
+
+		if fixtureValue.Offset < 0 {
+			v.add(field+".offset", "can't be negative")
+		}
+	}
+}
+
+// attributes checks fixture attribute levels and colors
+func (v *validator) attributes(path string, attributes map[string]interface{}) {
+	//	Check attributes in a stable order
+	names := make([]string, 0, len(attributes))
+	for name := range attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		field := path + "." + name
+
+		if strings.EqualFold(name, AttributeColor) {
+			text, ok := attributes[name].(string)
+			if !ok {
+				v.add(field, "must be a color string (like '#ff8800', 'hsv(30, 100%%, 100%%)' or '3200K')")
+			} else if _, err := color.Parse(text); err != nil {
+				v.add(field, "%v", err)
+			}
+			continue
+		}
+
+		if _, err := normalizedLevel(attributes[name]); err != nil {
+			v.add(field, "%v", err)
+		}
+	}
+}
+
+func (v *validator) presets(path string, presets []string) {
+	for i, name := range presets {
+		if strings.TrimSpace(name) == "" {
+			v.add(fmt.Sprintf("%s[%v]", path, i), "is required")
+		}
+	}
+}
+
+// look checks a chase step's or keyframe's channels, fixtures and presets
+func (v *validator) look(path string, channels []data2.ChannelValue, fixtures []data2.FixtureValue, presets []string) {
+	if len(channels) == 0 && len(fixtures) == 0 && len(presets) == 0 {
+		v.add(path, "needs channels, fixtures or presets")
+	}
+
+	v.channels(path+".channels", channels)
+	v.fixtures(path+".fixtures", fixtures)
+	v.presets(path+".presets", presets)
+}
+
+func (v *validator) effect(path string, settings *data2.Effect) {
+	if settings == nil {
+		v.add(path, "is required for effect frames")
+		return
+	}
+
+	shape := strings.ToLower(settings.Shape)
+	if !validShape(shape) {
+		v.add(path+".shape", "'%s' %s", settings.Shape, shapeChoices)
+	}
+
+	if settings.Rate < 0 {
+		v.add(path+".rate", "can't be negative")
+	}
+	if settings.BPM < 0 {
+		v.add(path+".bpm", "can't be negative")
+	}
+	if settings.Rate != 0 && settings.BPM != 0 {
+		v.add(path+".bpm", "set either rate or bpm, not both")
+	}
+	if settings.Rate == 0 && settings.BPM == 0 && validShape(shape) && naturalRates[shape] == 0 {
+		v.add(path+".rate", "rate or bpm is required")
+	}
+
+	if settings.Min < 0 || settings.Min > 1 {
+		v.add(path+".min", "out of range 0.0-1.0")
+	}
+	if settings.Max < 0 || settings.Max > 1 {
+		v.add(path+".max", "out of range 0.0-1.0")
+	}
+
+	if settings.Duration < 0 {
+		v.add(path+".duration", "can't be negative")
+	}
+}
+
+func (v *validator) chase(path string, settings *data2.Chase) {
+	if settings == nil {
+		v.add(path, "is required for chase frames")
+		return
+	}
+
+	if len(settings.Steps) < 1 {
+		v.add(path+".steps", "must contain at least one item")
+	}
+	for i, step := range settings.Steps {
+		v.look(fmt.Sprintf("%s.steps[%v]", path, i), step.Channels, step.Fixtures, step.Presets)
+	}
+
+	if settings.StepTime <= 0 {
+		v.add(path+".steptime", "must be greater than 0")
+	}
+
+	if settings.FadeRatio < 0 || settings.FadeRatio > 1 {
+		v.add(path+".faderatio", "out of range 0.0-1.0")
+	}
+
+	if !validDirection(strings.ToLower(settings.Direction)) {
+		v.add(path+".direction", "'%s' %s", settings.Direction, directionChoices)
+	}
+
+	if settings.Repeat < 0 {
+		v.add(path+".repeat", "can't be negative")
+	}
+}
+
+func (v *validator) keyframes(path string, keyframes []data2.Keyframe) {
+	if len(keyframes) < 1 {
+		v.add(path, "must contain at least one item for keyframe frames")
+	}
+
+	for i, keyframe := range keyframes {
+		field := fmt.Sprintf("%s[%v]", path, i)
+
+		if keyframe.Time < 0 {
+			v.add(field+".time", "can't be negative")
+		} else if i > 0 && keyframe.Time <= keyframes[i-1].Time {
+			v.add(field+".time", "must be after the keyframe before it (%v)", keyframes[i-1].Time)
+		}
+
+		v.look(field, keyframe.Channels, keyframe.Fixtures, keyframe.Presets)
+	}
+}
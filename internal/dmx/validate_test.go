@@ -0,0 +1,188 @@
+package dmx
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+func TestValidate_ValidateTimeline_Example_IsValid(t *testing.T) {
+
+	//	Arrange
+	contents, err := os.ReadFile("../../examples/cannon.json")
+	if err != nil {
+		t.Fatalf("Problem reading the example timeline: %s", err)
+	}
+
+	timeline := data2.Timeline{}
+	if err := json.Unmarshal(contents, &timeline); err != nil {
+		t.Fatalf("Problem decoding the example timeline: %s", err)
+	}
+
+	//	Act
+	problems := ValidateTimeline(timeline)
+
+	//	Assert
+	if problems != nil {
+		t.Errorf("ValidateTimeline - Should find no problems with the example, but got: %s", problems)
+	}
+}
+
+func TestValidate_ValidateTimeline_EveryFrameType_IsValid(t *testing.T) {
+
+	//	Arrange
+	channels := []data2.ChannelValue{{Channel: 1, Value: 255}, {Channel: 2, Fine: 3, Value16: 1000}}
+	timeline := data2.Timeline{
+		Frames: []data2.TimelineFrame{
+			{Type: "scene", Channels: channels},
+			{Type: "Fade", Fixtures: []data2.FixtureValue{{Group: "Pars", Attributes: map[string]interface{}{"dimmer": 1.0}, Spread: map[string]interface{}{"dimmer": 0.0}, Offset: 50}}, ColorSpace: "HSV"},
+			{Type: "sleep", SleepTime: 100},
+			{Type: "effect", Channels: channels, Effect: &data2.Effect{Shape: "candle"}},
+			{Type: "chase", Chase: &data2.Chase{Steps: []data2.ChaseStep{{Presets: []string{"Blackout"}}}, StepTime: 100, Direction: "bounce"}},
+			{Type: "keyframes", Keyframes: []data2.Keyframe{{Time: 0, Channels: channels}, {Time: 100, Channels: channels}}},
+			{Type: "timeline", Timeline: "intro", Parallel: true},
+		},
+		Tracks: []data2.Track{{Frames: []data2.TimelineFrame{{Type: "scene", Presets: []string{"Warm white"}}}}},
+	}
+
+	//	Act
+	problems := ValidateTimeline(timeline)
+
+	//	Assert
+	if problems != nil {
+		t.Errorf("ValidateTimeline - Should find no problems, but got: %s", problems)
+	}
+}
+
+func TestValidate_ValidateTimeline_Problems_ReturnsFieldPaths(t *testing.T) {
+
+	//	Arrange
+	timeline := data2.Timeline{
+		Frames: []data2.TimelineFrame{
+			{Type: "scene", Channels: []data2.ChannelValue{{Channel: 1}, {Channel: 513}, {Channel: 2, Fine: 3, Value: 10}, {Channel: 4, Value16: 500}}},
+			{Type: "sleep", SleepTime: -5},
+			{Type: "scene"},
+			{Type: "strobe"},
+			{Type: "fade", Channels: []data2.ChannelValue{{Channel: 0, Fine: 600}}, FadeTime: -1, ColorSpace: "cmyk"},
+			{Type: "effect", Fixtures: []data2.FixtureValue{{Fixture: "Left", Group: "Pars"}}, Effect: &data2.Effect{Shape: "wobble", Rate: 1, BPM: 60, Max: 2}},
+			{Type: "chase", Chase: &data2.Chase{Steps: []data2.ChaseStep{{}}, Direction: "sideways"}},
+			{Type: "keyframes", Keyframes: []data2.Keyframe{{Time: 100, Channels: []data2.ChannelValue{{Channel: 1}}}, {Time: 50, Presets: []string{" "}}}},
+			{Type: "timeline"},
+			{},
+		},
+		Tracks: []data2.Track{{}},
+	}
+
+	//	Act
+	problems := ValidateTimeline(timeline)
+
+	//	Assert
+	fields := []string{}
+	for _, problem := range problems {
+		fields = append(fields, problem.Field)
+	}
+
+	want := []string{
+		"frames[0].channels[1].channel",
+		"frames[0].channels[2].value",
+		"frames[0].channels[3].value16",
+		"frames[1].sleeptime",
+		"frames[2].channels",
+		"frames[3].type",
+		"frames[4].channels[0].channel",
+		"frames[4].channels[0].fine",
+		"frames[4].fadetime",
+		"frames[4].colorspace",
+		"frames[5].fixtures[0]",
+		"frames[5].effect.shape",
+		"frames[5].effect.bpm",
+		"frames[5].effect.max",
+		"frames[6].chase.steps[0]",
+		"frames[6].chase.steptime",
+		"frames[6].chase.direction",
+		"frames[7].keyframes[1].time",
+		"frames[7].keyframes[1].presets[0]",
+		"frames[8].timeline",
+		"frames[9].type",
+		"tracks[0].frames",
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("ValidateTimeline failed: Should find problems with %v but got %v", want, fields)
+	}
+
+	if problems[0].Error() != "frames[0].channels[1].channel: out of range 1-512" {
+		t.Errorf("ValidateTimeline failed: Should describe the problem with its field but got '%s'", problems[0].Error())
+	}
+}
+
+func TestValidate_ValidateTimeline_EffectRatesAndAttributes_ReturnsFieldPaths(t *testing.T) {
+
+	//	Arrange
+	timeline := data2.Timeline{
+		Frames: []data2.TimelineFrame{
+			{Type: "effect", Channels: []data2.ChannelValue{{Channel: 1}}, Effect: &data2.Effect{Shape: "sine"}},
+			{Type: "effect", Channels: []data2.ChannelValue{{Channel: 1}}, Effect: &data2.Effect{Shape: "Fire"}},
+			{Type: "scene", Fixtures: []data2.FixtureValue{
+				{Fixture: "Stage Left Par", Attributes: map[string]interface{}{"dimmer": 1.5, "color": "#ff8800", "red": "bright"}},
+				{Group: "Pars", Attributes: map[string]interface{}{"Color": "not a color"}, Spread: map[string]interface{}{"color": 7.0, "dimmer": -1.0}},
+			}},
+		},
+	}
+
+	//	Act
+	problems := ValidateTimeline(timeline)
+
+	//	Assert
+	fields := []string{}
+	for _, problem := range problems {
+		fields = append(fields, problem.Field)
+	}
+
+	want := []string{
+		"frames[0].effect.rate",
+		"frames[2].fixtures[0].attributes.dimmer",
+		"frames[2].fixtures[0].attributes.red",
+		"frames[2].fixtures[1].attributes.Color",
+		"frames[2].fixtures[1].spread.color",
+		"frames[2].fixtures[1].spread.dimmer",
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("ValidateTimeline failed: Should find problems with %v but got %v", want, fields)
+	}
+}
+
+func TestValidate_ValidateCueList_Problems_ReturnsFieldPaths(t *testing.T) {
+
+	//	Arrange
+	cues := []data2.Cue{
+		{Channels: []data2.ChannelValue{{Channel: 1, Value: 255}}},
+		{Channels: []data2.ChannelValue{{Channel: 0}, {Channel: 2, Fine: 600}}},
+		{Fixtures: []data2.FixtureValue{{Fixture: "Stage Left Par", Attributes: map[string]interface{}{"dimmer": 2.0}}}, Presets: []string{" "}},
+	}
+
+	//	Act
+	problems := ValidateCueList(cues)
+
+	//	Assert
+	fields := []string{}
+	for _, problem := range problems {
+		fields = append(fields, problem.Field)
+	}
+
+	want := []string{
+		"cues[1].channels[0].channel",
+		"cues[1].channels[1].fine",
+		"cues[2].fixtures[0].attributes.dimmer",
+		"cues[2].presets[0]",
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("ValidateCueList failed: Should find problems with %v but got %v", want, fields)
+	}
+
+	if problems := ValidateCueList(cues[:1]); problems != nil {
+		t.Errorf("ValidateCueList failed: Should find no problems with a valid cue but got %v", problems)
+	}
+}
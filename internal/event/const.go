@@ -28,6 +28,81 @@ const (
 	// ConfigUpdated event is when the system configuration has been updated (specifically the default usb device has been udpated)
 	ConfigUpdated = "Config updated"
 
+	// InputStarted event is when DMX input has started being received
+	InputStarted = "DMX input started"
+
+	// InputStopped event is when DMX input has stopped being received
+	InputStopped = "DMX input stopped"
+
+	// InputError event is when there was an error receiving DMX input
+	InputError = "DMX input error"
+
+	// RecordingStarted event is when DMX input has started being recorded
+	RecordingStarted = "Recording started"
+
+	// RecordingStopped event is when DMX input has stopped being recorded
+	RecordingStopped = "Recording stopped"
+
+	// RDMAddressUpdated event is when the DMX start address of an RDM device has been set
+	RDMAddressUpdated = "RDM address updated"
+
+	// ProfileCreated event is when a fixture profile has been created
+	ProfileCreated = "Fixture profile created"
+
+	// ProfileUpdated event is when a fixture profile has been updated
+	ProfileUpdated = "Fixture profile updated"
+
+	// ProfileDeleted event is when a fixture profile has been removed
+	ProfileDeleted = "Fixture profile deleted"
+
+	// FixtureCreated event is when a fixture has been patched
+	FixtureCreated = "Fixture created"
+
+	// FixtureUpdated event is when a fixture has been updated or re-patched
+	FixtureUpdated = "Fixture updated"
+
+	// FixtureDeleted event is when a fixture has been removed from the patch
+	FixtureDeleted = "Fixture deleted"
+
+	// GroupCreated event is when a fixture group has been created
+	GroupCreated = "Fixture group created"
+
+	// GroupUpdated event is when a fixture group has been updated
+	GroupUpdated = "Fixture group updated"
+
+	// GroupDeleted event is when a fixture group has been removed
+	GroupDeleted = "Fixture group deleted"
+
+	// PresetCreated event is when a preset has been created
+	PresetCreated = "Preset created"
+
+	// PresetUpdated event is when a preset has been updated
+	PresetUpdated = "Preset updated"
+
+	// PresetDeleted event is when a preset has been removed
+	PresetDeleted = "Preset deleted"
+
+	// CueListCreated event is when a cue list has been created
+	CueListCreated = "Cue list created"
+
+	// CueListUpdated event is when a cue list has been updated
+	CueListUpdated = "Cue list updated"
+
+	// CueListDeleted event is when a cue list has been removed
+	CueListDeleted = "Cue list deleted"
+
+	// CueListStarted event is when a cue list has started running
+	CueListStarted = "Cue list started"
+
+	// CueListCommand event is when a cue list has been sent a command (GO, BACK or GOTO)
+	CueListCommand = "Cue list command"
+
+	// CueListReleased event is when a cue list has stopped running
+	CueListReleased = "Cue list released"
+
+	// CueListError event is when there was an error running a cue list
+	CueListError = "Cue list error"
+
 	// SystemShutdown event is when the system is shutting down
 	SystemShutdown = "System Shutdown"
 )
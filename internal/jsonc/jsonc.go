@@ -0,0 +1,74 @@
+package jsonc
+
+// Standardize turns JSON with comments (// line comments and /* block */
+// comments) and trailing commas into standard JSON that encoding/json can
+// decode.  Comments and trailing commas are replaced with spaces (keeping
+// newlines), so errors still point at the right line and offset.
+func Standardize(in []byte) []byte {
+	out := make([]byte, len(in))
+	copy(out, in)
+
+	comma := -1    // Where the last comma after a value is, if only whitespace (or comments) have followed it
+	value := false // Set if the last thing outside a comment ended a value
+
+	for i := 0; i < len(out); i++ {
+		switch c := out[i]; {
+
+		case c == '"':
+			//	Skip over the string (and any escaped characters in it)
+			comma, value = -1, true
+			for i++; i < len(out) && out[i] != '"'; i++ {
+				if out[i] == '\\' {
+					i++
+				}
+			}
+
+		case c == '/' && i+1 < len(out) && out[i+1] == '/':
+			for ; i < len(out) && out[i] != '\n'; i++ {
+				out[i] = ' '
+			}
+
+		case c == '/' && i+1 < len(out) && out[i+1] == '*':
+			out[i], out[i+1] = ' ', ' '
+			for i += 2; i < len(out) && !(out[i] == '*' && i+1 < len(out) && out[i+1] == '/'); i++ {
+				blank(out, i)
+			}
+			if i < len(out) {
+				out[i], out[i+1] = ' ', ' '
+				i++
+			}
+
+		case c == ',':
+			//	Only a comma after a value can be a trailing comma
+			comma = -1
+			if value {
+				comma = i
+			}
+			value = false
+
+		case c == '}' || c == ']':
+			if comma >= 0 {
+				out[comma] = ' '
+			}
+			comma, value = -1, true
+
+		case c == '{' || c == '[' || c == ':':
+			comma, value = -1, false
+
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+
+		default:
+			//	Numbers and literals (like true)
+			comma, value = -1, true
+		}
+	}
+
+	return out
+}
+
+// blank replaces a character in a comment with a space (unless it's a newline)
+func blank(out []byte, i int) {
+	if out[i] != '\n' && out[i] != '\r' {
+		out[i] = ' '
+	}
+}
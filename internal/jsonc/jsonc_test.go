@@ -0,0 +1,115 @@
+package jsonc_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/danesparza/fxdmx/internal/data"
+	"github.com/danesparza/fxdmx/internal/jsonc"
+)
+
+func TestJSONC_Standardize_CommentsAndTrailingCommas_Decodes(t *testing.T) {
+
+	//	Arrange
+	in := []byte(`{
+	// The timeline name
+	"name": "Show // not a comment", /* a block
+	comment */
+	"frames": [
+		{"type": "sleep", "sleeptime": 10, /* trailing */ },
+		{"type": "scene", "presets": ["Say \"hi\", /* still not a comment */",],},
+	],
+}`)
+
+	//	Act
+	timeline := data.Timeline{}
+	err := json.Unmarshal(jsonc.Standardize(in), &timeline)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("Standardize - Should decode without error, but got: %s", err)
+	}
+
+	if timeline.Name != "Show // not a comment" {
+		t.Errorf("Standardize failed: Should leave strings alone but got name '%s'", timeline.Name)
+	}
+
+	if len(timeline.Frames) != 2 || timeline.Frames[0].SleepTime != 10 {
+		t.Fatalf("Standardize failed: Should decode 2 frames but got %+v", timeline.Frames)
+	}
+
+	if want := `Say "hi", /* still not a comment */`; timeline.Frames[1].Presets[0] != want {
+		t.Errorf("Standardize failed: Should decode preset '%s' but got '%s'", want, timeline.Frames[1].Presets[0])
+	}
+}
+
+func TestJSONC_Standardize_CommasWithoutValues_StillFail(t *testing.T) {
+
+	//	Arrange
+	tests := []string{
+		`[,]`,
+		`{,}`,
+		`{"a": 1,,}`,
+		`[1, /* nothing */ , ]`,
+		`{"a": ,}`,
+	}
+
+	for _, test := range tests {
+		//	Act
+		var decoded interface{}
+		err := json.Unmarshal(jsonc.Standardize([]byte(test)), &decoded)
+
+		//	Assert
+		if err == nil {
+			t.Errorf("Standardize - Should still fail to decode %s, but got %v", test, decoded)
+		}
+	}
+}
+
+func TestJSONC_Standardize_KeepsLineOffsets(t *testing.T) {
+
+	//	Arrange
+	in := []byte("{\n/* one\ntwo */\n\"name\": x\n}")
+
+	//	Act
+	out := jsonc.Standardize(in)
+	err := json.Unmarshal(out, &data.Timeline{})
+
+	//	Assert
+	if len(out) != len(in) {
+		t.Errorf("Standardize failed: Should keep the length (%v) but got %v", len(in), len(out))
+	}
+
+	syntaxError, ok := err.(*json.SyntaxError)
+	if !ok {
+		t.Fatalf("Standardize - Should still fail on the missing value, but got: %v", err)
+	}
+
+	if want := int64(bytes.IndexByte(in, 'x') + 1); syntaxError.Offset != want {
+		t.Errorf("Standardize failed: Should report the error at offset %v but got %v", want, syntaxError.Offset)
+	}
+}
+
+func TestJSONC_Standardize_Example_Decodes(t *testing.T) {
+
+	//	Arrange
+	contents, err := os.ReadFile("../../examples/test-timeline.jsonc")
+	if err != nil {
+		t.Fatalf("Problem reading the example timeline: %s", err)
+	}
+
+	//	Act
+	timeline := data.Timeline{}
+	err = json.Unmarshal(jsonc.Standardize(contents), &timeline)
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("Standardize - Should decode the example without error, but got: %s", err)
+	}
+
+	if len(timeline.Frames) != 5 {
+		t.Errorf("Standardize failed: Should decode the example's 5 frames but got %v", len(timeline.Frames))
+	}
+}
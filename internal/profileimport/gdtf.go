@@ -0,0 +1,358 @@
+package profileimport
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+// maxGDTFDescriptionSize is the largest (uncompressed) description.xml we'll
+// read.  Upload limits only cap the compressed archive
+const maxGDTFDescriptionSize = 16 << 20
+
+// gdtfDescription is the description.xml in a GDTF (General Device Type Format) archive
+// (see https://gdtf-share.com/help/en/help/gdtf/spec/gdtf-spec.html)
+type gdtfDescription struct {
+	XMLName     xml.Name        `xml:"GDTF"`
+	FixtureType gdtfFixtureType `xml:"FixtureType"`
+}
+
+type gdtfFixtureType struct {
+	Name         string        `xml:"Name,attr"`
+	LongName     string        `xml:"LongName,attr"`
+	Manufacturer string        `xml:"Manufacturer,attr"`
+	DMXModes     []gdtfDMXMode `xml:"DMXModes>DMXMode"`
+}
+
+type gdtfDMXMode struct {
+	Name        string           `xml:"Name,attr"`
+	DMXChannels []gdtfDMXChannel `xml:"DMXChannels>DMXChannel"`
+}
+
+type gdtfDMXChannel struct {
+	DMXBreak        string               `xml:"DMXBreak,attr"`
+	Offset          string               `xml:"Offset,attr"`
+	Default         string               `xml:"Default,attr"`
+	Geometry        string               `xml:"Geometry,attr"`
+	InitialFunction string               `xml:"InitialFunction,attr"`
+	LogicalChannels []gdtfLogicalChannel `xml:"LogicalChannel"`
+}
+
+type gdtfLogicalChannel struct {
+	Attribute        string                `xml:"Attribute,attr"`
+	ChannelFunctions []gdtfChannelFunction `xml:"ChannelFunction"`
+}
+
+type gdtfChannelFunction struct {
+	Name         string `xml:"Name,attr"`
+	Attribute    string `xml:"Attribute,attr"`
+	DMXFrom      string `xml:"DMXFrom,attr"`
+	Default      string `xml:"Default,attr"`
+	PhysicalFrom string `xml:"PhysicalFrom,attr"`
+	PhysicalTo   string `xml:"PhysicalTo,attr"`
+}
+
+// gdtfColors maps the color part of GDTF ColorAdd_/ColorRGB_/ColorSub_ attributes to fixture attributes
+var gdtfColors = map[string]string{
+	"r":       data2.AttributeRed,
+	"red":     data2.AttributeRed,
+	"g":       data2.AttributeGreen,
+	"green":   data2.AttributeGreen,
+	"b":       data2.AttributeBlue,
+	"blue":    data2.AttributeBlue,
+	"w":       data2.AttributeWhite,
+	"white":   data2.AttributeWhite,
+	"ww":      data2.AttributeWhite,
+	"cw":      data2.AttributeWhite,
+	"a":       data2.AttributeAmber,
+	"amber":   data2.AttributeAmber,
+	"uv":      data2.AttributeUV,
+	"c":       data2.AttributeCyan,
+	"cyan":    data2.AttributeCyan,
+	"m":       data2.AttributeMagenta,
+	"magenta": data2.AttributeMagenta,
+	"y":       data2.AttributeYellow,
+	"yellow":  data2.AttributeYellow,
+}
+
+// gdtfAttributes maps GDTF attribute name patterns to fixture attributes (first match wins)
+var gdtfAttributes = []struct {
+	pattern   *regexp.Regexp
+	attribute string
+}{
+	{regexp.MustCompile(`^Dimmer$`), data2.AttributeDimmer},
+	{regexp.MustCompile(`^Pan(Rotate)?$`), data2.AttributePan},
+	{regexp.MustCompile(`^Tilt(Rotate)?$`), data2.AttributeTilt},
+	{regexp.MustCompile(`^(CTO|CTC|CTB)$`), data2.AttributeColorTemp},
+	{regexp.MustCompile(`^Color\d+$`), data2.AttributeColorWheel},
+	{regexp.MustCompile(`^Gobo\d+(Pos|PosRotate|WheelSpin|SelectSpin)$`), data2.AttributeGoboRotate},
+	{regexp.MustCompile(`^Gobo\d+`), data2.AttributeGobo},
+	{regexp.MustCompile(`^Prism\d*`), data2.AttributePrism},
+	{regexp.MustCompile(`^Focus\d*`), data2.AttributeFocus},
+	{regexp.MustCompile(`^Zoom`), data2.AttributeZoom},
+	{regexp.MustCompile(`^Iris`), data2.AttributeIris},
+	{regexp.MustCompile(`^Frost\d*`), data2.AttributeFrost},
+	{regexp.MustCompile(`^(Shutter\d+Strobe|Strobe)`), data2.AttributeStrobe},
+	{regexp.MustCompile(`^Shutter\d*`), data2.AttributeShutter},
+	{regexp.MustCompile(`^(Fog|Haze)`), data2.AttributeFog},
+	{regexp.MustCompile(`^Fan`), data2.AttributeFan},
+	{regexp.MustCompile(`^Effects?\d*`), data2.AttributeEffect},
+	{regexp.MustCompile(`Speed|MSpeed`), data2.AttributeSpeed},
+	{regexp.MustCompile(`^(Control\d*|Fixture_|LampControl|Reset|DisplayIntensity|Function)`), data2.AttributeControl},
+}
+
+// ParseGDTF converts a GDTF fixture archive (a zip file with a
+// description.xml) into a fixture profile
+func ParseGDTF(r io.ReaderAt, size int64) (data2.FixtureProfile, error) {
+	retval := data2.FixtureProfile{}
+
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return retval, fmt.Errorf("problem reading the GDTF archive: %v", err)
+	}
+
+	description, err := archive.Open("description.xml")
+	if err != nil {
+		return retval, fmt.Errorf("the GDTF archive doesn't have a description.xml")
+	}
+	defer description.Close()
+
+	//	Make sure it's a sensible size before we decompress it
+	info, err := description.Stat()
+	if err != nil {
+		return retval, fmt.Errorf("problem reading the GDTF description: %v", err)
+	}
+	if info.Size() > maxGDTFDescriptionSize {
+		return retval, fmt.Errorf("the GDTF description is too large (%v bytes, the most is %v)", info.Size(), maxGDTFDescriptionSize)
+	}
+
+	return parseGDTFDescription(io.LimitReader(description, maxGDTFDescriptionSize))
+}
+
+// parseGDTFDescription converts a GDTF description.xml into a fixture profile
+func parseGDTFDescription(r io.Reader) (data2.FixtureProfile, error) {
+	retval := data2.FixtureProfile{}
+
+	gdtf := gdtfDescription{}
+	if err := xml.NewDecoder(r).Decode(&gdtf); err != nil {
+		return retval, fmt.Errorf("problem decoding the GDTF description: %v", err)
+	}
+
+	fixture := gdtf.FixtureType
+	retval.Manufacturer = fixture.Manufacturer
+	retval.Model = fixture.LongName
+	if strings.TrimSpace(retval.Model) == "" {
+		retval.Model = fixture.Name
+	}
+
+	if strings.TrimSpace(retval.Model) == "" {
+		return retval, fmt.Errorf("the GDTF fixture type doesn't have a name")
+	}
+
+	if len(fixture.DMXModes) == 0 {
+		return retval, fmt.Errorf("the GDTF fixture type '%s' doesn't have any DMX modes", retval.Model)
+	}
+
+	for _, mode := range fixture.DMXModes {
+		newMode, err := gdtfMode(mode)
+		if err != nil {
+			return retval, err
+		}
+		retval.Modes = append(retval.Modes, newMode)
+	}
+
+	return retval, nil
+}
+
+// gdtfMode converts a GDTF DMX mode.  Each DMX channel's offsets are its
+// coarse channel followed by its fine (and ultra fine) channels.
+func gdtfMode(mode gdtfDMXMode) (data2.FixtureMode, error) {
+	retval := data2.FixtureMode{Name: mode.Name}
+
+	channels := map[int]data2.FixtureChannel{}
+	names := map[string]bool{}
+	footprint := 0
+
+	for _, dmxChannel := range mode.DMXChannels {
+		//	Virtual channels don't have an offset
+		if strings.TrimSpace(dmxChannel.Offset) == "" || dmxChannel.Offset == "None" {
+			continue
+		}
+
+		if dmxChannel.DMXBreak != "" && dmxChannel.DMXBreak != "1" && dmxChannel.DMXBreak != "Overwrite" {
+			return retval, fmt.Errorf("mode '%s' uses more than one DMX break, which isn't supported", mode.Name)
+		}
+
+		if len(dmxChannel.LogicalChannels) == 0 {
+			return retval, fmt.Errorf("mode '%s' has a DMX channel at offset %s without a logical channel", mode.Name, dmxChannel.Offset)
+		}
+
+		gdtfAttribute := dmxChannel.LogicalChannels[0].Attribute
+		attribute := gdtfFixtureAttribute(gdtfAttribute)
+
+		//	Name the channel after its attribute (and its geometry if the attribute is used more than once)
+		name := gdtfAttribute
+		if names[name] && dmxChannel.Geometry != "" {
+			name = dmxChannel.Geometry + " " + gdtfAttribute
+		}
+		names[name] = true
+
+		for i, part := range strings.Split(dmxChannel.Offset, ",") {
+			offset, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil || offset < 1 || offset > 512 {
+				return retval, fmt.Errorf("mode '%s' has an invalid DMX channel offset '%s'", mode.Name, dmxChannel.Offset)
+			}
+			if _, exists := channels[offset]; exists {
+				return retval, fmt.Errorf("mode '%s' uses channel %v more than once", mode.Name, offset)
+			}
+
+			channel := data2.FixtureChannel{Name: name, Attribute: attribute}
+			switch i {
+			case 0:
+				channel.Default = gdtfDefault(dmxChannel)
+				channel.Functions = gdtfFunctions(dmxChannel)
+			case 1:
+				channel.Name, channel.Fine = name+" fine", true
+			default:
+				channel.Name, channel.Fine = name+" ultra", true
+			}
+
+			channels[offset] = channel
+			if offset > footprint {
+				footprint = offset
+			}
+		}
+	}
+
+	if footprint == 0 {
+		return retval, fmt.Errorf("mode '%s' doesn't have any DMX channels", mode.Name)
+	}
+
+	//	Lay the channels out in order (filling any gaps)
+	for offset := 1; offset <= footprint; offset++ {
+		channel, exists := channels[offset]
+		if !exists {
+			channel = data2.FixtureChannel{Name: "Unused", Attribute: data2.AttributeGeneric}
+		}
+		retval.Channels = append(retval.Channels, channel)
+	}
+
+	return retval, nil
+}
+
+// gdtfFixtureAttribute maps a GDTF attribute name to a fixture attribute
+func gdtfFixtureAttribute(name string) string {
+	for _, prefix := range []string{"ColorAdd_", "ColorRGB_", "ColorSub_"} {
+		if strings.HasPrefix(name, prefix) {
+			if attribute, found := gdtfColors[strings.ToLower(strings.TrimPrefix(name, prefix))]; found {
+				return attribute
+			}
+			return data2.AttributeGeneric
+		}
+	}
+
+	for _, mapping := range gdtfAttributes {
+		if mapping.pattern.MatchString(name) {
+			return mapping.attribute
+		}
+	}
+
+	return data2.AttributeGeneric
+}
+
+// gdtfFunctions converts the channel functions of a DMX channel to 8 bit DMX
+// ranges with their physical values
+func gdtfFunctions(dmxChannel gdtfDMXChannel) []data2.FixtureChannelFunction {
+	type function struct {
+		gdtf gdtfChannelFunction
+		from byte
+	}
+
+	//	Channel functions from every logical channel, in DMX order
+	functions := []function{}
+	for _, logical := range dmxChannel.LogicalChannels {
+		for _, channelFunction := range logical.ChannelFunctions {
+			from, _ := gdtfDMXValue(channelFunction.DMXFrom)
+			functions = append(functions, function{gdtf: channelFunction, from: from})
+		}
+	}
+	sort.SliceStable(functions, func(i, j int) bool { return functions[i].from < functions[j].from })
+
+	retval := []data2.FixtureChannelFunction{}
+	for i, item := range functions {
+		name := item.gdtf.Name
+		if strings.TrimSpace(name) == "" {
+			name = item.gdtf.Attribute
+		}
+
+		newFunction := data2.FixtureChannelFunction{Name: name, From: item.from, To: 255}
+		if i+1 < len(functions) && functions[i+1].from > item.from {
+			newFunction.To = functions[i+1].from - 1
+		}
+
+		//	Functions that share a starting value (like raw DMX and a
+		//	physical function on the same range) only need to be listed once
+		if i+1 < len(functions) && functions[i+1].from == item.from {
+			continue
+		}
+
+		newFunction.PhysicalFrom, _ = strconv.ParseFloat(item.gdtf.PhysicalFrom, 64)
+		newFunction.PhysicalTo, _ = strconv.ParseFloat(item.gdtf.PhysicalTo, 64)
+
+		retval = append(retval, newFunction)
+	}
+
+	return retval
+}
+
+// gdtfDefault finds the default (coarse) value of a DMX channel: from its
+// initial channel function if it has one, otherwise from the channel itself
+func gdtfDefault(dmxChannel gdtfDMXChannel) byte {
+	for _, logical := range dmxChannel.LogicalChannels {
+		for _, channelFunction := range logical.ChannelFunctions {
+			if channelFunction.Default != "" && strings.HasSuffix(dmxChannel.InitialFunction, "."+channelFunction.Name) {
+				value, _ := gdtfDMXValue(channelFunction.Default)
+				return value
+			}
+		}
+	}
+
+	value, _ := gdtfDMXValue(dmxChannel.Default)
+	return value
+}
+
+// gdtfDMXValue converts a GDTF DMX value (like '32768/2' -- a value and its
+// byte count) to an 8 bit value
+func gdtfDMXValue(value string) (byte, error) {
+	if strings.TrimSpace(value) == "" || value == "None" {
+		return 0, nil
+	}
+
+	parts := strings.SplitN(value, "/", 2)
+	number, err := strconv.ParseUint(strings.TrimSpace(parts[0]), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' isn't a valid DMX value", value)
+	}
+
+	bytes := uint64(1)
+	if len(parts) == 2 {
+		bytes, err = strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 8)
+		if err != nil || bytes < 1 || bytes > 4 {
+			return 0, fmt.Errorf("'%s' isn't a valid DMX value", value)
+		}
+	}
+
+	number >>= 8 * (bytes - 1)
+	if number > 255 {
+		number = 255
+	}
+
+	return byte(number), nil
+}
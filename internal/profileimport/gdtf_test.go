@@ -0,0 +1,143 @@
+package profileimport
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+const testGDTFDescription = `<?xml version="1.0" encoding="UTF-8" standalone="no" ?>
+<GDTF DataVersion="1.1">
+  <FixtureType Name="Spot 300" ShortName="Spot300" LongName="Spot 300 LED" Manufacturer="Unit test lighting" FixtureTypeID="00000000-0000-0000-0000-000000000001">
+    <DMXModes>
+      <DMXMode Name="Standard" Geometry="Base">
+        <DMXChannels>
+          <DMXChannel DMXBreak="1" Offset="1,2" Highlight="None" Geometry="Yoke" InitialFunction="Yoke_Pan.Pan.Pan 1">
+            <LogicalChannel Attribute="Pan" Snap="No" Master="None">
+              <ChannelFunction Name="Pan 1" Attribute="Pan" DMXFrom="0/2" Default="32768/2" PhysicalFrom="-270" PhysicalTo="270"/>
+            </LogicalChannel>
+          </DMXChannel>
+          <DMXChannel DMXBreak="1" Offset="3" Highlight="None" Geometry="Head" InitialFunction="Head_Dimmer.Dimmer.Dimmer 1">
+            <LogicalChannel Attribute="Dimmer" Snap="No" Master="Grand">
+              <ChannelFunction Name="Dimmer 1" Attribute="Dimmer" DMXFrom="0/1" Default="0/1" PhysicalFrom="0" PhysicalTo="1"/>
+            </LogicalChannel>
+          </DMXChannel>
+          <DMXChannel DMXBreak="1" Offset="5" Highlight="None" Geometry="Head" InitialFunction="Head_Shutter1.Shutter1.Open">
+            <LogicalChannel Attribute="Shutter1" Snap="No" Master="None">
+              <ChannelFunction Name="Open" Attribute="Shutter1" DMXFrom="0/1" Default="0/1"/>
+              <ChannelFunction Name="Strobe" Attribute="Shutter1Strobe" DMXFrom="32/1" Default="32/1" PhysicalFrom="1" PhysicalTo="20"/>
+            </LogicalChannel>
+          </DMXChannel>
+          <DMXChannel DMXBreak="1" Offset="None" Geometry="Head">
+            <LogicalChannel Attribute="ColorAdd_R"/>
+          </DMXChannel>
+        </DMXChannels>
+      </DMXMode>
+    </DMXModes>
+  </FixtureType>
+</GDTF>`
+
+func getTestGDTFArchive(t *testing.T, files map[string]string) *bytes.Reader {
+	buffer := bytes.Buffer{}
+	archive := zip.NewWriter(&buffer)
+	for name, contents := range files {
+		file, err := archive.Create(name)
+		if err != nil {
+			t.Fatalf("Problem creating the test GDTF archive: %s", err)
+		}
+		file.Write([]byte(contents))
+	}
+	archive.Close()
+
+	return bytes.NewReader(buffer.Bytes())
+}
+
+func TestGDTF_ParseGDTF_ValidArchive_Successful(t *testing.T) {
+
+	//	Arrange
+	archive := getTestGDTFArchive(t, map[string]string{"description.xml": testGDTFDescription})
+
+	//	Act
+	profile, err := ParseGDTF(archive, archive.Size())
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("ParseGDTF - Should parse without error, but got: %s", err)
+	}
+
+	if profile.Manufacturer != "Unit test lighting" || profile.Model != "Spot 300 LED" || len(profile.Modes) != 1 {
+		t.Fatalf("ParseGDTF failed: Should get the manufacturer, model and mode but got: %+v", profile)
+	}
+
+	channels := profile.Modes[0].Channels
+	if len(channels) != 5 {
+		t.Fatalf("ParseGDTF failed: Should get 5 channels (including the gap) but got: %+v", channels)
+	}
+
+	if channels[0].Attribute != data2.AttributePan || channels[0].Fine || channels[0].Default != 128 {
+		t.Errorf("ParseGDTF failed: Should get the coarse pan channel but got: %+v", channels[0])
+	}
+
+	if channels[1].Attribute != data2.AttributePan || !channels[1].Fine {
+		t.Errorf("ParseGDTF failed: Should get the fine pan channel but got: %+v", channels[1])
+	}
+
+	if len(channels[0].Functions) != 1 || channels[0].Functions[0].PhysicalFrom != -270 || channels[0].Functions[0].PhysicalTo != 270 {
+		t.Errorf("ParseGDTF failed: Should get the pan physical range but got: %+v", channels[0].Functions)
+	}
+
+	if channels[2].Attribute != data2.AttributeDimmer || channels[3].Attribute != data2.AttributeGeneric || channels[4].Attribute != data2.AttributeShutter {
+		t.Errorf("ParseGDTF failed: Should get the dimmer, unused and shutter channels but got: %+v", channels[2:])
+	}
+
+	functions := channels[4].Functions
+	if len(functions) != 2 || functions[0].To != 31 || functions[1].From != 32 || functions[1].To != 255 {
+		t.Errorf("ParseGDTF failed: Should get the shutter function ranges but got: %+v", functions)
+	}
+}
+
+func TestGDTF_ParseGDTF_NoDescription_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	archive := getTestGDTFArchive(t, map[string]string{"thumbnail.png": "not really a png"})
+
+	//	Act
+	_, err := ParseGDTF(archive, archive.Size())
+
+	//	Assert
+	if err == nil {
+		t.Errorf("ParseGDTF - Should return error for an archive without a description.xml, but got none")
+	}
+}
+
+func TestGDTF_ParseGDTF_NotAZip_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	archive := bytes.NewReader([]byte(testGDTFDescription))
+
+	//	Act
+	_, err := ParseGDTF(archive, archive.Size())
+
+	//	Assert
+	if err == nil {
+		t.Errorf("ParseGDTF - Should return error for a file that isn't a zip archive, but got none")
+	}
+}
+
+func TestGDTF_ParseGDTF_HugeDescription_ReturnsError(t *testing.T) {
+
+	//	Arrange (a description that compresses to almost nothing)
+	padding := strings.Repeat(" ", maxGDTFDescriptionSize)
+	archive := getTestGDTFArchive(t, map[string]string{"description.xml": padding + testGDTFDescription})
+
+	//	Act
+	_, err := ParseGDTF(archive, archive.Size())
+
+	//	Assert
+	if err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Errorf("ParseGDTF - Should return error for a description that's too large, but got: %v", err)
+	}
+}
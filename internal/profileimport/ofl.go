@@ -0,0 +1,264 @@
+package profileimport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+// oflFixture is an Open Fixture Library fixture definition
+// (see https://github.com/OpenLightingProject/open-fixture-library/blob/master/docs/fixture-format.md)
+type oflFixture struct {
+	Name              string                `json:"name"`
+	ShortName         string                `json:"shortName"`
+	Manufacturer      string                `json:"manufacturer"` // Not part of the fixture file, but some exports include it
+	AvailableChannels map[string]oflChannel `json:"availableChannels"`
+	Modes             []oflMode             `json:"modes"`
+}
+
+type oflChannel struct {
+	Name               string          `json:"name"`
+	FineChannelAliases []string        `json:"fineChannelAliases"`
+	DefaultValue       interface{}     `json:"defaultValue"`
+	Capability         *oflCapability  `json:"capability"`
+	Capabilities       []oflCapability `json:"capabilities"`
+}
+
+type oflCapability struct {
+	Type           string            `json:"type"`
+	Color          string            `json:"color"`
+	Wheel          string            `json:"wheel"`
+	ShutterEffect  string            `json:"shutterEffect"`
+	SwitchChannels map[string]string `json:"switchChannels"`
+}
+
+type oflMode struct {
+	Name      string            `json:"name"`
+	ShortName string            `json:"shortName"`
+	Channels  []json.RawMessage `json:"channels"`
+}
+
+// oflColors maps OFL ColorIntensity colors to fixture attributes
+var oflColors = map[string]string{
+	"red":        data2.AttributeRed,
+	"green":      data2.AttributeGreen,
+	"blue":       data2.AttributeBlue,
+	"white":      data2.AttributeWhite,
+	"warm white": data2.AttributeWhite,
+	"cold white": data2.AttributeWhite,
+	"amber":      data2.AttributeAmber,
+	"uv":         data2.AttributeUV,
+	"cyan":       data2.AttributeCyan,
+	"magenta":    data2.AttributeMagenta,
+	"yellow":     data2.AttributeYellow,
+}
+
+// oflTypes maps OFL capability types to fixture attributes
+var oflTypes = map[string]string{
+	"Intensity":        data2.AttributeDimmer,
+	"ColorPreset":      data2.AttributeColorWheel,
+	"ColorTemperature": data2.AttributeColorTemp,
+	"Pan":              data2.AttributePan,
+	"PanContinuous":    data2.AttributePan,
+	"Tilt":             data2.AttributeTilt,
+	"TiltContinuous":   data2.AttributeTilt,
+	"PanTiltSpeed":     data2.AttributeSpeed,
+	"Speed":            data2.AttributeSpeed,
+	"EffectSpeed":      data2.AttributeSpeed,
+	"ShutterStrobe":    data2.AttributeStrobe,
+	"StrobeSpeed":      data2.AttributeStrobe,
+	"StrobeDuration":   data2.AttributeStrobe,
+	"Prism":            data2.AttributePrism,
+	"PrismRotation":    data2.AttributePrism,
+	"Focus":            data2.AttributeFocus,
+	"Zoom":             data2.AttributeZoom,
+	"Iris":             data2.AttributeIris,
+	"IrisEffect":       data2.AttributeIris,
+	"Frost":            data2.AttributeFrost,
+	"FrostEffect":      data2.AttributeFrost,
+	"Fog":              data2.AttributeFog,
+	"FogOutput":        data2.AttributeFog,
+	"FogType":          data2.AttributeFog,
+	"Effect":           data2.AttributeEffect,
+	"EffectDuration":   data2.AttributeEffect,
+	"EffectParameter":  data2.AttributeEffect,
+	"SoundSensitivity": data2.AttributeEffect,
+	"Maintenance":      data2.AttributeControl,
+	"BeamAngle":        data2.AttributeZoom,
+	"Rotation":         data2.AttributeGeneric,
+	"Generic":          data2.AttributeGeneric,
+	"NoFunction":       data2.AttributeGeneric,
+}
+
+// ParseOFL converts an Open Fixture Library JSON fixture definition into a
+// fixture profile.  The fixture file doesn't include the manufacturer, so it
+// can be passed in (it's only used if the file doesn't have one).
+func ParseOFL(r io.Reader, manufacturer string) (data2.FixtureProfile, error) {
+	retval := data2.FixtureProfile{}
+
+	fixture := oflFixture{}
+	if err := json.NewDecoder(r).Decode(&fixture); err != nil {
+		return retval, fmt.Errorf("problem decoding the OFL fixture: %v", err)
+	}
+
+	if strings.TrimSpace(fixture.Name) == "" {
+		return retval, fmt.Errorf("the OFL fixture doesn't have a name")
+	}
+
+	if len(fixture.Modes) == 0 {
+		return retval, fmt.Errorf("the OFL fixture '%s' doesn't have any modes", fixture.Name)
+	}
+
+	retval.Manufacturer = fixture.Manufacturer
+	if strings.TrimSpace(retval.Manufacturer) == "" {
+		retval.Manufacturer = manufacturer
+	}
+	retval.Model = fixture.Name
+
+	//	Build a map of every channel name a mode can refer to:
+	//	the channels themselves, their fine channel aliases and switching channel aliases
+	channels := map[string]data2.FixtureChannel{}
+	for key, channel := range fixture.AvailableChannels {
+		attribute := oflAttribute(key, channel)
+		channels[key] = data2.FixtureChannel{
+			Name:      key,
+			Attribute: attribute,
+			Default:   oflDefault(channel.DefaultValue, len(channel.FineChannelAliases)),
+		}
+
+		for _, alias := range channel.FineChannelAliases {
+			channels[alias] = data2.FixtureChannel{Name: alias, Attribute: attribute, Fine: true}
+		}
+	}
+
+	for key, channel := range fixture.AvailableChannels {
+		for _, capability := range channel.allCapabilities() {
+			for alias, target := range capability.SwitchChannels {
+				if _, exists := channels[alias]; exists {
+					continue
+				}
+				if switched, found := channels[target]; found {
+					switched.Name = alias
+					channels[alias] = switched
+				} else {
+					return retval, fmt.Errorf("channel '%s' switches to unknown channel '%s'", key, target)
+				}
+			}
+		}
+	}
+
+	//	Convert each mode
+	for _, mode := range fixture.Modes {
+		newMode := data2.FixtureMode{Name: mode.Name}
+		if strings.TrimSpace(newMode.Name) == "" {
+			newMode.Name = mode.ShortName
+		}
+
+		for i, raw := range mode.Channels {
+			//	Unused channels are null
+			if string(raw) == "null" {
+				newMode.Channels = append(newMode.Channels, data2.FixtureChannel{Name: "Unused", Attribute: data2.AttributeGeneric})
+				continue
+			}
+
+			var key string
+			if err := json.Unmarshal(raw, &key); err != nil {
+				return retval, fmt.Errorf("mode '%s' channel %v isn't supported (matrix channels can't be imported)", newMode.Name, i+1)
+			}
+
+			channel, found := channels[key]
+			if !found {
+				return retval, fmt.Errorf("mode '%s' channel %v refers to unknown channel '%s'", newMode.Name, i+1, key)
+			}
+			newMode.Channels = append(newMode.Channels, channel)
+		}
+
+		retval.Modes = append(retval.Modes, newMode)
+	}
+
+	return retval, nil
+}
+
+// allCapabilities returns the capabilities of a channel, whether it has one or many
+func (c oflChannel) allCapabilities() []oflCapability {
+	if c.Capability != nil {
+		return []oflCapability{*c.Capability}
+	}
+	return c.Capabilities
+}
+
+// oflAttribute picks the fixture attribute for an OFL channel from its
+// first capability that does something
+func oflAttribute(key string, channel oflChannel) string {
+	for _, capability := range channel.allCapabilities() {
+		if capability.Type == "NoFunction" {
+			continue
+		}
+
+		switch capability.Type {
+		case "ColorIntensity":
+			if attribute, found := oflColors[strings.ToLower(capability.Color)]; found {
+				return attribute
+			}
+			return data2.AttributeGeneric
+
+		case "WheelSlot", "WheelShake", "WheelSlotRotation", "WheelRotation":
+			wheel := strings.ToLower(capability.Wheel + " " + key)
+			switch {
+			case strings.Contains(wheel, "gobo") && capability.Type == "WheelRotation":
+				return data2.AttributeGoboRotate
+			case strings.Contains(wheel, "gobo"):
+				return data2.AttributeGobo
+			case strings.Contains(wheel, "color"):
+				return data2.AttributeColorWheel
+			}
+			return data2.AttributeGeneric
+
+		case "ShutterStrobe":
+			//	A shutter channel that only opens and closes isn't a strobe
+			if capability.ShutterEffect == "Open" || capability.ShutterEffect == "Closed" {
+				if len(channel.allCapabilities()) == 1 {
+					return data2.AttributeShutter
+				}
+			}
+		}
+
+		if attribute, found := oflTypes[capability.Type]; found {
+			return attribute
+		}
+		return data2.AttributeGeneric
+	}
+
+	return data2.AttributeGeneric
+}
+
+// oflDefault converts an OFL default value (a DMX value or a percentage) to
+// a coarse channel value
+func oflDefault(value interface{}, fineChannels int) byte {
+	switch v := value.(type) {
+	case float64:
+		//	Values for channels with fine channels may be given at a higher resolution
+		level := int(v)
+		for level > 255 && fineChannels > 0 {
+			level >>= 8
+			fineChannels--
+		}
+		if level < 0 || level > 255 {
+			return 0
+		}
+		return byte(level)
+
+	case string:
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(v), "%"), 64)
+		if err != nil || percent < 0 || percent > 100 {
+			return 0
+		}
+		return byte(percent/100*255 + 0.5)
+	}
+
+	return 0
+}
@@ -0,0 +1,109 @@
+package profileimport
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	data2 "github.com/danesparza/fxdmx/internal/data"
+)
+
+const testOFLFixture = `{
+  "$schema": "https://raw.githubusercontent.com/OpenLightingProject/open-fixture-library/master/schemas/fixture.json",
+  "name": "Par 64",
+  "categories": ["Color Changer"],
+  "availableChannels": {
+    "Dimmer": {
+      "fineChannelAliases": ["Dimmer fine"],
+      "defaultValue": 65535,
+      "capability": {"type": "Intensity"}
+    },
+    "Red": {"capability": {"type": "ColorIntensity", "color": "Red"}},
+    "Green": {"capability": {"type": "ColorIntensity", "color": "Green"}},
+    "Blue": {"capability": {"type": "ColorIntensity", "color": "Blue"}},
+    "Strobe": {
+      "defaultValue": "0%",
+      "capabilities": [
+        {"dmxRange": [0, 9], "type": "ShutterStrobe", "shutterEffect": "Open"},
+        {"dmxRange": [10, 255], "type": "ShutterStrobe", "shutterEffect": "Strobe", "speedStart": "slow", "speedEnd": "fast"}
+      ]
+    },
+    "Color Macros": {
+      "capabilities": [
+        {"dmxRange": [0, 9], "type": "NoFunction"},
+        {"dmxRange": [10, 255], "type": "WheelSlot", "wheel": "Color Wheel", "slotNumber": 1}
+      ]
+    }
+  },
+  "modes": [
+    {"name": "3-channel", "shortName": "3ch", "channels": ["Red", "Green", "Blue"]},
+    {"name": "8-channel", "shortName": "8ch", "channels": ["Dimmer", "Dimmer fine", "Red", "Green", "Blue", "Strobe", "Color Macros", null]}
+  ]
+}`
+
+func TestOFL_ParseOFL_ValidFixture_Successful(t *testing.T) {
+
+	//	Arrange
+	reader := strings.NewReader(testOFLFixture)
+
+	//	Act
+	profile, err := ParseOFL(reader, "Unit test lighting")
+
+	//	Assert
+	if err != nil {
+		t.Fatalf("ParseOFL - Should parse without error, but got: %s", err)
+	}
+
+	if profile.Manufacturer != "Unit test lighting" || profile.Model != "Par 64" || len(profile.Modes) != 2 {
+		t.Fatalf("ParseOFL failed: Should get the manufacturer, model and modes but got: %+v", profile)
+	}
+
+	mode, found := profile.Mode("8-channel")
+	if !found || mode.Footprint() != 8 {
+		t.Fatalf("ParseOFL failed: Should get the 8 channel mode but got: %+v", profile.Modes)
+	}
+
+	expected := []data2.FixtureChannel{
+		{Name: "Dimmer", Attribute: data2.AttributeDimmer, Default: 255},
+		{Name: "Dimmer fine", Attribute: data2.AttributeDimmer, Fine: true},
+		{Name: "Red", Attribute: data2.AttributeRed},
+		{Name: "Green", Attribute: data2.AttributeGreen},
+		{Name: "Blue", Attribute: data2.AttributeBlue},
+		{Name: "Strobe", Attribute: data2.AttributeStrobe},
+		{Name: "Color Macros", Attribute: data2.AttributeColorWheel},
+		{Name: "Unused", Attribute: data2.AttributeGeneric},
+	}
+	for i, channel := range mode.Channels {
+		if !reflect.DeepEqual(channel, expected[i]) {
+			t.Errorf("ParseOFL failed: Channel %v should be %+v but got: %+v", i+1, expected[i], channel)
+		}
+	}
+}
+
+func TestOFL_ParseOFL_UnknownChannel_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	reader := strings.NewReader(`{"name": "Fogger", "availableChannels": {"Fog": {"capability": {"type": "Fog"}}}, "modes": [{"name": "2-channel", "channels": ["Fog", "Fan"]}]}`)
+
+	//	Act
+	_, err := ParseOFL(reader, "")
+
+	//	Assert
+	if err == nil {
+		t.Errorf("ParseOFL - Should return error for a mode with an unknown channel, but got none")
+	}
+}
+
+func TestOFL_ParseOFL_MatrixChannels_ReturnsError(t *testing.T) {
+
+	//	Arrange
+	reader := strings.NewReader(`{"name": "Bar", "availableChannels": {}, "modes": [{"name": "Pixels", "channels": [{"insert": "matrixChannels", "repeatFor": "eachPixelABC", "channelOrder": "perPixel", "templateChannels": ["Red $pixelKey"]}]}]}`)
+
+	//	Act
+	_, err := ParseOFL(reader, "")
+
+	//	Assert
+	if err == nil {
+		t.Errorf("ParseOFL - Should return error for matrix channels, but got none")
+	}
+}